@@ -28,3 +28,11 @@ func (server *HTTPServer) AdminProvisioningReloadNotifications(c *models.ReqCont
 	}
 	return Success("Notifications config reloaded")
 }
+
+func (server *HTTPServer) AdminProvisioningReloadAlertRules(c *models.ReqContext) Response {
+	err := server.ProvisioningService.ProvisionAlertRules()
+	if err != nil {
+		return Error(500, "", err)
+	}
+	return Success("Alert rules config reloaded")
+}
@@ -67,6 +67,12 @@ func AdminUpdateUserPassword(c *models.ReqContext, form dtos.AdminUpdateUserPass
 		return
 	}
 
+	// service accounts can only authenticate with service account tokens
+	if userQuery.Result.IsServiceAccount {
+		c.JsonApiErr(400, models.ErrUserIsServiceAccount.Error(), nil)
+		return
+	}
+
 	passwordHashed, err := util.EncodePassword(form.Password, userQuery.Result.Salt)
 	if err != nil {
 		c.JsonApiErr(500, "Could not encode password", err)
@@ -96,8 +102,8 @@ func AdminUpdateUserPermissions(c *models.ReqContext, form dtos.AdminUpdateUserP
 	}
 
 	if err := bus.Dispatch(&cmd); err != nil {
-		if err == models.ErrLastGrafanaAdmin {
-			c.JsonApiErr(400, models.ErrLastGrafanaAdmin.Error(), nil)
+		if err == models.ErrLastGrafanaAdmin || err == models.ErrUserIsServiceAccount {
+			c.JsonApiErr(400, err.Error(), nil)
 			return
 		}
 
@@ -36,6 +36,21 @@ func TestAdminApiEndpoint(t *testing.T) {
 		})
 	})
 
+	Convey("Given a server admin attempts to make a service account a server admin", t, func() {
+		updateCmd := dtos.AdminUpdateUserPermissionsForm{
+			IsGrafanaAdmin: true,
+		}
+
+		bus.AddHandler("test", func(cmd *models.UpdateUserPermissionsCommand) error {
+			return models.ErrUserIsServiceAccount
+		})
+
+		putAdminScenario("When calling PUT on", "/api/admin/users/2/permissions", "/api/admin/users/:id/permissions", role, updateCmd, func(sc *scenarioContext) {
+			sc.fakeReqWithParams("PUT", sc.url, map[string]string{}).exec()
+			So(sc.resp.Code, ShouldEqual, 400)
+		})
+	})
+
 	Convey("When a server admin attempts to logout himself from all devices", t, func() {
 		bus.AddHandler("test", func(cmd *models.GetUserByIdQuery) error {
 			cmd.Result = &models.User{Id: TestUserID}
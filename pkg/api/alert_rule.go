@@ -0,0 +1,120 @@
+package api
+
+import (
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+)
+
+func toAlertRuleDTO(rule *models.AlertRule) *models.AlertRuleDTO {
+	dto := &models.AlertRuleDTO{
+		Id:          rule.Id,
+		Uid:         rule.Uid,
+		AlertId:     rule.AlertId,
+		Name:        rule.Name,
+		Settings:    rule.Settings,
+		Provisioned: rule.Provisioned,
+		Version:     rule.Version,
+		Created:     rule.Created,
+		Updated:     rule.Updated,
+	}
+
+	query := models.GetAlertByIdQuery{Id: rule.AlertId}
+	if err := bus.Dispatch(&query); err == nil {
+		dto.State = query.Result.State
+		dto.NewStateDate = query.Result.NewStateDate
+	}
+
+	return dto
+}
+
+func alertRuleError(err error, message string) Response {
+	if validationErr, ok := err.(alerting.ValidationError); ok {
+		return Error(422, validationErr.Error(), nil)
+	}
+
+	switch err {
+	case models.ErrAlertRuleNotFound:
+		return Error(404, err.Error(), nil)
+	case models.ErrAlertRuleWithSameUIDExists, models.ErrAlertRuleWithSameNameExists:
+		return Error(409, err.Error(), nil)
+	case models.ErrAlertRuleProvisioned, models.ErrAlertRuleMissingName:
+		return Error(400, err.Error(), nil)
+	case models.ErrDataSourceAccessDenied:
+		return Error(403, err.Error(), nil)
+	}
+
+	return Error(500, message, err)
+}
+
+// GET /api/alert-rules
+func GetAlertRules(c *models.ReqContext) Response {
+	query := models.GetAlertRulesQuery{OrgId: c.OrgId}
+
+	if err := bus.Dispatch(&query); err != nil {
+		return Error(500, "Failed to get alert rules", err)
+	}
+
+	return JSON(200, query.Result)
+}
+
+// GET /api/alert-rules/:uid
+func GetAlertRuleByUID(c *models.ReqContext) Response {
+	query := models.GetAlertRuleByUidQuery{OrgId: c.OrgId, Uid: c.Params(":uid")}
+
+	if err := bus.Dispatch(&query); err != nil {
+		return alertRuleError(err, "Failed to get alert rule")
+	}
+
+	return JSON(200, toAlertRuleDTO(query.Result))
+}
+
+// POST /api/alert-rules
+func CreateAlertRule(c *models.ReqContext, cmd models.SaveAlertRuleCommand) Response {
+	cmd.OrgId = c.OrgId
+	cmd.User = c.SignedInUser
+
+	if cmd.Uid != "" {
+		query := models.GetAlertRuleByUidQuery{OrgId: c.OrgId, Uid: cmd.Uid}
+		if err := bus.Dispatch(&query); err == nil {
+			return alertRuleError(models.ErrAlertRuleWithSameUIDExists, "")
+		} else if err != models.ErrAlertRuleNotFound {
+			return Error(500, "Failed to create alert rule", err)
+		}
+	}
+
+	if err := bus.Dispatch(&cmd); err != nil {
+		return alertRuleError(err, "Failed to create alert rule")
+	}
+
+	return JSON(200, toAlertRuleDTO(cmd.Result))
+}
+
+// PUT /api/alert-rules/:uid
+func UpdateAlertRule(c *models.ReqContext, cmd models.SaveAlertRuleCommand) Response {
+	cmd.OrgId = c.OrgId
+	cmd.User = c.SignedInUser
+	cmd.Uid = c.Params(":uid")
+
+	query := models.GetAlertRuleByUidQuery{OrgId: c.OrgId, Uid: cmd.Uid}
+	if err := bus.Dispatch(&query); err != nil {
+		return alertRuleError(err, "Failed to update alert rule")
+	}
+
+	if err := bus.Dispatch(&cmd); err != nil {
+		return alertRuleError(err, "Failed to update alert rule")
+	}
+
+	return JSON(200, toAlertRuleDTO(cmd.Result))
+}
+
+// DELETE /api/alert-rules/:uid
+func DeleteAlertRule(c *models.ReqContext) Response {
+	cmd := models.DeleteAlertRuleCommand{OrgId: c.OrgId, Uid: c.Params(":uid")}
+
+	if err := bus.Dispatch(&cmd); err != nil {
+		return alertRuleError(err, "Failed to delete alert rule")
+	}
+
+	return Success("Alert rule deleted")
+}
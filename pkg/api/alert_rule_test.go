@@ -0,0 +1,83 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	_ "github.com/grafana/grafana/pkg/services/alerting/conditions"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertRuleApiEndpoint(t *testing.T) {
+	Convey("Given an alert rule with malformed query params", t, func() {
+		settings, err := simplejson.NewJson([]byte(`{
+			"name": "High CPU",
+			"conditions": [{
+				"type": "query",
+				"query": {"params": ["A"], "datasource": "Graphite", "model": {"target": "cpu"}},
+				"reducer": {"type": "avg"},
+				"evaluator": {"type": "gt", "params": [90]}
+			}]
+		}`))
+		So(err, ShouldBeNil)
+
+		cmd := models.SaveAlertRuleCommand{Settings: settings}
+
+		postAlertRuleScenario("When calling POST on", "/api/alert-rules", "/api/alert-rules", "POST", cmd, CreateAlertRule, func(sc *scenarioContext) {
+			sc.fakeReqWithParams("POST", sc.url, map[string]string{}).exec()
+			So(sc.resp.Code, ShouldEqual, 422)
+		})
+
+		postAlertRuleScenario("When calling PUT on", "/api/alert-rules/cpu", "/api/alert-rules/:uid", "PUT", cmd, UpdateAlertRule, func(sc *scenarioContext) {
+			bus.AddHandler("test", func(query *models.GetAlertRuleByUidQuery) error {
+				query.Result = &models.AlertRule{Id: 1, OrgId: query.OrgId, Uid: query.Uid}
+				return nil
+			})
+
+			sc.fakeReqWithParams("PUT", sc.url, map[string]string{}).exec()
+			So(sc.resp.Code, ShouldEqual, 422)
+		})
+	})
+}
+
+func postAlertRuleScenario(desc string, url string, routePattern string, method string, cmd models.SaveAlertRuleCommand, handler func(*models.ReqContext, models.SaveAlertRuleCommand) Response, fn scenarioFunc) {
+	Convey(desc+" "+url, func() {
+		defer bus.ClearBusHandlers()
+
+		bus.AddHandler("test", func(query *models.GetAlertRuleByUidQuery) error {
+			return models.ErrAlertRuleNotFound
+		})
+		bus.AddHandler("test", func(query *models.GetDataSourceByNameQuery) error {
+			query.Result = &models.DataSource{Id: 1, OrgId: query.OrgId, Name: query.Name}
+			return nil
+		})
+		// validates the rule like the alerting service does before storing it
+		bus.AddHandler("test", func(cmd *models.SaveAlertRuleCommand) error {
+			_, err := alerting.NewAlertFromAlertRule(cmd.OrgId, cmd.User, cmd.Settings)
+			return err
+		})
+
+		sc := setupScenarioContext(url)
+		sc.defaultHandler = Wrap(func(c *models.ReqContext) Response {
+			sc.context = c
+			sc.context.UserId = TestUserID
+			sc.context.OrgId = TestOrgID
+			sc.context.OrgRole = models.ROLE_EDITOR
+
+			return handler(c, cmd)
+		})
+
+		switch method {
+		case "POST":
+			sc.m.Post(routePattern, sc.defaultHandler)
+		case "PUT":
+			sc.m.Put(routePattern, sc.defaultHandler)
+		}
+
+		fn(sc)
+	})
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+func toAlertSilenceDTO(silence *models.AlertSilence) *models.AlertSilenceDTO {
+	return &models.AlertSilenceDTO{
+		Id:           silence.Id,
+		Comment:      silence.Comment,
+		AlertName:    silence.AlertName,
+		DashboardUid: silence.DashboardUid,
+		FolderUid:    silence.FolderUid,
+		Tags:         silence.Tags,
+		StartsAt:     silence.StartsAt,
+		EndsAt:       silence.EndsAt,
+		CreatedBy:    silence.CreatedBy,
+		Created:      silence.Created,
+		Updated:      silence.Updated,
+	}
+}
+
+func alertSilenceError(err error, message string) Response {
+	switch err {
+	case models.ErrAlertSilenceNotFound:
+		return Error(404, err.Error(), nil)
+	case models.ErrAlertSilenceMissingMatcher, models.ErrAlertSilenceInvalidDuration:
+		return Error(400, err.Error(), nil)
+	}
+
+	return Error(500, message, err)
+}
+
+// GET /api/alert-silences
+func GetAlertSilences(c *models.ReqContext) Response {
+	query := models.GetAlertSilencesQuery{OrgId: c.OrgId}
+	if c.QueryBool("active") {
+		query.ActiveAt = time.Now()
+	}
+
+	if err := bus.Dispatch(&query); err != nil {
+		return Error(500, "Failed to get alert silences", err)
+	}
+
+	result := make([]*models.AlertSilenceDTO, 0, len(query.Result))
+	for _, silence := range query.Result {
+		result = append(result, toAlertSilenceDTO(silence))
+	}
+
+	return JSON(200, result)
+}
+
+// GET /api/alert-silences/:silenceId
+func GetAlertSilenceByID(c *models.ReqContext) Response {
+	query := models.GetAlertSilenceByIdQuery{OrgId: c.OrgId, Id: c.ParamsInt64(":silenceId")}
+
+	if err := bus.Dispatch(&query); err != nil {
+		return alertSilenceError(err, "Failed to get alert silence")
+	}
+
+	return JSON(200, toAlertSilenceDTO(query.Result))
+}
+
+// POST /api/alert-silences
+func CreateAlertSilence(c *models.ReqContext, cmd models.CreateAlertSilenceCommand) Response {
+	cmd.OrgId = c.OrgId
+	cmd.CreatedBy = c.UserId
+
+	if err := bus.Dispatch(&cmd); err != nil {
+		return alertSilenceError(err, "Failed to create alert silence")
+	}
+
+	return JSON(200, toAlertSilenceDTO(cmd.Result))
+}
+
+// PUT /api/alert-silences/:silenceId
+func UpdateAlertSilence(c *models.ReqContext, cmd models.UpdateAlertSilenceCommand) Response {
+	cmd.OrgId = c.OrgId
+	cmd.Id = c.ParamsInt64(":silenceId")
+
+	if err := bus.Dispatch(&cmd); err != nil {
+		return alertSilenceError(err, "Failed to update alert silence")
+	}
+
+	return JSON(200, toAlertSilenceDTO(cmd.Result))
+}
+
+// DELETE /api/alert-silences/:silenceId
+func DeleteAlertSilence(c *models.ReqContext) Response {
+	cmd := models.DeleteAlertSilenceCommand{OrgId: c.OrgId, Id: c.ParamsInt64(":silenceId")}
+
+	if err := bus.Dispatch(&cmd); err != nil {
+		return alertSilenceError(err, "Failed to delete alert silence")
+	}
+
+	return Success("Alert silence deleted")
+}
@@ -118,7 +118,10 @@ func GetAlerts(c *models.ReqContext) Response {
 	}
 
 	for _, alert := range query.Result {
-		alert.Url = models.GetDashboardUrl(alert.DashboardUid, alert.DashboardSlug)
+		// standalone alert rules have no dashboard to link to
+		if alert.DashboardUid != "" {
+			alert.Url = models.GetDashboardUrl(alert.DashboardUid, alert.DashboardSlug)
+		}
 	}
 
 	return JSON(200, query.Result)
@@ -182,6 +185,52 @@ func GetAlert(c *models.ReqContext) Response {
 	return JSON(200, &query.Result)
 }
 
+// GET /api/alerts/:id/instances
+func GetAlertInstances(c *models.ReqContext) Response {
+	query := models.GetAlertInstancesQuery{
+		OrgId:   c.OrgId,
+		AlertId: c.ParamsInt64(":alertId"),
+		State:   c.QueryStrings("state"),
+	}
+
+	if err := bus.Dispatch(&query); err != nil {
+		return Error(500, "List alert instances failed", err)
+	}
+
+	result := make([]*models.AlertInstanceDTO, 0, len(query.Result))
+	for _, instance := range query.Result {
+		result = append(result, &models.AlertInstanceDTO{
+			Id:           instance.Id,
+			AlertId:      instance.AlertId,
+			Metric:       instance.Metric,
+			Labels:       instance.Labels,
+			State:        instance.State,
+			NewStateDate: instance.NewStateDate,
+			EvalData:     instance.EvalData,
+		})
+	}
+
+	return JSON(200, result)
+}
+
+// GET /api/alerts/:id/history
+func GetAlertStateHistory(c *models.ReqContext) Response {
+	query := models.GetAlertStateHistoryQuery{
+		OrgId:   c.OrgId,
+		AlertId: c.ParamsInt64(":alertId"),
+		From:    c.QueryInt64("from"),
+		To:      c.QueryInt64("to"),
+		Page:    c.QueryInt("page"),
+		PerPage: c.QueryInt("perpage"),
+	}
+
+	if err := bus.Dispatch(&query); err != nil {
+		return Error(500, "Failed to get alert state history", err)
+	}
+
+	return JSON(200, query.Result)
+}
+
 func GetAlertNotifiers(c *models.ReqContext) Response {
 	return JSON(200, alerting.GetNotifiers())
 }
@@ -351,6 +400,21 @@ func NotificationTest(c *models.ReqContext, dto dtos.NotificationTestCommand) Re
 	return Success("Test notification sent")
 }
 
+//POST /api/alert-notifications/preview
+func NotificationPreview(c *models.ReqContext, dto dtos.NotificationTestCommand) Response {
+	cmd := &alerting.NotificationPreviewCommand{
+		Name:     dto.Name,
+		Type:     dto.Type,
+		Settings: dto.Settings,
+	}
+
+	if err := bus.Dispatch(cmd); err != nil {
+		return Error(400, "Failed to render notification templates", err)
+	}
+
+	return JSON(200, cmd.Result)
+}
+
 //POST /api/alerts/:alertId/pause
 func PauseAlert(c *models.ReqContext, dto dtos.PauseAlertCommand) Response {
 	alertID := c.ParamsInt64("alertId")
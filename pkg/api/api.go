@@ -230,6 +230,18 @@ func (hs *HTTPServer) registerRoutes() {
 			keysRoute.Delete("/:id", Wrap(DeleteAPIKey))
 		}, reqOrgAdmin)
 
+		// service accounts
+		apiRoute.Group("/serviceaccounts", func(serviceAccountsRoute routing.RouteRegister) {
+			serviceAccountsRoute.Get("/", Wrap(GetServiceAccounts))
+			serviceAccountsRoute.Post("/", bind(models.CreateServiceAccountCommand{}), Wrap(CreateServiceAccount))
+			serviceAccountsRoute.Get("/:serviceAccountId", Wrap(GetServiceAccountByID))
+			serviceAccountsRoute.Patch("/:serviceAccountId", bind(models.UpdateServiceAccountCommand{}), Wrap(UpdateServiceAccount))
+			serviceAccountsRoute.Delete("/:serviceAccountId", Wrap(DeleteServiceAccount))
+			serviceAccountsRoute.Get("/:serviceAccountId/tokens", Wrap(GetServiceAccountTokens))
+			serviceAccountsRoute.Post("/:serviceAccountId/tokens", quota("api_key"), bind(models.AddServiceAccountTokenCommand{}), Wrap(hs.AddServiceAccountToken))
+			serviceAccountsRoute.Delete("/:serviceAccountId/tokens/:tokenId", Wrap(DeleteServiceAccountToken))
+		}, reqOrgAdmin)
+
 		// Preferences
 		apiRoute.Group("/preferences", func(prefRoute routing.RouteRegister) {
 			prefRoute.Post("/set-home-dash", bind(models.SavePreferencesCommand{}), Wrap(SetHomeDashboard))
@@ -346,6 +358,8 @@ func (hs *HTTPServer) registerRoutes() {
 			alertsRoute.Post("/test", bind(dtos.AlertTestCommand{}), Wrap(AlertTest))
 			alertsRoute.Post("/:alertId/pause", reqEditorRole, bind(dtos.PauseAlertCommand{}), Wrap(PauseAlert))
 			alertsRoute.Get("/:alertId", ValidateOrgAlert, Wrap(GetAlert))
+			alertsRoute.Get("/:alertId/instances", ValidateOrgAlert, Wrap(GetAlertInstances))
+			alertsRoute.Get("/:alertId/history", ValidateOrgAlert, Wrap(GetAlertStateHistory))
 			alertsRoute.Get("/", Wrap(GetAlerts))
 			alertsRoute.Get("/states-for-dashboard", Wrap(GetAlertStatesForDashboard))
 		})
@@ -355,6 +369,7 @@ func (hs *HTTPServer) registerRoutes() {
 		apiRoute.Group("/alert-notifications", func(alertNotifications routing.RouteRegister) {
 			alertNotifications.Get("/", Wrap(GetAlertNotifications))
 			alertNotifications.Post("/test", bind(dtos.NotificationTestCommand{}), Wrap(NotificationTest))
+			alertNotifications.Post("/preview", bind(dtos.NotificationTestCommand{}), Wrap(NotificationPreview))
 			alertNotifications.Post("/", bind(models.CreateAlertNotificationCommand{}), Wrap(CreateAlertNotification))
 			alertNotifications.Put("/:notificationId", bind(models.UpdateAlertNotificationCommand{}), Wrap(UpdateAlertNotification))
 			alertNotifications.Get("/:notificationId", Wrap(GetAlertNotificationByID))
@@ -364,6 +379,22 @@ func (hs *HTTPServer) registerRoutes() {
 			alertNotifications.Delete("/uid/:uid", Wrap(DeleteAlertNotificationByUID))
 		}, reqEditorRole)
 
+		apiRoute.Group("/alert-rules", func(alertRules routing.RouteRegister) {
+			alertRules.Get("/", Wrap(GetAlertRules))
+			alertRules.Get("/:uid", Wrap(GetAlertRuleByUID))
+			alertRules.Post("/", reqEditorRole, bind(models.SaveAlertRuleCommand{}), Wrap(CreateAlertRule))
+			alertRules.Put("/:uid", reqEditorRole, bind(models.SaveAlertRuleCommand{}), Wrap(UpdateAlertRule))
+			alertRules.Delete("/:uid", reqEditorRole, Wrap(DeleteAlertRule))
+		})
+
+		apiRoute.Group("/alert-silences", func(alertSilences routing.RouteRegister) {
+			alertSilences.Get("/", Wrap(GetAlertSilences))
+			alertSilences.Get("/:silenceId", Wrap(GetAlertSilenceByID))
+			alertSilences.Post("/", reqEditorRole, bind(models.CreateAlertSilenceCommand{}), Wrap(CreateAlertSilence))
+			alertSilences.Put("/:silenceId", reqEditorRole, bind(models.UpdateAlertSilenceCommand{}), Wrap(UpdateAlertSilence))
+			alertSilences.Delete("/:silenceId", reqEditorRole, Wrap(DeleteAlertSilence))
+		})
+
 		// alert notifications without requirement of user to be org editor
 		apiRoute.Group("/alert-notifications", func(orgRoute routing.RouteRegister) {
 			orgRoute.Get("/lookup", Wrap(GetAlertNotificationLookup))
@@ -406,6 +437,7 @@ func (hs *HTTPServer) registerRoutes() {
 		adminRoute.Post("/provisioning/dashboards/reload", Wrap(hs.AdminProvisioningReloadDasboards))
 		adminRoute.Post("/provisioning/datasources/reload", Wrap(hs.AdminProvisioningReloadDatasources))
 		adminRoute.Post("/provisioning/notifications/reload", Wrap(hs.AdminProvisioningReloadNotifications))
+		adminRoute.Post("/provisioning/alert_rules/reload", Wrap(hs.AdminProvisioningReloadAlertRules))
 		adminRoute.Post("/ldap/reload", Wrap(hs.ReloadLDAPCfg))
 		adminRoute.Post("/ldap/sync/:id", Wrap(hs.PostSyncUserWithLDAP))
 		adminRoute.Get("/ldap/:username", Wrap(hs.GetUserFromLDAP))
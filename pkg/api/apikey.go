@@ -1,6 +1,10 @@
 package api
 
 import (
+	"fmt"
+	"net"
+	"path"
+	"strings"
 	"time"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
@@ -24,10 +28,14 @@ func GetAPIKeys(c *models.ReqContext) Response {
 			expiration = &v
 		}
 		result[i] = &models.ApiKeyDTO{
-			Id:         t.Id,
-			Name:       t.Name,
-			Role:       t.Role,
-			Expiration: expiration,
+			Id:                   t.Id,
+			Name:                 t.Name,
+			Role:                 t.Role,
+			Expiration:           expiration,
+			LastUsedAt:           t.LastUsedAt,
+			LastUsedIp:           t.LastUsedIp,
+			AllowedCidrs:         t.AllowedCidrs,
+			AllowedRoutePrefixes: t.AllowedRoutePrefixes,
 		}
 	}
 
@@ -60,6 +68,18 @@ func (hs *HTTPServer) AddAPIKey(c *models.ReqContext, cmd models.AddApiKeyComman
 			return Error(400, "Number of seconds before expiration is greater than the global limit", nil)
 		}
 	}
+	cidrs, err := normalizeAllowedCidrs(cmd.AllowedCidrs)
+	if err != nil {
+		return Error(400, err.Error(), nil)
+	}
+	cmd.AllowedCidrs = cidrs
+
+	prefixes, err := normalizeAllowedRoutePrefixes(cmd.AllowedRoutePrefixes)
+	if err != nil {
+		return Error(400, err.Error(), nil)
+	}
+	cmd.AllowedRoutePrefixes = prefixes
+
 	cmd.OrgId = c.OrgId
 
 	newKeyInfo, err := apikeygen.New(cmd.OrgId, cmd.Name)
@@ -85,3 +105,53 @@ func (hs *HTTPServer) AddAPIKey(c *models.ReqContext, cmd models.AddApiKeyComman
 
 	return JSON(200, result)
 }
+
+// normalizeAllowedCidrs validates the CIDR ranges a key may be used from,
+// single IP addresses are turned into ranges containing only that address.
+func normalizeAllowedCidrs(cidrs []string) ([]string, error) {
+	result := make([]string, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return nil, fmt.Errorf("Invalid IP address %q", cidr)
+			}
+			if ip.To4() != nil {
+				cidr += "/32"
+			} else {
+				cidr += "/128"
+			}
+		}
+
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid CIDR range %q", cidr)
+		}
+		result = append(result, network.String())
+	}
+
+	return result, nil
+}
+
+// normalizeAllowedRoutePrefixes validates the route prefixes a key may be used for, ex /api/annotations.
+func normalizeAllowedRoutePrefixes(prefixes []string) ([]string, error) {
+	result := make([]string, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		prefix = strings.TrimSpace(prefix)
+		if prefix == "" {
+			continue
+		}
+
+		if !strings.HasPrefix(prefix, "/") {
+			return nil, fmt.Errorf("Invalid route prefix %q, route prefixes must start with /", prefix)
+		}
+		result = append(result, path.Clean(prefix))
+	}
+
+	return result, nil
+}
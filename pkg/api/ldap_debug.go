@@ -9,6 +9,7 @@ import (
 	"github.com/grafana/grafana/pkg/login"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/ldap"
+	"github.com/grafana/grafana/pkg/services/ldapsync"
 	"github.com/grafana/grafana/pkg/services/multildap"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
@@ -59,6 +60,12 @@ type LDAPServerDTO struct {
 	Error     string `json:"error"`
 }
 
+// LDAPStatusDTO is a serializer for the LDAP server statuses and the background sync report
+type LDAPStatusDTO struct {
+	Servers []*LDAPServerDTO   `json:"servers"`
+	Sync    *ldapsync.SyncInfo `json:"sync"`
+}
+
 // FetchOrgs fetches the organization(s) information by executing a single query to the database. Then, populating the DTO with the information retrieved.
 func (user *LDAPUserDTO) FetchOrgs() error {
 	orgIds := []int64{}
@@ -148,7 +155,10 @@ func (server *HTTPServer) GetLDAPStatus(c *models.ReqContext) Response {
 		serverDTOs = append(serverDTOs, s)
 	}
 
-	return JSON(http.StatusOK, serverDTOs)
+	return JSON(http.StatusOK, &LDAPStatusDTO{
+		Servers: serverDTOs,
+		Sync:    server.LDAPSyncService.SyncInfo(),
+	})
 }
 
 // PostSyncUserWithLDAP enables a single Grafana user to be synchronized against LDAP
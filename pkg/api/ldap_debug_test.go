@@ -10,6 +10,7 @@ import (
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/auth"
 	"github.com/grafana/grafana/pkg/services/ldap"
+	"github.com/grafana/grafana/pkg/services/ldapsync"
 	"github.com/grafana/grafana/pkg/services/multildap"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/stretchr/testify/assert"
@@ -325,7 +326,7 @@ func getLDAPStatusContext(t *testing.T) *scenarioContext {
 	setting.LDAPEnabled = true
 	defer func() { setting.LDAPEnabled = ldap }()
 
-	hs := &HTTPServer{Cfg: setting.NewCfg()}
+	hs := &HTTPServer{Cfg: setting.NewCfg(), LDAPSyncService: &ldapsync.LDAPSyncService{}}
 
 	sc.defaultHandler = Wrap(func(c *models.ReqContext) Response {
 		sc.context = c
@@ -362,11 +363,14 @@ func TestGetLDAPStatusApiEndpoint(t *testing.T) {
 	require.Equal(t, http.StatusOK, sc.resp.Code)
 
 	expected := `
-	[
-		{ "host": "10.0.0.3", "port": 361, "available": true, "error": "" },
-		{ "host": "10.0.0.3", "port": 362, "available": true, "error": "" },
-		{ "host": "10.0.0.5", "port": 361, "available": false, "error": "something is awfully wrong" }
-	]
+	{
+		"servers": [
+			{ "host": "10.0.0.3", "port": 361, "available": true, "error": "" },
+			{ "host": "10.0.0.3", "port": 362, "available": true, "error": "" },
+			{ "host": "10.0.0.5", "port": 361, "available": false, "error": "something is awfully wrong" }
+		],
+		"sync": { "enabled": false, "schedule": "", "nextSync": null, "prevSync": null }
+	}
 	`
 	assert.JSONEq(t, expected, sc.resp.Body.String())
 }
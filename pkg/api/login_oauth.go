@@ -181,9 +181,13 @@ func (hs *HTTPServer) OAuthLogin(ctx *models.ReqContext) {
 		Email:      userInfo.Email,
 		OrgRoles:   map[int64]models.RoleType{},
 		Groups:     userInfo.Groups,
+		Teams:      userInfo.Teams,
 	}
 
-	if userInfo.Role != "" {
+	if userInfo.OrgRoles != nil {
+		// org roles from the org mappings take precedence over the role of the user
+		extUser.OrgRoles = userInfo.OrgRoles
+	} else if userInfo.Role != "" {
 		rt := models.RoleType(userInfo.Role)
 		if rt.IsValid() {
 			var orgID int64
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"errors"
 	"sort"
 
 	"github.com/grafana/grafana/pkg/models"
@@ -16,6 +17,19 @@ import (
 	"github.com/grafana/grafana/pkg/util"
 )
 
+// metricRequestError returns the response of a failed data source request, requests
+// rejected by the query limits of the data source are reported as such to the client.
+func metricRequestError(err error) Response {
+	switch {
+	case errors.Is(err, tsdb.ErrTooManyQueries):
+		return Error(429, err.Error(), err)
+	case errors.Is(err, tsdb.ErrQueryTimeout):
+		return Error(504, err.Error(), err)
+	}
+
+	return Error(500, "Metric request error", err)
+}
+
 // QueryMetricsV2 returns query metrics
 // POST /api/ds/query   DataSource query w/ expressions
 func (hs *HTTPServer) QueryMetricsV2(c *models.ReqContext, reqDto dtos.MetricRequest) Response {
@@ -70,7 +84,7 @@ func (hs *HTTPServer) QueryMetricsV2(c *models.ReqContext, reqDto dtos.MetricReq
 	if !expr {
 		resp, err = tsdb.HandleRequest(c.Req.Context(), ds, request)
 		if err != nil {
-			return Error(500, "Metric request error", err)
+			return metricRequestError(err)
 		}
 	} else {
 		if !setting.IsExpressionsEnabled() {
@@ -135,7 +149,7 @@ func (hs *HTTPServer) QueryMetrics(c *models.ReqContext, reqDto dtos.MetricReque
 
 	resp, err := tsdb.HandleRequest(c.Req.Context(), ds, request)
 	if err != nil {
-		return Error(500, "Metric request error", err)
+		return metricRequestError(err)
 	}
 
 	statusCode := 200
@@ -2,6 +2,7 @@ package pluginproxy
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -19,10 +20,12 @@ import (
 	"github.com/grafana/grafana/pkg/api/datasource"
 	"github.com/grafana/grafana/pkg/bus"
 	glog "github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/login/social"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/util/proxyutil"
 )
@@ -101,12 +104,37 @@ func (proxy *DataSourceProxy) HandleRequest() {
 		return
 	}
 
+	release, err := tsdb.AcquireQuerySlot(proxy.ctx.Req.Context(), proxy.ds)
+	if err != nil {
+		if errors.Is(err, tsdb.ErrTooManyQueries) {
+			proxy.ctx.JsonApiErr(429, err.Error(), nil)
+		} else {
+			proxy.ctx.JsonApiErr(400, "Request canceled", err)
+		}
+		return
+	}
+	defer release()
+
+	queryCtx, cancel := tsdb.WithQueryTimeout(proxy.ctx.Req.Context(), proxy.ds)
+	defer cancel()
+	proxy.ctx.Req.Request = proxy.ctx.Req.WithContext(queryCtx)
+
 	proxyErrorLogger := logger.New("userId", proxy.ctx.UserId, "orgId", proxy.ctx.OrgId, "uname", proxy.ctx.Login, "path", proxy.ctx.Req.URL.Path, "remote_addr", proxy.ctx.RemoteAddr(), "referer", proxy.ctx.Req.Referer())
 
 	reverseProxy := &httputil.ReverseProxy{
 		Director:      proxy.getDirector(),
 		FlushInterval: time.Millisecond * 200,
 		ErrorLog:      log.New(&logWrapper{logger: proxyErrorLogger}, "", 0),
+		ErrorHandler: func(w http.ResponseWriter, req *http.Request, err error) {
+			if queryCtx.Err() == context.DeadlineExceeded {
+				metrics.MDataSourceQueriesRejected.WithLabelValues(proxy.ds.Type, tsdb.QueryRejectedQueryTimeout).Inc()
+				proxy.ctx.JsonApiErr(504, tsdb.ErrQueryTimeout.Error(), err)
+				return
+			}
+
+			proxyErrorLogger.Error("Data proxy error", "error", err)
+			w.WriteHeader(http.StatusBadGateway)
+		},
 	}
 
 	transport, err := proxy.ds.GetHttpTransport()
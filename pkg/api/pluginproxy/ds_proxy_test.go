@@ -2,6 +2,7 @@ package pluginproxy
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -21,9 +22,11 @@ import (
 
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/login/social"
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb"
 	"github.com/grafana/grafana/pkg/util"
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -549,6 +552,58 @@ func TestDSRouteRule(t *testing.T) {
 					"important_cookie=important_value")
 			})
 		})
+
+		Convey("HandleRequest() with query limits", func() {
+			backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(100 * time.Millisecond)
+				w.WriteHeader(200)
+			}))
+			defer backend.Close()
+
+			plugin := &plugins.DataSourcePlugin{}
+			ds := &models.DataSource{Id: 1000, Url: backend.URL, Type: models.DS_GRAPHITE, JsonData: simplejson.NewFromAny(map[string]interface{}{
+				"maxConcurrentQueries": 1,
+				"queryQueueTimeout":    "0s",
+			})}
+
+			recorder := httptest.NewRecorder()
+			responseWriter := macaron.NewResponseWriter("GET", recorder)
+			ctx := &models.ReqContext{
+				SignedInUser: &models.SignedInUser{},
+				Context: &macaron.Context{
+					Req: macaron.Request{
+						Request: httptest.NewRequest("GET", "/render", nil),
+					},
+					Resp:   responseWriter,
+					Render: &macaron.TplRender{ResponseWriter: responseWriter, Opt: &macaron.RenderOptions{}},
+				},
+				Logger: log.New("test"),
+			}
+
+			Convey("When all query slots are taken should respond with too many requests", func() {
+				release, err := tsdb.AcquireQuerySlot(context.Background(), ds)
+				So(err, ShouldBeNil)
+				defer release()
+
+				proxy, err := NewDataSourceProxy(ds, plugin, ctx, "/render", &setting.Cfg{})
+				So(err, ShouldBeNil)
+
+				proxy.HandleRequest()
+
+				So(recorder.Code, ShouldEqual, 429)
+			})
+
+			Convey("When the query timeout is exceeded should respond with gateway timeout", func() {
+				ds.JsonData.Set("queryTimeout", "20ms")
+
+				proxy, err := NewDataSourceProxy(ds, plugin, ctx, "/render", &setting.Cfg{})
+				So(err, ShouldBeNil)
+
+				proxy.HandleRequest()
+
+				So(recorder.Code, ShouldEqual, 504)
+			})
+		})
 	})
 }
 
@@ -599,7 +654,10 @@ func (r *CloseNotifierResponseRecorder) CloseNotify() <-chan bool {
 }
 
 func (r *CloseNotifierResponseRecorder) Close() {
-	close(r.closeChan)
+	// the reverse proxy only uses CloseNotify for requests without a cancelable context
+	if r.closeChan != nil {
+		close(r.closeChan)
+	}
 }
 
 // getDatasourceProxiedRequest is a helper for easier setup of tests based on global config and ReqContext.
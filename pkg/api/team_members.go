@@ -96,7 +96,8 @@ func (hs *HTTPServer) RemoveTeamMember(c *models.ReqContext) Response {
 		protectLastAdmin = true
 	}
 
-	if err := hs.Bus.Dispatch(&models.RemoveTeamMemberCommand{OrgId: orgId, TeamId: teamId, UserId: userId, ProtectLastAdmin: protectLastAdmin}); err != nil {
+	cmd := models.RemoveTeamMemberCommand{OrgId: orgId, TeamId: teamId, UserId: userId, ProtectLastAdmin: protectLastAdmin, ProtectExternal: true}
+	if err := hs.Bus.Dispatch(&cmd); err != nil {
 		if err == models.ErrTeamNotFound {
 			return Error(404, "Team not found", nil)
 		}
@@ -105,6 +106,10 @@ func (hs *HTTPServer) RemoveTeamMember(c *models.ReqContext) Response {
 			return Error(404, "Team member not found", nil)
 		}
 
+		if err == models.ErrTeamMemberIsExternal {
+			return Error(400, "Team member is managed by an external auth provider and can't be removed", nil)
+		}
+
 		return Error(500, "Failed to remove Member from Team", err)
 	}
 	return Success("Team Member removed")
@@ -70,6 +70,13 @@ type UserCreated struct {
 	Email     string    `json:"email"`
 }
 
+// ApiKeyUsed is published when a request is authenticated with an API key or a service account token.
+type ApiKeyUsed struct {
+	Timestamp time.Time `json:"timestamp"`
+	Id        int64     `json:"id"`
+	ClientIp  string    `json:"client_ip"`
+}
+
 type SignUpStarted struct {
 	Timestamp time.Time `json:"timestamp"`
 	Email     string    `json:"email"`
@@ -84,6 +84,27 @@ var (
 	// MAlertingNotificationSent is a metric counter for how many alert notifications that failed
 	MAlertingNotificationFailed *prometheus.CounterVec
 
+	// MAlertingNotificationSilenced is a metric counter for how many alert notifications that were silenced
+	MAlertingNotificationSilenced *prometheus.CounterVec
+
+	// MAlertingNotificationGrouped is a metric counter for how many alert notifications were sent as part of a digest
+	MAlertingNotificationGrouped *prometheus.CounterVec
+
+	// MAlertingNotificationDropped is a metric counter for how many alert notifications were dropped by rate limits
+	MAlertingNotificationDropped *prometheus.CounterVec
+
+	// MDataSourceQueryCacheHits is a metric counter for data source queries answered from the query cache
+	MDataSourceQueryCacheHits *prometheus.CounterVec
+
+	// MDataSourceQueryCacheMisses is a metric counter for cacheable data source queries not found in the query cache
+	MDataSourceQueryCacheMisses *prometheus.CounterVec
+
+	// MDataSourceQueryQueueDepth is a metric gauge for data source queries waiting for the concurrent queries limit
+	MDataSourceQueryQueueDepth *prometheus.GaugeVec
+
+	// MDataSourceQueriesRejected is a metric counter for data source queries rejected by the queue or query timeout
+	MDataSourceQueriesRejected *prometheus.CounterVec
+
 	// MAwsCloudWatchGetMetricStatistics is a metric counter for getting metric statistics from aws
 	MAwsCloudWatchGetMetricStatistics prometheus.Counter
 
@@ -324,6 +345,48 @@ func init() {
 		Namespace: ExporterName,
 	}, []string{"type"})
 
+	MAlertingNotificationSilenced = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:      "alerting_notification_silenced_total",
+		Help:      "counter for how many alert notifications have been silenced",
+		Namespace: ExporterName,
+	}, []string{"type"})
+
+	MAlertingNotificationGrouped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:      "alerting_notification_grouped_total",
+		Help:      "counter for how many alert notifications have been grouped into digests",
+		Namespace: ExporterName,
+	}, []string{"type"})
+
+	MAlertingNotificationDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:      "alerting_notification_dropped_total",
+		Help:      "counter for how many alert notifications have been dropped by rate limits",
+		Namespace: ExporterName,
+	}, []string{"type"})
+
+	MDataSourceQueryCacheHits = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:      "datasource_query_cache_hits_total",
+		Help:      "counter for data source queries answered from the query cache",
+		Namespace: ExporterName,
+	}, []string{"datasource_type"})
+
+	MDataSourceQueryCacheMisses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:      "datasource_query_cache_misses_total",
+		Help:      "counter for cacheable data source queries not found in the query cache",
+		Namespace: ExporterName,
+	}, []string{"datasource_type"})
+
+	MDataSourceQueryQueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name:      "datasource_query_queue_depth",
+		Help:      "number of data source queries waiting for the concurrent queries limit",
+		Namespace: ExporterName,
+	}, []string{"datasource_type"})
+
+	MDataSourceQueriesRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:      "datasource_queries_rejected_total",
+		Help:      "counter for data source queries rejected by the queue or query timeout",
+		Namespace: ExporterName,
+	}, []string{"datasource_type", "reason"})
+
 	MAwsCloudWatchGetMetricStatistics = newCounterStartingAtZero(prometheus.CounterOpts{
 		Name:      "aws_cloudwatch_get_metric_statistics_total",
 		Help:      "counter for getting metric statistics from aws",
@@ -527,6 +590,13 @@ func initMetricVars() {
 		MAlertingResultState,
 		MAlertingNotificationSent,
 		MAlertingNotificationFailed,
+		MAlertingNotificationSilenced,
+		MAlertingNotificationGrouped,
+		MAlertingNotificationDropped,
+		MDataSourceQueryCacheHits,
+		MDataSourceQueryCacheMisses,
+		MDataSourceQueryQueueDepth,
+		MDataSourceQueriesRejected,
 		MAwsCloudWatchGetMetricStatistics,
 		MAwsCloudWatchListMetrics,
 		MAwsCloudWatchGetMetricData,
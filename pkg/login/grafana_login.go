@@ -29,6 +29,11 @@ var loginUsingGrafanaDB = func(query *models.LoginUserQuery) error {
 
 	user := userQuery.Result
 
+	// service accounts authenticate with their tokens only
+	if user.IsServiceAccount {
+		return models.ErrUserNotFound
+	}
+
 	if user.IsDisabled {
 		return ErrUserDisabled
 	}
@@ -59,14 +59,18 @@ func (s *SocialAzureAD) UserInfo(_ *http.Client, token *oauth2.Token) (*BasicUse
 		return nil, ErrMissingGroupMembership
 	}
 
-	return &BasicUserInfo{
+	userInfo := &BasicUserInfo{
 		Id:     claims.ID,
 		Name:   claims.Name,
 		Email:  email,
 		Login:  email,
 		Role:   string(role),
 		Groups: groups,
-	}, nil
+	}
+
+	s.applyGroupMappings(userInfo)
+
+	return userInfo, nil
 }
 
 func (s *SocialAzureAD) IsGroupMember(groups []string) bool {
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana/pkg/models"
 	"golang.org/x/oauth2"
 	"gopkg.in/square/go-jose.v2"
 	"gopkg.in/square/go-jose.v2/jwt"
@@ -219,6 +220,40 @@ func TestSocialAzureAD_UserInfo(t *testing.T) {
 				Groups:  []string{"foo"},
 			},
 		},
+		{
+			name: "Org roles and teams from group mappings",
+			fields: fields{
+				SocialBase: &SocialBase{
+					orgMappings: []*orgMapping{
+						{group: "foo", orgId: 2, role: models.ROLE_EDITOR},
+						{group: "bar", orgId: 3, role: models.ROLE_ADMIN},
+					},
+					teamMappings: []*teamMapping{
+						{group: "foo", orgId: 2, teamName: "Foo"},
+						{group: "bar", orgId: 3, teamName: "Bar"},
+					},
+				},
+			},
+			claims: &azureClaims{
+				Email:             "me@example.com",
+				PreferredUsername: "",
+				Roles:             []string{},
+				Groups:            []string{"foo"},
+				Name:              "My Name",
+				ID:                "1234",
+			},
+			want: &BasicUserInfo{
+				Id:       "1234",
+				Name:     "My Name",
+				Email:    "me@example.com",
+				Login:    "me@example.com",
+				Company:  "",
+				Role:     "Viewer",
+				Groups:   []string{"foo"},
+				OrgRoles: map[int64]models.RoleType{2: models.ROLE_EDITOR},
+				Teams:    []*models.ExternalTeam{{OrgId: 2, Name: "Foo"}},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
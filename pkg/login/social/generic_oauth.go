@@ -22,6 +22,7 @@ type SocialGenericOAuth struct {
 	emailAttributeName   string
 	emailAttributePath   string
 	roleAttributePath    string
+	groupsAttributePath  string
 	teamIds              []int
 }
 
@@ -121,6 +122,8 @@ func (s *SocialGenericOAuth) UserInfo(client *http.Client, token *oauth2.Token)
 		return nil, errors.New("User not a member of one of the required organizations")
 	}
 
+	s.applyGroupMappings(userInfo)
+
 	s.log.Debug("User info result", "result", userInfo)
 	return userInfo, nil
 }
@@ -143,6 +146,14 @@ func (s *SocialGenericOAuth) fillUserInfo(userInfo *BasicUserInfo, data *UserInf
 	if userInfo.Login == "" {
 		userInfo.Login = s.extractLogin(data)
 	}
+	if len(userInfo.Groups) == 0 {
+		groups, err := s.extractGroups(data)
+		if err != nil {
+			s.log.Error("Failed to extract groups", "error", err)
+		} else {
+			userInfo.Groups = groups
+		}
+	}
 }
 
 func (s *SocialGenericOAuth) extractToken(data *UserInfoJson, token *oauth2.Token) bool {
@@ -239,6 +250,14 @@ func (s *SocialGenericOAuth) extractRole(data *UserInfoJson) (string, error) {
 	return role, nil
 }
 
+func (s *SocialGenericOAuth) extractGroups(data *UserInfoJson) ([]string, error) {
+	if s.groupsAttributePath == "" {
+		return []string{}, nil
+	}
+
+	return s.searchJSONForStringArrayAttr(s.groupsAttributePath, data.rawJSON)
+}
+
 func (s *SocialGenericOAuth) extractLogin(data *UserInfoJson) string {
 	if data.Login != "" {
 		return data.Login
@@ -166,6 +166,50 @@ func TestSearchJSONForRole(t *testing.T) {
 	})
 }
 
+func TestSearchJSONForGroups(t *testing.T) {
+	t.Run("Given a generic OAuth provider", func(t *testing.T) {
+		provider := SocialGenericOAuth{
+			SocialBase: &SocialBase{
+				log: log.New("generic_oauth_test"),
+			},
+		}
+
+		tests := []struct {
+			Name                 string
+			UserInfoJSONResponse []byte
+			GroupsAttributePath  string
+			ExpectedResult       []string
+		}{
+			{
+				Name:                 "Given a user info JSON response with a list of groups",
+				UserInfoJSONResponse: []byte(`{"attributes": {"groups": ["admins", "editors"]}}`),
+				GroupsAttributePath:  "attributes.groups",
+				ExpectedResult:       []string{"admins", "editors"},
+			},
+			{
+				Name:                 "Given a user info JSON response with a list of group objects",
+				UserInfoJSONResponse: []byte(`{"groups": [{"name": "admins"}, {"name": "editors"}]}`),
+				GroupsAttributePath:  "groups[*].name",
+				ExpectedResult:       []string{"admins", "editors"},
+			},
+			{
+				Name:                 "Given a user info JSON response where the path isn't a list",
+				UserInfoJSONResponse: []byte(`{"groups": "admins"}`),
+				GroupsAttributePath:  "groups",
+				ExpectedResult:       []string{},
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.Name, func(t *testing.T) {
+				actualResult, err := provider.searchJSONForStringArrayAttr(test.GroupsAttributePath, test.UserInfoJSONResponse)
+				require.NoError(t, err, "Testing case %q", test.Name)
+				require.Equal(t, test.ExpectedResult, actualResult)
+			})
+		}
+	})
+}
+
 func TestUserInfoSearchesForEmailAndRole(t *testing.T) {
 	t.Run("Given a generic OAuth provider", func(t *testing.T) {
 		provider := SocialGenericOAuth{
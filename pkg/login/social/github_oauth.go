@@ -230,6 +230,8 @@ func (s *SocialGithub) UserInfo(client *http.Client, token *oauth2.Token) (*Basi
 		}
 	}
 
+	s.applyGroupMappings(userInfo)
+
 	return userInfo, nil
 }
 
@@ -127,5 +127,7 @@ func (s *SocialGitlab) UserInfo(client *http.Client, token *oauth2.Token) (*Basi
 		return nil, ErrMissingGroupMembership
 	}
 
+	s.applyGroupMappings(userInfo)
+
 	return userInfo, nil
 }
@@ -0,0 +1,131 @@
+package social
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// orgMapping maps the members of a group to a role in an organization
+type orgMapping struct {
+	group string
+	orgId int64
+	role  models.RoleType
+}
+
+// teamMapping maps the members of a group to a team in an organization
+type teamMapping struct {
+	group    string
+	orgId    int64
+	teamName string
+}
+
+// splitMappings splits a comma separated list of mappings. Mappings are
+// not split on spaces, as group and team names can contain spaces.
+func splitMappings(str string) []string {
+	mappings := []string{}
+	for _, mapping := range strings.Split(str, ",") {
+		if mapping = strings.TrimSpace(mapping); mapping != "" {
+			mappings = append(mappings, mapping)
+		}
+	}
+	return mappings
+}
+
+// splitMapping splits a `<group>:<org id>:<value>` mapping. The org id and value
+// are split off from the end, so that the group can contain colons itself.
+func splitMapping(mapping string) (string, int64, string, bool) {
+	valueIdx := strings.LastIndex(mapping, ":")
+	if valueIdx <= 0 {
+		return "", 0, "", false
+	}
+
+	orgIdx := strings.LastIndex(mapping[:valueIdx], ":")
+	if orgIdx <= 0 {
+		return "", 0, "", false
+	}
+
+	orgId, err := strconv.ParseInt(mapping[orgIdx+1:valueIdx], 10, 64)
+	if err != nil || orgId <= 0 {
+		return "", 0, "", false
+	}
+
+	return mapping[:orgIdx], orgId, mapping[valueIdx+1:], true
+}
+
+func parseOrgMappings(logger log.Logger, mappings []string) []*orgMapping {
+	result := []*orgMapping{}
+	for _, mapping := range mappings {
+		group, orgId, role, ok := splitMapping(mapping)
+		if !ok || !models.RoleType(role).IsValid() {
+			logger.Error("Invalid org mapping, expected <group>:<org id>:<role>", "mapping", mapping)
+			continue
+		}
+
+		result = append(result, &orgMapping{group: group, orgId: orgId, role: models.RoleType(role)})
+	}
+	return result
+}
+
+func parseTeamMappings(logger log.Logger, mappings []string) []*teamMapping {
+	result := []*teamMapping{}
+	for _, mapping := range mappings {
+		group, orgId, teamName, ok := splitMapping(mapping)
+		if !ok || teamName == "" {
+			logger.Error("Invalid team mapping, expected <group>:<org id>:<team name>", "mapping", mapping)
+			continue
+		}
+
+		result = append(result, &teamMapping{group: group, orgId: orgId, teamName: teamName})
+	}
+	return result
+}
+
+func isMemberOf(groups []string, group string) bool {
+	if group == "*" {
+		return true
+	}
+
+	for _, member := range groups {
+		if strings.EqualFold(member, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyGroupMappings sets the org roles and teams of the user from the groups it's a member of.
+// The first org mapping that matches is used for each organization. Org roles are left unset
+// when no org mapping matches, and teams are left unset when no team mappings are configured,
+// so that the user's org roles and teams aren't synced. A nil SocialBase has no mappings.
+func (s *SocialBase) applyGroupMappings(userInfo *BasicUserInfo) {
+	if s == nil {
+		return
+	}
+
+	for _, mapping := range s.orgMappings {
+		if !isMemberOf(userInfo.Groups, mapping.group) {
+			continue
+		}
+
+		if userInfo.OrgRoles == nil {
+			userInfo.OrgRoles = map[int64]models.RoleType{}
+		}
+		if _, exists := userInfo.OrgRoles[mapping.orgId]; !exists {
+			userInfo.OrgRoles[mapping.orgId] = mapping.role
+		}
+	}
+
+	if len(s.teamMappings) == 0 {
+		return
+	}
+
+	userInfo.Teams = []*models.ExternalTeam{}
+	for _, mapping := range s.teamMappings {
+		if isMemberOf(userInfo.Groups, mapping.group) {
+			userInfo.Teams = append(userInfo.Teams, &models.ExternalTeam{OrgId: mapping.orgId, Name: mapping.teamName})
+		}
+	}
+}
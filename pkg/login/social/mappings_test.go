@@ -0,0 +1,55 @@
+package social
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupMappings(t *testing.T) {
+	logger := log.New("mappings_test")
+
+	t.Run("Should parse org and team mappings", func(t *testing.T) {
+		orgMappings := parseOrgMappings(logger, splitMappings("admins:1:Admin, @grafana/team:a:2:Editor,invalid:1:Owner, missing:Viewer"))
+		require.Len(t, orgMappings, 2)
+		assert.Equal(t, &orgMapping{group: "admins", orgId: 1, role: models.ROLE_ADMIN}, orgMappings[0])
+		assert.Equal(t, &orgMapping{group: "@grafana/team:a", orgId: 2, role: models.ROLE_EDITOR}, orgMappings[1])
+
+		teamMappings := parseTeamMappings(logger, splitMappings("admins:1:Site Reliability,editors:0:Editors"))
+		require.Len(t, teamMappings, 1)
+		assert.Equal(t, &teamMapping{group: "admins", orgId: 1, teamName: "Site Reliability"}, teamMappings[0])
+	})
+
+	t.Run("Should apply the first matching org mapping and all matching team mappings", func(t *testing.T) {
+		s := &SocialBase{
+			orgMappings: []*orgMapping{
+				{group: "admins", orgId: 1, role: models.ROLE_ADMIN},
+				{group: "*", orgId: 1, role: models.ROLE_VIEWER},
+				{group: "*", orgId: 2, role: models.ROLE_VIEWER},
+			},
+			teamMappings: []*teamMapping{
+				{group: "Admins", orgId: 1, teamName: "Admins"},
+				{group: "editors", orgId: 1, teamName: "Editors"},
+			},
+		}
+
+		userInfo := &BasicUserInfo{Groups: []string{"admins"}}
+		s.applyGroupMappings(userInfo)
+
+		assert.Equal(t, map[int64]models.RoleType{1: models.ROLE_ADMIN, 2: models.ROLE_VIEWER}, userInfo.OrgRoles)
+		assert.Equal(t, []*models.ExternalTeam{{OrgId: 1, Name: "Admins"}}, userInfo.Teams)
+	})
+
+	t.Run("Should not set org roles and teams without mappings", func(t *testing.T) {
+		s := &SocialBase{}
+
+		userInfo := &BasicUserInfo{Groups: []string{"admins"}}
+		s.applyGroupMappings(userInfo)
+
+		assert.Nil(t, userInfo.OrgRoles)
+		assert.Nil(t, userInfo.Teams)
+	})
+}
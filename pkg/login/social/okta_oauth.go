@@ -87,14 +87,18 @@ func (s *SocialOkta) UserInfo(client *http.Client, token *oauth2.Token) (*BasicU
 		return nil, ErrMissingGroupMembership
 	}
 
-	return &BasicUserInfo{
+	userInfo := &BasicUserInfo{
 		Id:     claims.ID,
 		Name:   claims.Name,
 		Email:  email,
 		Login:  email,
 		Role:   role,
 		Groups: groups,
-	}, nil
+	}
+
+	s.applyGroupMappings(userInfo)
+
+	return userInfo, nil
 }
 
 func (s *SocialOkta) extractAPI(data *OktaUserInfoJson, client *http.Client) error {
@@ -9,18 +9,21 @@ import (
 	"golang.org/x/oauth2"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
 
 type BasicUserInfo struct {
-	Id      string
-	Name    string
-	Email   string
-	Login   string
-	Company string
-	Role    string
-	Groups  []string
+	Id       string
+	Name     string
+	Email    string
+	Login    string
+	Company  string
+	Role     string
+	Groups   []string
+	OrgRoles map[int64]models.RoleType // set from the org mappings of the groups, nil if none matched
+	Teams    []*models.ExternalTeam    // set from the team mappings of the groups, nil if no team mappings are configured
 }
 
 type SocialConnector interface {
@@ -40,6 +43,8 @@ type SocialBase struct {
 	log            log.Logger
 	allowSignup    bool
 	allowedDomains []string
+	orgMappings    []*orgMapping
+	teamMappings   []*teamMapping
 }
 
 type Error struct {
@@ -68,6 +73,8 @@ func newSocialBase(name string, config *oauth2.Config, info *setting.OAuthInfo)
 		log:            logger,
 		allowSignup:    info.AllowSignup,
 		allowedDomains: info.AllowedDomains,
+		orgMappings:    parseOrgMappings(logger, info.OrgMapping),
+		teamMappings:   parseTeamMappings(logger, info.TeamMapping),
 	}
 }
 
@@ -78,24 +85,27 @@ func NewOAuthService() {
 	for _, name := range allOauthes {
 		sec := setting.Raw.Section("auth." + name)
 		info := &setting.OAuthInfo{
-			ClientId:           sec.Key("client_id").String(),
-			ClientSecret:       sec.Key("client_secret").String(),
-			Scopes:             util.SplitString(sec.Key("scopes").String()),
-			AuthUrl:            sec.Key("auth_url").String(),
-			TokenUrl:           sec.Key("token_url").String(),
-			ApiUrl:             sec.Key("api_url").String(),
-			Enabled:            sec.Key("enabled").MustBool(),
-			EmailAttributeName: sec.Key("email_attribute_name").String(),
-			EmailAttributePath: sec.Key("email_attribute_path").String(),
-			RoleAttributePath:  sec.Key("role_attribute_path").String(),
-			AllowedDomains:     util.SplitString(sec.Key("allowed_domains").String()),
-			HostedDomain:       sec.Key("hosted_domain").String(),
-			AllowSignup:        sec.Key("allow_sign_up").MustBool(),
-			Name:               sec.Key("name").MustString(name),
-			TlsClientCert:      sec.Key("tls_client_cert").String(),
-			TlsClientKey:       sec.Key("tls_client_key").String(),
-			TlsClientCa:        sec.Key("tls_client_ca").String(),
-			TlsSkipVerify:      sec.Key("tls_skip_verify_insecure").MustBool(),
+			ClientId:            sec.Key("client_id").String(),
+			ClientSecret:        sec.Key("client_secret").String(),
+			Scopes:              util.SplitString(sec.Key("scopes").String()),
+			AuthUrl:             sec.Key("auth_url").String(),
+			TokenUrl:            sec.Key("token_url").String(),
+			ApiUrl:              sec.Key("api_url").String(),
+			Enabled:             sec.Key("enabled").MustBool(),
+			EmailAttributeName:  sec.Key("email_attribute_name").String(),
+			EmailAttributePath:  sec.Key("email_attribute_path").String(),
+			RoleAttributePath:   sec.Key("role_attribute_path").String(),
+			GroupsAttributePath: sec.Key("groups_attribute_path").String(),
+			OrgMapping:          splitMappings(sec.Key("org_mapping").String()),
+			TeamMapping:         splitMappings(sec.Key("team_mapping").String()),
+			AllowedDomains:      util.SplitString(sec.Key("allowed_domains").String()),
+			HostedDomain:        sec.Key("hosted_domain").String(),
+			AllowSignup:         sec.Key("allow_sign_up").MustBool(),
+			Name:                sec.Key("name").MustString(name),
+			TlsClientCert:       sec.Key("tls_client_cert").String(),
+			TlsClientKey:        sec.Key("tls_client_key").String(),
+			TlsClientCa:         sec.Key("tls_client_ca").String(),
+			TlsSkipVerify:       sec.Key("tls_skip_verify_insecure").MustBool(),
 		}
 
 		if !info.Enabled {
@@ -174,6 +184,7 @@ func NewOAuthService() {
 				emailAttributeName:   info.EmailAttributeName,
 				emailAttributePath:   info.EmailAttributePath,
 				roleAttributePath:    info.RoleAttributePath,
+				groupsAttributePath:  info.GroupsAttributePath,
 				teamIds:              sec.Key("team_ids").Ints(","),
 				allowedOrganizations: util.SplitString(sec.Key("allowed_organizations").String()),
 			}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/url"
 	"strconv"
 	"strings"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/components/apikeygen"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/remotecache"
 	"github.com/grafana/grafana/pkg/models"
@@ -146,6 +148,30 @@ func initContextWithApiKey(ctx *models.ReqContext) bool {
 		return true
 	}
 
+	clientIp, err := getApiKeyClientIP(ctx)
+	if err != nil {
+		ctx.Logger.Debug("Failed to parse client IP address", "remote_addr", ctx.Req.RemoteAddr, "error", err)
+	}
+
+	if !apikey.IsAllowedFrom(clientIp) {
+		ctx.JsonApiErr(403, "API key not allowed from this IP address", nil)
+		return true
+	}
+
+	if !apikey.IsAllowedRoute(strings.TrimPrefix(ctx.Req.URL.Path, setting.AppSubUrl)) {
+		ctx.JsonApiErr(403, "API key not allowed for this route", nil)
+		return true
+	}
+
+	// last used is written asynchronously by the api key usage service
+	if err := bus.Publish(&events.ApiKeyUsed{Timestamp: getTime(), Id: apikey.Id, ClientIp: clientIp}); err != nil {
+		ctx.Logger.Error("Failed to publish API key usage", "error", err)
+	}
+
+	if apikey.ServiceAccountId != nil {
+		return initContextWithServiceAccount(ctx, apikey)
+	}
+
 	ctx.IsSignedIn = true
 	ctx.SignedInUser = &models.SignedInUser{}
 	ctx.OrgRole = apikey.Role
@@ -154,6 +180,77 @@ func initContextWithApiKey(ctx *models.ReqContext) bool {
 	return true
 }
 
+// getApiKeyClientIP returns the address of the client of an API key request. The forwarding
+// headers are set by the client, so they are only used for requests from trusted proxies.
+func getApiKeyClientIP(ctx *models.ReqContext) (string, error) {
+	remoteIp, err := util.ParseIPAddress(ctx.Req.RemoteAddr)
+	if err != nil || !isTrustedProxy(remoteIp) {
+		return remoteIp, err
+	}
+
+	if realIp := strings.TrimSpace(ctx.Req.Header.Get("X-Real-IP")); realIp != "" {
+		return parseForwardedIP(realIp)
+	}
+
+	forwardedFor := ctx.Req.Header.Get("X-Forwarded-For")
+	if forwardedFor == "" {
+		return remoteIp, nil
+	}
+
+	// every proxy appends the address it received the request from, the
+	// client is the last address that wasn't added by a trusted proxy
+	forwardedIps := strings.Split(forwardedFor, ",")
+	for i := len(forwardedIps) - 1; i >= 0; i-- {
+		ip, err := parseForwardedIP(strings.TrimSpace(forwardedIps[i]))
+		if err != nil || i == 0 || !isTrustedProxy(ip) {
+			return ip, err
+		}
+	}
+	return remoteIp, nil
+}
+
+func parseForwardedIP(addr string) (string, error) {
+	if ip := net.ParseIP(addr); ip != nil {
+		return ip.String(), nil
+	}
+	return util.ParseIPAddress(addr)
+}
+
+func isTrustedProxy(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+
+	for _, proxy := range setting.ApiKeyTrustedProxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// initContextWithServiceAccount signs in the service account of a token, requests
+// use the role and the team and folder permissions of the service account.
+func initContextWithServiceAccount(ctx *models.ReqContext, apikey *models.ApiKey) bool {
+	query := models.GetSignedInUserQuery{UserId: *apikey.ServiceAccountId, OrgId: apikey.OrgId}
+	if err := bus.Dispatch(&query); err != nil {
+		ctx.JsonApiErr(401, errStringInvalidAPIKey, err)
+		return true
+	}
+
+	user := query.Result
+	if !user.IsServiceAccount || user.OrgId != apikey.OrgId {
+		ctx.JsonApiErr(401, errStringInvalidAPIKey, nil)
+		return true
+	}
+
+	ctx.IsSignedIn = true
+	ctx.SignedInUser = user
+	ctx.ApiKeyId = apikey.Id
+	return true
+}
+
 func initContextWithBasicAuth(ctx *models.ReqContext, orgId int64) bool {
 	if !setting.BasicAuthEnabled {
 		return false
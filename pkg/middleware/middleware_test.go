@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
@@ -17,6 +18,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/remotecache"
 	authproxy "github.com/grafana/grafana/pkg/middleware/auth_proxy"
@@ -166,6 +168,170 @@ func TestMiddlewareContext(t *testing.T) {
 			})
 		})
 
+		middlewareScenario(t, "Valid service account token", func(sc *scenarioContext) {
+			keyhash, err := util.EncodePassword("v5nAwpMafFP6znaS4urhdWDLS5511M42", "asd")
+			So(err, ShouldBeNil)
+
+			serviceAccountId := int64(42)
+			bus.AddHandler("test", func(query *models.GetApiKeyByNameQuery) error {
+				query.Result = &models.ApiKey{Id: 7, OrgId: 12, Role: models.ROLE_VIEWER, Key: keyhash, ServiceAccountId: &serviceAccountId}
+				return nil
+			})
+
+			bus.AddHandler("test", func(query *models.GetSignedInUserQuery) error {
+				query.Result = &models.SignedInUser{OrgId: 12, UserId: serviceAccountId, OrgRole: models.ROLE_EDITOR, IsServiceAccount: true, Teams: []int64{3}}
+				return nil
+			})
+
+			var usedEvent *events.ApiKeyUsed
+			bus.AddEventListener(func(evt *events.ApiKeyUsed) error {
+				usedEvent = evt
+				return nil
+			})
+
+			sc.fakeReq("GET", "/").withValidApiKey().exec()
+
+			Convey("Should sign in the service account", func() {
+				So(sc.resp.Code, ShouldEqual, 200)
+				So(sc.context.IsSignedIn, ShouldBeTrue)
+				So(sc.context.UserId, ShouldEqual, serviceAccountId)
+				So(sc.context.IsServiceAccount, ShouldBeTrue)
+				So(sc.context.ApiKeyId, ShouldEqual, 7)
+				So(sc.context.OrgRole, ShouldEqual, models.ROLE_EDITOR)
+				So(sc.context.Teams, ShouldResemble, []int64{3})
+			})
+
+			Convey("Should publish the usage of the token", func() {
+				So(usedEvent, ShouldNotBeNil)
+				So(usedEvent.Id, ShouldEqual, 7)
+			})
+		})
+
+		middlewareScenario(t, "Valid service account token, but the service account is not in the org", func(sc *scenarioContext) {
+			keyhash, err := util.EncodePassword("v5nAwpMafFP6znaS4urhdWDLS5511M42", "asd")
+			So(err, ShouldBeNil)
+
+			serviceAccountId := int64(42)
+			bus.AddHandler("test", func(query *models.GetApiKeyByNameQuery) error {
+				query.Result = &models.ApiKey{OrgId: 12, Role: models.ROLE_VIEWER, Key: keyhash, ServiceAccountId: &serviceAccountId}
+				return nil
+			})
+
+			bus.AddHandler("test", func(query *models.GetSignedInUserQuery) error {
+				query.Result = &models.SignedInUser{OrgId: -1, UserId: serviceAccountId, IsServiceAccount: true}
+				return nil
+			})
+
+			sc.fakeReq("GET", "/").withValidApiKey().exec()
+
+			Convey("Should return 401", func() {
+				So(sc.resp.Code, ShouldEqual, 401)
+				So(sc.respJson["message"], ShouldEqual, errStringInvalidAPIKey)
+			})
+		})
+
+		middlewareScenario(t, "Valid api key restricted to CIDR ranges", func(sc *scenarioContext) {
+			keyhash, err := util.EncodePassword("v5nAwpMafFP6znaS4urhdWDLS5511M42", "asd")
+			So(err, ShouldBeNil)
+
+			bus.AddHandler("test", func(query *models.GetApiKeyByNameQuery) error {
+				query.Result = &models.ApiKey{Id: 7, OrgId: 12, Role: models.ROLE_EDITOR, Key: keyhash, AllowedCidrs: []string{"10.0.0.0/8"}}
+				return nil
+			})
+
+			var usedEvent *events.ApiKeyUsed
+			bus.AddEventListener(func(evt *events.ApiKeyUsed) error {
+				usedEvent = evt
+				return nil
+			})
+
+			Convey("Should allow requests from the allowed ranges", func() {
+				sc.fakeReq("GET", "/").withValidApiKey()
+				sc.req.RemoteAddr = "10.1.2.3:51234"
+				sc.exec()
+
+				So(sc.resp.Code, ShouldEqual, 200)
+				So(usedEvent, ShouldNotBeNil)
+				So(usedEvent.ClientIp, ShouldEqual, "10.1.2.3")
+			})
+
+			Convey("Should return 403 for requests from other addresses", func() {
+				sc.fakeReq("GET", "/").withValidApiKey()
+				sc.req.RemoteAddr = "192.168.1.1:51234"
+				sc.exec()
+
+				So(sc.resp.Code, ShouldEqual, 403)
+				So(sc.respJson["message"], ShouldEqual, "API key not allowed from this IP address")
+				So(usedEvent, ShouldBeNil)
+			})
+
+			Convey("Should ignore spoofed X-Real-IP headers", func() {
+				sc.fakeReq("GET", "/").withValidApiKey()
+				sc.req.RemoteAddr = "192.168.1.1:51234"
+				sc.req.Header.Set("X-Real-IP", "10.0.0.1")
+				sc.exec()
+
+				So(sc.resp.Code, ShouldEqual, 403)
+				So(usedEvent, ShouldBeNil)
+			})
+
+			Convey("Should ignore spoofed X-Forwarded-For headers", func() {
+				sc.fakeReq("GET", "/").withValidApiKey()
+				sc.req.RemoteAddr = "192.168.1.1:51234"
+				sc.req.Header.Set("X-Forwarded-For", "10.0.0.1")
+				sc.exec()
+
+				So(sc.resp.Code, ShouldEqual, 403)
+				So(usedEvent, ShouldBeNil)
+			})
+
+			Convey("With a trusted proxy", func() {
+				_, proxy, err := net.ParseCIDR("192.168.1.0/24")
+				So(err, ShouldBeNil)
+				setting.ApiKeyTrustedProxies = []*net.IPNet{proxy}
+				Reset(func() {
+					setting.ApiKeyTrustedProxies = nil
+				})
+
+				Convey("Should use the client address forwarded by the proxy", func() {
+					sc.fakeReq("GET", "/").withValidApiKey()
+					sc.req.RemoteAddr = "192.168.1.1:51234"
+					sc.req.Header.Set("X-Forwarded-For", "10.1.2.3, 192.168.1.2")
+					sc.exec()
+
+					So(sc.resp.Code, ShouldEqual, 200)
+					So(usedEvent, ShouldNotBeNil)
+					So(usedEvent.ClientIp, ShouldEqual, "10.1.2.3")
+				})
+
+				Convey("Should ignore addresses the client prepended to X-Forwarded-For", func() {
+					sc.fakeReq("GET", "/").withValidApiKey()
+					sc.req.RemoteAddr = "192.168.1.1:51234"
+					sc.req.Header.Set("X-Forwarded-For", "10.0.0.1, 172.16.0.1")
+					sc.exec()
+
+					So(sc.resp.Code, ShouldEqual, 403)
+				})
+			})
+		})
+
+		middlewareScenario(t, "Valid api key restricted to route prefixes", func(sc *scenarioContext) {
+			keyhash, err := util.EncodePassword("v5nAwpMafFP6znaS4urhdWDLS5511M42", "asd")
+			So(err, ShouldBeNil)
+
+			bus.AddHandler("test", func(query *models.GetApiKeyByNameQuery) error {
+				query.Result = &models.ApiKey{OrgId: 12, Role: models.ROLE_EDITOR, Key: keyhash, AllowedRoutePrefixes: []string{"/api/annotations"}}
+				return nil
+			})
+
+			sc.fakeReq("GET", "/").withValidApiKey().exec()
+
+			Convey("Should return 403 for other routes", func() {
+				So(sc.resp.Code, ShouldEqual, 403)
+				So(sc.respJson["message"], ShouldEqual, "API key not allowed for this route")
+			})
+		})
+
 		middlewareScenario(t, "Valid api key, but does not match db hash", func(sc *scenarioContext) {
 			keyhash := "Something_not_matching"
 
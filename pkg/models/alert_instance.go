@@ -0,0 +1,95 @@
+package models
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+var (
+	ErrAlertInstanceStateVersionConflict = errors.New("alert instance state update version conflict")
+)
+
+// AlertInstance is the state of a single series (label set)
+// of a multi-dimensional alert rule.
+type AlertInstance struct {
+	Id           int64
+	OrgId        int64
+	AlertId      int64
+	LabelsHash   string
+	Metric       string
+	Labels       map[string]string
+	State        AlertStateType
+	EvalData     *simplejson.Json
+	NewStateDate time.Time
+	StateChanges int64
+
+	Created time.Time
+	Updated time.Time
+}
+
+// GetAlertInstanceLabelsHash returns a stable identifier for a
+// series based on its metric name and labels.
+func GetAlertInstanceLabelsHash(metric string, labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(metric)
+	for _, k := range keys {
+		b.WriteString("\xff")
+		b.WriteString(k)
+		b.WriteString("=")
+		b.WriteString(labels[k])
+	}
+
+	hash := sha1.Sum([]byte(b.String()))
+	return hex.EncodeToString(hash[:])
+}
+
+type AlertInstanceDTO struct {
+	Id           int64             `json:"id"`
+	AlertId      int64             `json:"alertId"`
+	Metric       string            `json:"metric"`
+	Labels       map[string]string `json:"labels"`
+	State        AlertStateType    `json:"state"`
+	NewStateDate time.Time         `json:"newStateDate"`
+	EvalData     *simplejson.Json  `json:"evalData"`
+}
+
+// SetAlertInstanceStateCommand creates or updates the state of an alert instance.
+// StateChanges is the number of state changes the caller expects the instance
+// to have, which makes sure that only one server records a transition.
+type SetAlertInstanceStateCommand struct {
+	OrgId        int64
+	AlertId      int64
+	Metric       string
+	Labels       map[string]string
+	State        AlertStateType
+	EvalData     *simplejson.Json
+	StateChanges int64
+
+	Result *AlertInstance
+}
+
+type GetAlertInstancesQuery struct {
+	OrgId   int64
+	AlertId int64
+	State   []string
+
+	Result []*AlertInstance
+}
+
+type DeleteAlertInstanceCommand struct {
+	OrgId      int64
+	AlertId    int64
+	LabelsHash string
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+var (
+	ErrAlertRuleNotFound                = errors.New("Alert rule not found")
+	ErrAlertRuleWithSameUIDExists       = errors.New("An alert rule with the same uid already exists")
+	ErrAlertRuleWithSameNameExists      = errors.New("An alert rule with the same name already exists")
+	ErrAlertRuleFailedGenerateUniqueUid = errors.New("Failed to generate unique alert rule uid")
+	ErrAlertRuleProvisioned             = errors.New("Cannot change a provisioned alert rule")
+	ErrAlertRuleMissingName             = errors.New("Alert rule name is required")
+)
+
+// AlertRule is an alert rule defined outside of dashboards.
+//
+// Settings holds the rule definition, using the same format as the alert of
+// a graph panel, ex frequency, conditions and notifications. Each condition
+// query holds its own query model and data source name since there is no panel
+// to read them from. The scheduler evaluates the alert with id AlertId,
+// derived from the definition when the rule is saved.
+type AlertRule struct {
+	Id          int64
+	OrgId       int64
+	Uid         string
+	AlertId     int64
+	Name        string
+	Settings    *simplejson.Json
+	Provisioned bool
+	Version     int
+
+	Created   time.Time
+	Updated   time.Time
+	CreatedBy int64
+	UpdatedBy int64
+}
+
+type AlertRuleDTO struct {
+	Id           int64            `json:"id"`
+	Uid          string           `json:"uid"`
+	AlertId      int64            `json:"alertId"`
+	Name         string           `json:"name"`
+	Settings     *simplejson.Json `json:"rule"`
+	Provisioned  bool             `json:"provisioned"`
+	Version      int              `json:"version"`
+	State        AlertStateType   `json:"state"`
+	NewStateDate time.Time        `json:"newStateDate"`
+	Created      time.Time        `json:"created"`
+	Updated      time.Time        `json:"updated"`
+}
+
+//
+// COMMANDS
+//
+
+// SaveAlertRuleCommand validates and saves a standalone alert rule.
+// A new rule is created when no rule with the uid exists.
+type SaveAlertRuleCommand struct {
+	Uid      string           `json:"uid"`
+	Settings *simplejson.Json `json:"rule" binding:"Required"`
+
+	// Overwrite allows changing provisioned rules
+	Overwrite   bool          `json:"-"`
+	Provisioned bool          `json:"-"`
+	OrgId       int64         `json:"-"`
+	User        *SignedInUser `json:"-"`
+
+	Result *AlertRule
+}
+
+// StoreAlertRuleCommand stores a validated alert rule together
+// with the alert evaluated by the scheduler.
+type StoreAlertRuleCommand struct {
+	Uid         string
+	OrgId       int64
+	UserId      int64
+	Settings    *simplejson.Json
+	Overwrite   bool
+	Provisioned bool
+	Alert       *Alert
+
+	Result *AlertRule
+}
+
+type DeleteAlertRuleCommand struct {
+	OrgId     int64
+	Uid       string
+	Overwrite bool
+}
+
+//
+// QUERIES
+//
+
+type GetAlertRuleByUidQuery struct {
+	OrgId int64
+	Uid   string
+
+	Result *AlertRule
+}
+
+type GetAlertRulesQuery struct {
+	OrgId int64
+
+	Result []*AlertRuleDTO
+}
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrAlertSilenceNotFound        = errors.New("Alert silence not found")
+	ErrAlertSilenceMissingMatcher  = errors.New("Alert silence needs at least one matcher")
+	ErrAlertSilenceInvalidDuration = errors.New("Alert silence must end after it starts")
+)
+
+// AlertSilence suppresses alert notifications for matching alert rules
+// between StartsAt and EndsAt while evaluation continues as usual.
+// All matchers that are set must match for a rule to be silenced.
+type AlertSilence struct {
+	Id           int64
+	OrgId        int64
+	Comment      string
+	AlertName    string
+	DashboardUid string
+	FolderUid    string
+	Tags         map[string]string
+	StartsAt     time.Time
+	EndsAt       time.Time
+	CreatedBy    int64
+
+	Created time.Time
+	Updated time.Time
+}
+
+// IsActive returns true if the silence applies at the given time.
+func (s *AlertSilence) IsActive(t time.Time) bool {
+	return !t.Before(s.StartsAt) && t.Before(s.EndsAt)
+}
+
+// MatchesTags returns true if all tags of the silence
+// are present on the alert rule with the same value.
+func (s *AlertSilence) MatchesTags(tags []*Tag) bool {
+	for key, value := range s.Tags {
+		found := false
+		for _, tag := range tags {
+			if tag.Key == key && tag.Value == value {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+type AlertSilenceDTO struct {
+	Id           int64             `json:"id"`
+	Comment      string            `json:"comment"`
+	AlertName    string            `json:"alertName"`
+	DashboardUid string            `json:"dashboardUid"`
+	FolderUid    string            `json:"folderUid"`
+	Tags         map[string]string `json:"tags"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	CreatedBy    int64             `json:"createdBy"`
+	Created      time.Time         `json:"created"`
+	Updated      time.Time         `json:"updated"`
+}
+
+//
+// COMMANDS
+//
+
+type CreateAlertSilenceCommand struct {
+	Comment      string            `json:"comment"`
+	AlertName    string            `json:"alertName"`
+	DashboardUid string            `json:"dashboardUid"`
+	FolderUid    string            `json:"folderUid"`
+	Tags         map[string]string `json:"tags"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+
+	OrgId     int64 `json:"-"`
+	CreatedBy int64 `json:"-"`
+	Result    *AlertSilence
+}
+
+type UpdateAlertSilenceCommand struct {
+	Id           int64             `json:"-"`
+	Comment      string            `json:"comment"`
+	AlertName    string            `json:"alertName"`
+	DashboardUid string            `json:"dashboardUid"`
+	FolderUid    string            `json:"folderUid"`
+	Tags         map[string]string `json:"tags"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+
+	OrgId  int64 `json:"-"`
+	Result *AlertSilence
+}
+
+type DeleteAlertSilenceCommand struct {
+	Id    int64
+	OrgId int64
+}
+
+type DeleteExpiredAlertSilencesCommand struct {
+	OlderThan   time.Time
+	DeletedRows int64
+}
+
+//
+// QUERIES
+//
+
+type GetAlertSilenceByIdQuery struct {
+	Id    int64
+	OrgId int64
+
+	Result *AlertSilence
+}
+
+// GetAlertSilencesQuery returns the silences of an organization.
+// When ActiveAt is set only silences active at that time are returned.
+type GetAlertSilencesQuery struct {
+	OrgId    int64
+	ActiveAt time.Time
+
+	Result []*AlertSilence
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// AlertStateHistory is a state transition of an alert rule, or of a single
+// series of a multi-dimensional alert rule when Metric is set. It records
+// the evaluation that caused the transition and the outcome of the
+// notifications sent for it.
+type AlertStateHistory struct {
+	Id             int64
+	OrgId          int64
+	AlertId        int64
+	Metric         string
+	Labels         map[string]string
+	PrevState      AlertStateType
+	NewState       AlertStateType
+	EvalMatches    *simplejson.Json
+	ConditionEvals string
+	Error          string
+	Notifications  *simplejson.Json
+	Created        time.Time
+}
+
+type AlertStateHistoryDTO struct {
+	Id             int64             `json:"id"`
+	AlertId        int64             `json:"alertId"`
+	Metric         string            `json:"metric,omitempty"`
+	Labels         map[string]string `json:"labels,omitempty"`
+	PrevState      AlertStateType    `json:"prevState"`
+	NewState       AlertStateType    `json:"newState"`
+	EvalMatches    *simplejson.Json  `json:"evalMatches"`
+	ConditionEvals string            `json:"conditionEvals"`
+	Error          string            `json:"error"`
+	Notifications  *simplejson.Json  `json:"notifications"`
+	Time           int64             `json:"time"`
+}
+
+type AlertStateHistorySearchResult struct {
+	TotalCount int64                   `json:"totalCount"`
+	History    []*AlertStateHistoryDTO `json:"history"`
+	Page       int                     `json:"page"`
+	PerPage    int                     `json:"perPage"`
+}
+
+//
+// COMMANDS
+//
+
+type AddAlertStateHistoryCommand struct {
+	OrgId          int64
+	AlertId        int64
+	Metric         string
+	Labels         map[string]string
+	PrevState      AlertStateType
+	NewState       AlertStateType
+	EvalMatches    *simplejson.Json
+	ConditionEvals string
+	Error          string
+	Notifications  *simplejson.Json
+
+	Result *AlertStateHistory
+}
+
+type DeleteOldAlertStateHistoryCommand struct {
+	OlderThan   time.Time
+	DeletedRows int64
+}
+
+//
+// QUERIES
+//
+
+// GetAlertStateHistoryQuery returns the state transitions of an alert,
+// newest first. From and To are epoch milliseconds and are ignored when zero.
+type GetAlertStateHistoryQuery struct {
+	OrgId   int64
+	AlertId int64
+	From    int64
+	To      int64
+	Page    int
+	PerPage int
+
+	Result *AlertStateHistorySearchResult
+}
@@ -2,6 +2,9 @@ package models
 
 import (
 	"errors"
+	"net"
+	"path"
+	"strings"
 	"time"
 )
 
@@ -18,6 +21,52 @@ type ApiKey struct {
 	Created time.Time
 	Updated time.Time
 	Expires *int64
+
+	ServiceAccountId *int64
+	LastUsedAt       *time.Time
+	LastUsedIp       string
+
+	AllowedCidrs         []string
+	AllowedRoutePrefixes []string
+}
+
+// IsAllowedFrom returns true if the key has no CIDR restrictions
+// or the client IP is in one of the allowed CIDR ranges.
+func (k *ApiKey) IsAllowedFrom(clientIp string) bool {
+	if len(k.AllowedCidrs) == 0 {
+		return true
+	}
+
+	ip := net.ParseIP(clientIp)
+	if ip == nil {
+		return false
+	}
+
+	for _, cidr := range k.AllowedCidrs {
+		if _, network, err := net.ParseCIDR(cidr); err == nil && network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsAllowedRoute returns true if the key has no route restrictions
+// or the request path is below one of the allowed route prefixes.
+func (k *ApiKey) IsAllowedRoute(requestPath string) bool {
+	if len(k.AllowedRoutePrefixes) == 0 {
+		return true
+	}
+
+	requestPath = path.Clean("/" + requestPath)
+	for _, prefix := range k.AllowedRoutePrefixes {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if prefix == "" || requestPath == prefix || strings.HasPrefix(requestPath, prefix+"/") {
+			return true
+		}
+	}
+
+	return false
 }
 
 // ---------------------
@@ -29,6 +78,9 @@ type AddApiKeyCommand struct {
 	Key           string   `json:"-"`
 	SecondsToLive int64    `json:"secondsToLive"`
 
+	AllowedCidrs         []string `json:"allowedCidrs"`
+	AllowedRoutePrefixes []string `json:"allowedRoutePrefixes"`
+
 	Result *ApiKey `json:"-"`
 }
 
@@ -45,6 +97,25 @@ type DeleteApiKeyCommand struct {
 	OrgId int64 `json:"-"`
 }
 
+// UpdateApiKeysLastUsedCommand records the last use of several keys at once,
+// usages are collected in memory so that keys are not updated on every request.
+type UpdateApiKeysLastUsedCommand struct {
+	Usages []*ApiKeyUsage
+}
+
+type ApiKeyUsage struct {
+	Id         int64
+	LastUsedAt time.Time
+	LastUsedIp string
+}
+
+// DeleteUnusedApiKeysCommand deletes keys that were not used since the given time,
+// keys that were never used are deleted if they were created before it.
+type DeleteUnusedApiKeysCommand struct {
+	UnusedSince time.Time
+	DeletedRows int64
+}
+
 // ----------------------
 // QUERIES
 
@@ -69,8 +140,12 @@ type GetApiKeyByIdQuery struct {
 // DTO & Projections
 
 type ApiKeyDTO struct {
-	Id         int64      `json:"id"`
-	Name       string     `json:"name"`
-	Role       RoleType   `json:"role"`
-	Expiration *time.Time `json:"expiration,omitempty"`
+	Id                   int64      `json:"id"`
+	Name                 string     `json:"name"`
+	Role                 RoleType   `json:"role"`
+	Expiration           *time.Time `json:"expiration,omitempty"`
+	LastUsedAt           *time.Time `json:"lastUsedAt,omitempty"`
+	LastUsedIp           string     `json:"lastUsedIp,omitempty"`
+	AllowedCidrs         []string   `json:"allowedCidrs,omitempty"`
+	AllowedRoutePrefixes []string   `json:"allowedRoutePrefixes,omitempty"`
 }
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestApiKeyRestrictions(t *testing.T) {
+	Convey("Testing API key restrictions", t, func() {
+		Convey("Keys without restrictions are allowed everywhere", func() {
+			key := &ApiKey{}
+			So(key.IsAllowedFrom("192.168.1.1"), ShouldBeTrue)
+			So(key.IsAllowedFrom(""), ShouldBeTrue)
+			So(key.IsAllowedRoute("/api/dashboards/db"), ShouldBeTrue)
+		})
+
+		Convey("Keys are allowed from IPs in the allowed CIDR ranges only", func() {
+			key := &ApiKey{AllowedCidrs: []string{"10.0.0.0/8", "2001:db8::/32"}}
+			So(key.IsAllowedFrom("10.1.2.3"), ShouldBeTrue)
+			So(key.IsAllowedFrom("2001:db8::1"), ShouldBeTrue)
+			So(key.IsAllowedFrom("192.168.1.1"), ShouldBeFalse)
+			So(key.IsAllowedFrom(""), ShouldBeFalse)
+		})
+
+		Convey("Keys are allowed for routes below the allowed route prefixes only", func() {
+			key := &ApiKey{AllowedRoutePrefixes: []string{"/api/annotations"}}
+			So(key.IsAllowedRoute("/api/annotations"), ShouldBeTrue)
+			So(key.IsAllowedRoute("/api/annotations/1"), ShouldBeTrue)
+			So(key.IsAllowedRoute("/api/annotationsfoo"), ShouldBeFalse)
+			So(key.IsAllowedRoute("/api/annotations/../dashboards/db"), ShouldBeFalse)
+			So(key.IsAllowedRoute("/api/dashboards/db"), ShouldBeFalse)
+		})
+	})
+}
@@ -20,6 +20,7 @@ const (
 	DS_POSTGRES      = "postgres"
 	DS_MYSQL         = "mysql"
 	DS_MSSQL         = "mssql"
+	DS_CLICKHOUSE    = "clickhouse"
 	DS_ACCESS_DIRECT = "direct"
 	DS_ACCESS_PROXY  = "proxy"
 	DS_STACKDRIVER   = "stackdriver"
@@ -97,6 +98,7 @@ var knownDatasourcePlugins = map[string]bool{
 	DS_POSTGRES:                              true,
 	DS_MYSQL:                                 true,
 	DS_MSSQL:                                 true,
+	DS_CLICKHOUSE:                            true,
 	DS_STACKDRIVER:                           true,
 	DS_AZURE_MONITOR:                         true,
 	DS_LOKI:                                  true,
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Typed errors
+var (
+	ErrServiceAccountNotFound  = errors.New("Service account not found")
+	ErrServiceAccountNameTaken = errors.New("Service account name is taken")
+	ErrUserIsServiceAccount    = errors.New("Not allowed for service accounts")
+)
+
+// ServiceAccountLoginPrefix is prepended to the login of the user backing a service account.
+const ServiceAccountLoginPrefix = "sa-"
+
+// ---------------------
+// COMMANDS
+
+type CreateServiceAccountCommand struct {
+	Name  string   `json:"name" binding:"Required"`
+	Role  RoleType `json:"role" binding:"Required"`
+	OrgId int64    `json:"-"`
+
+	Result *ServiceAccountDTO `json:"-"`
+}
+
+type UpdateServiceAccountCommand struct {
+	Name string   `json:"name"`
+	Role RoleType `json:"role"`
+
+	Id    int64 `json:"-"`
+	OrgId int64 `json:"-"`
+}
+
+type DeleteServiceAccountCommand struct {
+	Id    int64
+	OrgId int64
+}
+
+type AddServiceAccountTokenCommand struct {
+	Name             string `json:"name" binding:"Required"`
+	SecondsToLive    int64  `json:"secondsToLive"`
+	OrgId            int64  `json:"-"`
+	ServiceAccountId int64  `json:"-"`
+	Key              string `json:"-"`
+
+	AllowedCidrs         []string `json:"allowedCidrs"`
+	AllowedRoutePrefixes []string `json:"allowedRoutePrefixes"`
+
+	Result *ApiKey `json:"-"`
+}
+
+type DeleteServiceAccountTokenCommand struct {
+	Id               int64
+	OrgId            int64
+	ServiceAccountId int64
+}
+
+// ----------------------
+// QUERIES
+
+type GetServiceAccountsQuery struct {
+	OrgId  int64
+	Result []*ServiceAccountDTO
+}
+
+type GetServiceAccountByIdQuery struct {
+	Id     int64
+	OrgId  int64
+	Result *ServiceAccountDTO
+}
+
+type GetServiceAccountTokensQuery struct {
+	OrgId            int64
+	ServiceAccountId int64
+	Result           []*ApiKey
+}
+
+// ------------------------
+// DTO & Projections
+
+type ServiceAccountDTO struct {
+	Id     int64    `json:"id"`
+	OrgId  int64    `json:"orgId"`
+	Name   string   `json:"name"`
+	Login  string   `json:"login"`
+	Role   RoleType `json:"role"`
+	Tokens int64    `json:"tokens"`
+	Teams  []string `json:"teams" xorm:"-"`
+}
+
+type ServiceAccountTokenDTO struct {
+	Id                   int64      `json:"id"`
+	Name                 string     `json:"name"`
+	Created              time.Time  `json:"created"`
+	Expiration           *time.Time `json:"expiration,omitempty"`
+	LastUsedAt           *time.Time `json:"lastUsedAt,omitempty"`
+	LastUsedIp           string     `json:"lastUsedIp,omitempty"`
+	AllowedCidrs         []string   `json:"allowedCidrs,omitempty"`
+	AllowedRoutePrefixes []string   `json:"allowedRoutePrefixes,omitempty"`
+}
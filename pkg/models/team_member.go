@@ -8,6 +8,7 @@ import (
 // Typed errors
 var (
 	ErrTeamMemberAlreadyAdded = errors.New("User is already added to this team")
+	ErrTeamMemberIsExternal   = errors.New("Team member is managed by an external auth provider")
 )
 
 // TeamMember model
@@ -47,6 +48,7 @@ type RemoveTeamMemberCommand struct {
 	UserId           int64
 	TeamId           int64
 	ProtectLastAdmin bool `json:"-"`
+	ProtectExternal  bool `json:"-"`
 }
 
 // ----------------------
@@ -67,7 +69,7 @@ type TeamMemberDTO struct {
 	OrgId      int64          `json:"orgId"`
 	TeamId     int64          `json:"teamId"`
 	UserId     int64          `json:"userId"`
-	External   bool           `json:"-"`
+	External   bool           `json:"external"`
 	AuthModule string         `json:"auth_module"`
 	Email      string         `json:"email"`
 	Name       string         `json:"name"`
@@ -34,6 +34,14 @@ type ExternalUserInfo struct {
 	OrgRoles       map[int64]RoleType
 	IsGrafanaAdmin *bool // This is a pointer to know if we should sync this or not (nil = ignore sync)
 	IsDisabled     bool
+	Teams          []*ExternalTeam // nil = ignore sync, empty = remove the user from all externally managed teams
+}
+
+// ExternalTeam references a team, by organization and name, the external auth provider
+// says the user is a member of
+type ExternalTeam struct {
+	OrgId int64
+	Name  string
 }
 
 // ---------------------
@@ -37,6 +37,7 @@ type UserTokenService interface {
 	TryRotateToken(ctx context.Context, token *UserToken, clientIP, userAgent string) (bool, error)
 	RevokeToken(ctx context.Context, token *UserToken) error
 	RevokeAllUserTokens(ctx context.Context, userId int64) error
+	BatchRevokeAllUserTokens(ctx context.Context, userIds []int64) error
 	ActiveTokenCount(ctx context.Context) (int64, error)
 	GetUserToken(ctx context.Context, userId, userTokenId int64) (*UserToken, error)
 	GetUserTokens(ctx context.Context, userId int64) ([]*UserToken, error)
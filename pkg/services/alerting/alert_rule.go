@@ -0,0 +1,138 @@
+package alerting
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+func init() {
+	bus.AddHandler("alerting", saveAlertRule)
+}
+
+func saveAlertRule(cmd *models.SaveAlertRuleCommand) error {
+	alert, err := NewAlertFromAlertRule(cmd.OrgId, cmd.User, cmd.Settings)
+	if err != nil {
+		return err
+	}
+
+	storeCmd := &models.StoreAlertRuleCommand{
+		Uid:         cmd.Uid,
+		OrgId:       cmd.OrgId,
+		Settings:    cmd.Settings,
+		Overwrite:   cmd.Overwrite,
+		Provisioned: cmd.Provisioned,
+		Alert:       alert,
+	}
+
+	if cmd.User != nil {
+		storeCmd.UserId = cmd.User.UserId
+	}
+
+	if err := bus.Dispatch(storeCmd); err != nil {
+		return err
+	}
+
+	cmd.Result = storeCmd.Result
+	return nil
+}
+
+// NewAlertFromAlertRule returns the alert evaluated by the scheduler for the
+// definition of a standalone alert rule. The data source of each condition query
+// is resolved by name, or by id if the name is missing, like panel queries are
+// for dashboard alerts. A nil user skips the data source permission check.
+func NewAlertFromAlertRule(orgID int64, user *models.SignedInUser, settings *simplejson.Json) (*models.Alert, error) {
+	if settings == nil {
+		return nil, ValidationError{Reason: "Alert rule definition is missing"}
+	}
+
+	name := settings.Get("name").MustString()
+	if name == "" {
+		return nil, ValidationError{Reason: "Alert rule name is missing"}
+	}
+
+	frequency, err := getTimeDurationStringToSeconds(settings.Get("frequency").MustString("1m"))
+	if err != nil {
+		return nil, ValidationError{Reason: err.Error()}
+	}
+
+	var forValue time.Duration
+	if rawFor := settings.Get("for").MustString(); rawFor != "" {
+		forValue, err = time.ParseDuration(rawFor)
+		if err != nil {
+			return nil, ValidationError{Reason: "Could not parse for"}
+		}
+	}
+
+	alertSettings, err := copyJSON(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, condition := range alertSettings.Get("conditions").MustArray() {
+		for _, jsonQuery := range getConditionQueries(simplejson.NewFromAny(condition)) {
+			if _, ok := jsonQuery.CheckGet("model"); !ok {
+				return nil, ValidationError{Reason: fmt.Sprintf("Alert rule %s has a condition query without model", name)}
+			}
+
+			datasource, err := lookupAlertRuleDatasource(orgID, jsonQuery)
+			if err != nil {
+				return nil, ValidationError{Reason: fmt.Sprintf("Data source used by alert rule not found, alertName=%v", name), Err: err}
+			}
+
+			if user != nil {
+				dsFilterQuery := models.DatasourcesPermissionFilterQuery{
+					User:        user,
+					Datasources: []*models.DataSource{datasource},
+				}
+
+				if err := bus.Dispatch(&dsFilterQuery); err != nil {
+					if err != bus.ErrHandlerNotFound {
+						return nil, err
+					}
+				} else if len(dsFilterQuery.Result) == 0 {
+					return nil, models.ErrDataSourceAccessDenied
+				}
+			}
+
+			jsonQuery.Set("datasourceId", datasource.Id)
+			jsonQuery.Del("datasource")
+		}
+	}
+
+	alert := &models.Alert{
+		OrgId:     orgID,
+		Name:      name,
+		Message:   settings.Get("message").MustString(),
+		Frequency: frequency,
+		For:       forValue,
+		Settings:  alertSettings,
+	}
+
+	// validate
+	if _, err := NewRuleFromDBAlert(alert); err != nil {
+		return nil, err
+	}
+
+	return alert, nil
+}
+
+func lookupAlertRuleDatasource(orgID int64, jsonQuery *simplejson.Json) (*models.DataSource, error) {
+	if dsName := jsonQuery.Get("datasource").MustString(); dsName != "" {
+		return lookupDatasource(orgID, dsName)
+	}
+
+	if dsID := jsonQuery.Get("datasourceId").MustInt64(); dsID != 0 {
+		query := &models.GetDataSourceByIdQuery{Id: dsID, OrgId: orgID}
+		if err := bus.Dispatch(query); err != nil {
+			return nil, err
+		}
+
+		return query.Result, nil
+	}
+
+	return lookupDatasource(orgID, "")
+}
@@ -0,0 +1,112 @@
+package alerting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+func TestNewAlertFromAlertRule(t *testing.T) {
+	RegisterCondition("query", func(model *simplejson.Json, index int) (Condition, error) {
+		return &FakeCondition{}, nil
+	})
+	RegisterCondition("math", func(model *simplejson.Json, index int) (Condition, error) {
+		return &FakeCondition{}, nil
+	})
+
+	bus.AddHandler("test", func(query *models.GetDataSourceByNameQuery) error {
+		if query.Name != "Prometheus" {
+			return models.ErrDataSourceNotFound
+		}
+		query.Result = &models.DataSource{Id: 12, OrgId: query.OrgId, Name: query.Name}
+		return nil
+	})
+	bus.AddHandler("test", func(query *models.GetDataSourcesQuery) error {
+		query.Result = []*models.DataSource{{Id: 3, Name: "Default", IsDefault: true}}
+		return nil
+	})
+
+	parse := func(t *testing.T, text string) *simplejson.Json {
+		settings, err := simplejson.NewJson([]byte(text))
+		require.NoError(t, err)
+		return settings
+	}
+
+	t.Run("resolves data source names", func(t *testing.T) {
+		settings := parse(t, `{
+			"name": "High CPU",
+			"message": "CPU is high",
+			"frequency": "30s",
+			"for": "5m",
+			"conditions": [{
+				"type": "query",
+				"query": {"params": ["A", "5m", "now"], "datasource": "Prometheus", "model": {"refId": "A", "expr": "up"}}
+			}]
+		}`)
+
+		alert, err := NewAlertFromAlertRule(1, nil, settings)
+		require.NoError(t, err)
+
+		assert.Equal(t, "High CPU", alert.Name)
+		assert.Equal(t, "CPU is high", alert.Message)
+		assert.Equal(t, int64(30), alert.Frequency)
+		assert.Equal(t, 5*time.Minute, alert.For)
+
+		query := alert.Settings.Get("conditions").GetIndex(0).Get("query")
+		assert.Equal(t, int64(12), query.Get("datasourceId").MustInt64())
+		_, hasName := query.CheckGet("datasource")
+		assert.False(t, hasName)
+
+		// the definition is left untouched
+		assert.Equal(t, "Prometheus", settings.Get("conditions").GetIndex(0).Get("query").Get("datasource").MustString())
+	})
+
+	t.Run("uses the default data source", func(t *testing.T) {
+		settings := parse(t, `{"name": "Default", "conditions": [{"type": "query", "query": {"params": ["A", "5m", "now"], "model": {}}}]}`)
+
+		alert, err := NewAlertFromAlertRule(1, nil, settings)
+		require.NoError(t, err)
+		assert.Equal(t, int64(60), alert.Frequency)
+		assert.Equal(t, int64(3), alert.Settings.Get("conditions").GetIndex(0).Get("query").Get("datasourceId").MustInt64())
+	})
+
+	t.Run("resolves data sources of math condition queries", func(t *testing.T) {
+		settings := parse(t, `{"name": "Error ratio", "conditions": [{
+			"type": "math",
+			"queries": [
+				{"query": {"params": ["errors", "5m", "now"], "datasource": "Prometheus", "model": {"expr": "errors"}}},
+				{"query": {"params": ["requests", "5m", "now"], "model": {"expr": "requests"}}}
+			],
+			"expression": "$errors / $requests"
+		}]}`)
+
+		alert, err := NewAlertFromAlertRule(1, nil, settings)
+		require.NoError(t, err)
+
+		queries := alert.Settings.Get("conditions").GetIndex(0).Get("queries")
+		assert.Equal(t, int64(12), queries.GetIndex(0).Get("query").Get("datasourceId").MustInt64())
+		assert.Equal(t, int64(3), queries.GetIndex(1).Get("query").Get("datasourceId").MustInt64())
+	})
+
+	t.Run("returns validation errors", func(t *testing.T) {
+		tcs := []string{
+			`{"conditions": [{"type": "query", "query": {"model": {}}}]}`,
+			`{"name": "no conditions"}`,
+			`{"name": "bad frequency", "frequency": "never", "conditions": [{"type": "query", "query": {"model": {}}}]}`,
+			`{"name": "missing model", "conditions": [{"type": "query", "query": {"params": ["A"]}}]}`,
+			`{"name": "unknown data source", "conditions": [{"type": "query", "query": {"datasource": "Loki", "model": {}}}]}`,
+		}
+
+		for _, tc := range tcs {
+			_, err := NewAlertFromAlertRule(1, nil, parse(t, tc))
+			require.Error(t, err, tc)
+			assert.IsType(t, ValidationError{}, err, tc)
+		}
+	})
+}
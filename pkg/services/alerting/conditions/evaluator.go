@@ -3,10 +3,15 @@ package conditions
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"time"
 
 	"github.com/grafana/grafana/pkg/components/null"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/services/alerting"
+	"github.com/grafana/grafana/pkg/tsdb"
 )
 
 var (
@@ -108,6 +113,145 @@ func (e *rangedEvaluator) Eval(reducedValue null.Float) bool {
 	return false
 }
 
+// anomalyEvaluator compares the reduced value against a baseline computed
+// from the same query shifted by several seasons, ex the same hour of the
+// previous days. Each shifted time range is reduced like the current one and
+// the evaluator fires when the value deviates from the mean of the reduced
+// values by more than Deviations standard deviations.
+type anomalyEvaluator struct {
+	Deviations float64
+	Period     time.Duration
+	Seasons    int
+}
+
+func newAnomalyEvaluator(model *simplejson.Json) (*anomalyEvaluator, error) {
+	params := model.Get("params").MustArray()
+	if len(params) == 0 || params[0] == nil {
+		return nil, alerting.ValidationError{Reason: "Evaluator 'IS ANOMALOUS' is missing the standard deviations parameter"}
+	}
+
+	deviations, err := evaluatorFloatParam(params[0])
+	if err != nil || deviations <= 0 {
+		return nil, alerting.ValidationError{Reason: "Evaluator 'IS ANOMALOUS' has invalid standard deviations parameter"}
+	}
+
+	e := &anomalyEvaluator{Deviations: deviations, Period: 24 * time.Hour, Seasons: 4}
+
+	if len(params) > 1 && params[1] != nil {
+		period, ok := params[1].(string)
+		if !ok {
+			return nil, alerting.ValidationError{Reason: "Evaluator 'IS ANOMALOUS' has invalid period parameter"}
+		}
+		if e.Period, err = parseSeasonPeriod(period); err != nil || e.Period <= 0 {
+			return nil, alerting.ValidationError{Reason: fmt.Sprintf("Evaluator 'IS ANOMALOUS' has invalid period %q", period)}
+		}
+	}
+
+	if len(params) > 2 && params[2] != nil {
+		seasons, err := evaluatorFloatParam(params[2])
+		if err != nil {
+			return nil, alerting.ValidationError{Reason: "Evaluator 'IS ANOMALOUS' has invalid seasons parameter"}
+		}
+		// the deviation of the baseline needs the reduced values of several seasons
+		if seasons < 2 {
+			return nil, alerting.ValidationError{Reason: "Evaluator 'IS ANOMALOUS' needs at least 2 seasons"}
+		}
+		e.Seasons = int(seasons)
+	}
+
+	return e, nil
+}
+
+// Eval returns false since anomalies can only be detected against a baseline.
+func (e *anomalyEvaluator) Eval(reducedValue null.Float) bool {
+	return false
+}
+
+// EvalBaseline returns true if the reduced value deviates from the mean of the
+// reduced baseline values by more than the configured number of standard deviations.
+// A baseline without variance fires for any value different from its mean.
+func (e *anomalyEvaluator) EvalBaseline(reducedValue null.Float, baseline []float64) bool {
+	if !reducedValue.Valid || len(baseline) == 0 {
+		return false
+	}
+
+	mean, stddev := meanAndStdDev(baseline)
+	deviation := math.Abs(reducedValue.Float64 - mean)
+	if stddev == 0 {
+		return deviation > 0
+	}
+
+	return deviation/stddev > e.Deviations
+}
+
+// shiftedTimeRanges returns the time ranges of the baseline, the time range
+// of the query shifted by each season.
+func (e *anomalyEvaluator) shiftedTimeRanges(timeRange *tsdb.TimeRange) []*tsdb.TimeRange {
+	from := timeRange.MustGetFrom()
+	to := timeRange.MustGetTo()
+
+	ranges := make([]*tsdb.TimeRange, 0, e.Seasons)
+	for i := 1; i <= e.Seasons; i++ {
+		shift := time.Duration(i) * e.Period
+		ranges = append(ranges, tsdb.NewTimeRange(
+			strconv.FormatInt(from.Add(-shift).UnixNano()/int64(time.Millisecond), 10),
+			strconv.FormatInt(to.Add(-shift).UnixNano()/int64(time.Millisecond), 10),
+		))
+	}
+
+	return ranges
+}
+
+var seasonPeriodPattern = regexp.MustCompile(`^(\d+)([dw])$`)
+
+// parseSeasonPeriod parses durations with support for days and weeks.
+// Days are always 24 hours so baselines do not move with daylight saving time.
+func parseSeasonPeriod(period string) (time.Duration, error) {
+	result := seasonPeriodPattern.FindStringSubmatch(period)
+	if len(result) != 3 {
+		return time.ParseDuration(period)
+	}
+
+	num, err := strconv.Atoi(result[1])
+	if err != nil {
+		return 0, err
+	}
+
+	day := 24 * time.Hour
+	if result[2] == "w" {
+		return time.Duration(num) * 7 * day, nil
+	}
+
+	return time.Duration(num) * day, nil
+}
+
+func meanAndStdDev(values []float64) (float64, float64) {
+	sum := float64(0)
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	variance := float64(0)
+	for _, v := range values {
+		variance += math.Pow(v-mean, 2)
+	}
+	variance = variance / float64(len(values))
+
+	return mean, math.Sqrt(variance)
+}
+
+func evaluatorFloatParam(param interface{}) (float64, error) {
+	switch v := param.(type) {
+	case json.Number:
+		return v.Float64()
+	case string:
+		return strconv.ParseFloat(v, 64)
+	}
+
+	return 0, fmt.Errorf("invalid parameter %v", param)
+}
+
 // NewAlertEvaluator is a factory function for returning
 // an `AlertEvaluator` depending on the json model.
 func NewAlertEvaluator(model *simplejson.Json) (AlertEvaluator, error) {
@@ -128,6 +272,10 @@ func NewAlertEvaluator(model *simplejson.Json) (AlertEvaluator, error) {
 		return &noValueEvaluator{}, nil
 	}
 
+	if typ == "anomaly" {
+		return newAnomalyEvaluator(model)
+	}
+
 	return nil, fmt.Errorf("Evaluator invalid evaluator type: %s", typ)
 }
 
@@ -152,6 +300,8 @@ func HumanThresholdType(typ string) string {
 		return "IS WITHIN RANGE"
 	case "outside_range":
 		return "IS OUTSIDE RANGE"
+	case "anomaly":
+		return "IS ANOMALOUS"
 	}
 	return ""
 }
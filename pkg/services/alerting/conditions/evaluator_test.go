@@ -2,11 +2,13 @@ package conditions
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/smartystreets/goconvey/convey"
 
 	"github.com/grafana/grafana/pkg/components/null"
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/tsdb"
 )
 
 func evalutorScenario(json string, reducedValue float64, datapoints ...float64) bool {
@@ -60,4 +62,81 @@ func TestEvalutors(t *testing.T) {
 
 		})
 	})
+
+	Convey("anomaly", t, func() {
+		anomalyScenario := func(json string) *anomalyEvaluator {
+			jsonModel, err := simplejson.NewJson([]byte(json))
+			So(err, ShouldBeNil)
+
+			evaluator, err := NewAlertEvaluator(jsonModel)
+			So(err, ShouldBeNil)
+
+			anomaly, ok := evaluator.(*anomalyEvaluator)
+			So(ok, ShouldBeTrue)
+			return anomaly
+		}
+
+		Convey("should read params", func() {
+			e := anomalyScenario(`{"type": "anomaly", "params": [3, "1w", 2] }`)
+			So(e.Deviations, ShouldEqual, 3)
+			So(e.Period, ShouldEqual, 7*24*time.Hour)
+			So(e.Seasons, ShouldEqual, 2)
+		})
+
+		Convey("should default to one day and four seasons", func() {
+			e := anomalyScenario(`{"type": "anomaly", "params": [2] }`)
+			So(e.Period, ShouldEqual, 24*time.Hour)
+			So(e.Seasons, ShouldEqual, 4)
+		})
+
+		Convey("should fail on invalid params", func() {
+			for _, json := range []string{
+				`{"type": "anomaly", "params": [] }`,
+				`{"type": "anomaly", "params": [-1] }`,
+				`{"type": "anomaly", "params": [3, "yesterday"] }`,
+				`{"type": "anomaly", "params": [3, "1d", 0] }`,
+				`{"type": "anomaly", "params": [3, "1d", 1] }`,
+			} {
+				jsonModel, err := simplejson.NewJson([]byte(json))
+				So(err, ShouldBeNil)
+
+				_, err = NewAlertEvaluator(jsonModel)
+				So(err, ShouldNotBeNil)
+			}
+		})
+
+		Convey("should compare against the baseline", func() {
+			e := anomalyScenario(`{"type": "anomaly", "params": [2, "1d"] }`)
+
+			// mean 10, standard deviation 2
+			baseline := []float64{8, 12, 8, 12}
+
+			So(e.EvalBaseline(null.FloatFrom(13), baseline), ShouldBeFalse)
+			So(e.EvalBaseline(null.FloatFrom(14), baseline), ShouldBeFalse)
+			So(e.EvalBaseline(null.FloatFrom(14.5), baseline), ShouldBeTrue)
+			So(e.EvalBaseline(null.FloatFrom(5.5), baseline), ShouldBeTrue)
+			So(e.EvalBaseline(null.FloatFromPtr(nil), baseline), ShouldBeFalse)
+			So(e.EvalBaseline(null.FloatFrom(100), nil), ShouldBeFalse)
+			So(e.Eval(null.FloatFrom(100)), ShouldBeFalse)
+		})
+
+		Convey("should fire on any change of a flat baseline", func() {
+			e := anomalyScenario(`{"type": "anomaly", "params": [3] }`)
+
+			So(e.EvalBaseline(null.FloatFrom(0), []float64{0, 0, 0}), ShouldBeFalse)
+			So(e.EvalBaseline(null.FloatFrom(1), []float64{0, 0, 0}), ShouldBeTrue)
+		})
+
+		Convey("should shift the time range by each season", func() {
+			e := anomalyScenario(`{"type": "anomaly", "params": [3, "1d", 2] }`)
+			now := time.Date(2020, 5, 14, 12, 0, 0, 0, time.UTC)
+
+			ranges := e.shiftedTimeRanges(tsdb.NewFakeTimeRange("1h", "now", now))
+			So(ranges, ShouldHaveLength, 2)
+			So(ranges[0].MustGetFrom().UTC(), ShouldEqual, time.Date(2020, 5, 13, 11, 0, 0, 0, time.UTC))
+			So(ranges[0].MustGetTo().UTC(), ShouldEqual, time.Date(2020, 5, 13, 12, 0, 0, 0, time.UTC))
+			So(ranges[1].MustGetFrom().UTC(), ShouldEqual, time.Date(2020, 5, 12, 11, 0, 0, 0, time.UTC))
+			So(ranges[1].MustGetTo().UTC(), ShouldEqual, time.Date(2020, 5, 12, 12, 0, 0, 0, time.UTC))
+		})
+	})
 }
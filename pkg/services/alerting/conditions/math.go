@@ -0,0 +1,245 @@
+package conditions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	"github.com/grafana/grafana/pkg/tsdb"
+)
+
+func init() {
+	alerting.RegisterCondition("math", func(model *simplejson.Json, index int) (alerting.Condition, error) {
+		return newMathCondition(model, index)
+	})
+}
+
+// MathCondition issues several queries, reduces the timeseries of each
+// query into single values and evaluates an arithmetic expression over
+// them, ex "$A / $B * 100", before evaluating if the result is firing.
+//
+// Series of different queries are combined when the tags they have in
+// common are equal, so a series without tags is combined with every
+// series of the other queries.
+type MathCondition struct {
+	Index         int
+	Queries       []*MathQuery
+	Expression    *mathExpression
+	Evaluator     AlertEvaluator
+	Operator      string
+	HandleRequest tsdb.HandleRequestFunc
+}
+
+// MathQuery is a query of a math condition. Its reduced
+// value is referenced in the expression by $RefID.
+type MathQuery struct {
+	RefID   string
+	Query   AlertQuery
+	Reducer *queryReducer
+}
+
+// mathSeries holds the reduced values of one combination
+// of series, one series per query.
+type mathSeries struct {
+	names  []string
+	tags   map[string]string
+	values map[string]null.Float
+}
+
+// Eval evaluates the `MathCondition`.
+func (c *MathCondition) Eval(context *alerting.EvalContext) (*alerting.ConditionResult, error) {
+	combined := []*mathSeries{{tags: map[string]string{}, values: map[string]null.Float{}}}
+
+	for _, q := range c.Queries {
+		queryCondition := &QueryCondition{Index: c.Index, Query: q.Query, HandleRequest: c.HandleRequest}
+		seriesList, err := queryCondition.executeQuery(context, tsdb.NewTimeRange(q.Query.From, q.Query.To))
+		if err != nil {
+			return nil, err
+		}
+
+		next := make([]*mathSeries, 0)
+		for _, series := range seriesList {
+			reducedValue := q.Reducer.Reduce(series)
+
+			for _, s := range combined {
+				if !tagsMatch(s.tags, series.Tags) {
+					continue
+				}
+				next = append(next, s.with(q.RefID, series, reducedValue))
+			}
+		}
+		combined = next
+	}
+
+	emptySeriesCount := 0
+	evalMatchCount := 0
+	var matches []*alerting.EvalMatch
+
+	for _, s := range combined {
+		value := c.Expression.Eval(s.values)
+		evalMatch := c.Evaluator.Eval(value)
+
+		if !value.Valid {
+			emptySeriesCount++
+		}
+
+		if context.IsTestRun {
+			context.Logs = append(context.Logs, &alerting.ResultLogEntry{
+				Message: fmt.Sprintf("Condition[%d]: Eval: %v, Metric: %s, Value: %s", c.Index, evalMatch, strings.Join(s.names, ", "), value),
+			})
+		}
+
+		if evalMatch {
+			evalMatchCount++
+
+			matches = append(matches, &alerting.EvalMatch{
+				Metric: c.Expression.Text + formatMathTags(s.tags),
+				Value:  value,
+				Tags:   s.tags,
+			})
+		}
+	}
+
+	// handle no series special case
+	if len(combined) == 0 {
+		// eval condition for null value
+		evalMatch := c.Evaluator.Eval(null.FloatFromPtr(nil))
+
+		if context.IsTestRun {
+			context.Logs = append(context.Logs, &alerting.ResultLogEntry{
+				Message: fmt.Sprintf("Condition[%d]: Eval: %v, Queries Returned No Matching Series (reduced to null/no value)", c.Index, evalMatch),
+			})
+		}
+
+		if evalMatch {
+			evalMatchCount++
+			matches = append(matches, &alerting.EvalMatch{Metric: "NoData", Value: null.FloatFromPtr(nil)})
+		}
+	}
+
+	return &alerting.ConditionResult{
+		Firing:      evalMatchCount > 0,
+		NoDataFound: emptySeriesCount == len(combined),
+		Operator:    c.Operator,
+		EvalMatches: matches,
+	}, nil
+}
+
+// with returns a copy of the combination extended with the reduced value of a series.
+func (s *mathSeries) with(refID string, series *tsdb.TimeSeries, value null.Float) *mathSeries {
+	result := &mathSeries{
+		names:  append(append([]string{}, s.names...), series.Name),
+		tags:   make(map[string]string, len(s.tags)+len(series.Tags)),
+		values: make(map[string]null.Float, len(s.values)+1),
+	}
+
+	for k, v := range s.tags {
+		result.tags[k] = v
+	}
+	for k, v := range series.Tags {
+		result.tags[k] = v
+	}
+	for k, v := range s.values {
+		result.values[k] = v
+	}
+	result.values[refID] = value
+
+	return result
+}
+
+// tagsMatch returns true if the tags both sets have in common are equal.
+func tagsMatch(a, b map[string]string) bool {
+	for k, v := range b {
+		if value, ok := a[k]; ok && value != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+func formatMathTags(tags map[string]string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(tags))
+	for k, v := range tags {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+
+	return " {" + strings.Join(pairs, ", ") + "}"
+}
+
+func newMathCondition(model *simplejson.Json, index int) (*MathCondition, error) {
+	condition := MathCondition{}
+	condition.Index = index
+	condition.HandleRequest = tsdb.HandleRequest
+
+	refIDs := make([]string, 0)
+	for _, queryObj := range model.Get("queries").MustArray() {
+		queryJSON := simplejson.NewFromAny(queryObj)
+
+		params, err := getQueryParams(queryJSON.Get("query"), index)
+		if err != nil {
+			return nil, err
+		}
+
+		refID := params[0]
+		if refID == "" {
+			return nil, fmt.Errorf("error in condition %v: query ref id is missing", index)
+		}
+
+		query, err := newAlertQuery(queryJSON.Get("query"), index)
+		if err != nil {
+			return nil, err
+		}
+
+		if inSlice(refID, refIDs) {
+			return nil, fmt.Errorf("error in condition %v: query %s is defined more than once", index, refID)
+		}
+		refIDs = append(refIDs, refID)
+
+		reducer, err := newQueryReducer(queryJSON.Get("reducer"))
+		if err != nil {
+			return nil, fmt.Errorf("error in condition %v: %v", index, err)
+		}
+
+		condition.Queries = append(condition.Queries, &MathQuery{RefID: refID, Query: *query, Reducer: reducer})
+	}
+
+	if len(condition.Queries) == 0 {
+		return nil, fmt.Errorf("error in condition %v: math condition has no queries", index)
+	}
+
+	expression, err := newMathExpression(model.Get("expression").MustString())
+	if err != nil {
+		return nil, fmt.Errorf("error in condition %v: %v", index, err)
+	}
+
+	for _, variable := range expression.Variables {
+		if !inSlice(variable, refIDs) {
+			return nil, fmt.Errorf("error in condition %v: expression refers to query $%s that cannot be found", index, variable)
+		}
+	}
+	condition.Expression = expression
+
+	evaluator, err := NewAlertEvaluator(model.Get("evaluator"))
+	if err != nil {
+		return nil, fmt.Errorf("error in condition %v: %v", index, err)
+	}
+
+	// baselines are computed from the history of a single query
+	if _, ok := evaluator.(*anomalyEvaluator); ok {
+		return nil, fmt.Errorf("error in condition %v: math conditions do not support the anomaly evaluator", index)
+	}
+	condition.Evaluator = evaluator
+
+	condition.Operator = model.Get("operator").Get("type").MustString("and")
+
+	return &condition, nil
+}
@@ -0,0 +1,212 @@
+package conditions
+
+import (
+	"fmt"
+	"strconv"
+	"unicode"
+
+	"github.com/grafana/grafana/pkg/components/null"
+)
+
+// mathExpression is an arithmetic expression over the reduced values
+// of the queries of a math condition, ex "$A / $B * 100".
+//
+// It supports numbers, query variables ($refId), the +, -, * and /
+// operators and parentheses. The result is null when a variable is null
+// or when dividing by zero.
+type mathExpression struct {
+	Text      string
+	Variables []string
+	root      mathNode
+}
+
+type mathNode interface {
+	eval(vars map[string]null.Float) null.Float
+}
+
+type mathNumber float64
+
+func (n mathNumber) eval(vars map[string]null.Float) null.Float {
+	return null.FloatFrom(float64(n))
+}
+
+type mathVariable string
+
+func (v mathVariable) eval(vars map[string]null.Float) null.Float {
+	return vars[string(v)]
+}
+
+type mathNegation struct {
+	node mathNode
+}
+
+func (n *mathNegation) eval(vars map[string]null.Float) null.Float {
+	value := n.node.eval(vars)
+	if !value.Valid {
+		return value
+	}
+
+	return null.FloatFrom(-value.Float64)
+}
+
+type mathBinary struct {
+	op          rune
+	left, right mathNode
+}
+
+func (b *mathBinary) eval(vars map[string]null.Float) null.Float {
+	left := b.left.eval(vars)
+	right := b.right.eval(vars)
+	if !left.Valid || !right.Valid {
+		return null.FloatFromPtr(nil)
+	}
+
+	switch b.op {
+	case '+':
+		return null.FloatFrom(left.Float64 + right.Float64)
+	case '-':
+		return null.FloatFrom(left.Float64 - right.Float64)
+	case '*':
+		return null.FloatFrom(left.Float64 * right.Float64)
+	case '/':
+		if right.Float64 == 0 {
+			return null.FloatFromPtr(nil)
+		}
+		return null.FloatFrom(left.Float64 / right.Float64)
+	}
+
+	return null.FloatFromPtr(nil)
+}
+
+// Eval returns the value of the expression for the given variable values.
+func (e *mathExpression) Eval(vars map[string]null.Float) null.Float {
+	return e.root.eval(vars)
+}
+
+func newMathExpression(text string) (*mathExpression, error) {
+	p := &mathParser{input: []rune(text)}
+
+	root, err := p.parseSum()
+	if err == nil && p.peek() != 0 {
+		err = fmt.Errorf("unexpected %q at position %d", p.peek(), p.pos)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Invalid math expression %q: %v", text, err)
+	}
+
+	return &mathExpression{Text: text, Variables: p.variables, root: root}, nil
+}
+
+// mathParser is a recursive descent parser for math expressions.
+type mathParser struct {
+	input     []rune
+	pos       int
+	variables []string
+}
+
+// peek returns the next non space character or 0 at the end of the input.
+func (p *mathParser) peek() rune {
+	for p.pos < len(p.input) && unicode.IsSpace(p.input[p.pos]) {
+		p.pos++
+	}
+
+	if p.pos >= len(p.input) {
+		return 0
+	}
+
+	return p.input[p.pos]
+}
+
+func (p *mathParser) parseSum() (mathNode, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+
+	for op := p.peek(); op == '+' || op == '-'; op = p.peek() {
+		p.pos++
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = &mathBinary{op: op, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *mathParser) parseProduct() (mathNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for op := p.peek(); op == '*' || op == '/'; op = p.peek() {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &mathBinary{op: op, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *mathParser) parseUnary() (mathNode, error) {
+	if p.peek() == '-' {
+		p.pos++
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &mathNegation{node: node}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *mathParser) parsePrimary() (mathNode, error) {
+	c := p.peek()
+	switch {
+	case c == 0:
+		return nil, fmt.Errorf("unexpected end of expression")
+	case c == '(':
+		p.pos++
+		node, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("missing closing parenthesis at position %d", p.pos)
+		}
+		p.pos++
+		return node, nil
+	case c == '$':
+		p.pos++
+		start := p.pos
+		for p.pos < len(p.input) && (unicode.IsLetter(p.input[p.pos]) || unicode.IsDigit(p.input[p.pos]) || p.input[p.pos] == '_') {
+			p.pos++
+		}
+		if start == p.pos {
+			return nil, fmt.Errorf("missing variable name at position %d", start)
+		}
+		name := string(p.input[start:p.pos])
+		if !inSlice(name, p.variables) {
+			p.variables = append(p.variables, name)
+		}
+		return mathVariable(name), nil
+	case unicode.IsDigit(c) || c == '.':
+		start := p.pos
+		for p.pos < len(p.input) && (unicode.IsDigit(p.input[p.pos]) || p.input[p.pos] == '.') {
+			p.pos++
+		}
+		value, err := strconv.ParseFloat(string(p.input[start:p.pos]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", string(p.input[start:p.pos]))
+		}
+		return mathNumber(value), nil
+	}
+
+	return nil, fmt.Errorf("unexpected %q at position %d", c, p.pos)
+}
@@ -0,0 +1,229 @@
+package conditions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	"github.com/grafana/grafana/pkg/tsdb"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMathExpression(t *testing.T) {
+	Convey("Math expressions", t, func() {
+		vars := map[string]null.Float{
+			"A": null.FloatFrom(10),
+			"B": null.FloatFrom(4),
+			"C": null.FloatFromPtr(nil),
+			"Z": null.FloatFrom(0),
+		}
+
+		tcs := []struct {
+			expression string
+			expected   null.Float
+		}{
+			{expression: "$A + $B", expected: null.FloatFrom(14)},
+			{expression: "$A - $B * 2", expected: null.FloatFrom(2)},
+			{expression: "($A - $B) * 2", expected: null.FloatFrom(12)},
+			{expression: "$B / $A * 100", expected: null.FloatFrom(40)},
+			{expression: "-$A + 1.5", expected: null.FloatFrom(-8.5)},
+			{expression: "$A - -$B", expected: null.FloatFrom(14)},
+			{expression: "$A + $C", expected: null.FloatFromPtr(nil)},
+			{expression: "$A / $Z", expected: null.FloatFromPtr(nil)},
+		}
+
+		for _, tc := range tcs {
+			expression, err := newMathExpression(tc.expression)
+			So(err, ShouldBeNil)
+			So(expression.Eval(vars), ShouldResemble, tc.expected)
+		}
+
+		Convey("Should return the variables", func() {
+			expression, err := newMathExpression("$errors / ($errors + $success)")
+			So(err, ShouldBeNil)
+			So(expression.Variables, ShouldResemble, []string{"errors", "success"})
+		})
+
+		Convey("Should fail on invalid expressions", func() {
+			for _, text := range []string{"", "$A +", "($A", "$A $B", "$ + 1", "1..2", "$A % 2"} {
+				_, err := newMathExpression(text)
+				So(err, ShouldNotBeNil)
+			}
+		})
+	})
+}
+
+func TestMathCondition(t *testing.T) {
+	Convey("when evaluating math condition", t, func() {
+		bus.AddHandler("test", func(query *models.GetDataSourceByIdQuery) error {
+			query.Result = &models.DataSource{Id: 1, Type: "graphite"}
+			return nil
+		})
+
+		ctx := &mathConditionTestContext{
+			series: map[string]tsdb.TimeSeriesSlice{},
+			result: &alerting.EvalContext{Rule: &alerting.Rule{}},
+		}
+
+		Convey("Can read math condition from json model", func() {
+			condition, err := newMathCondition(ctx.model(`"$errors / $requests * 100"`), 0)
+			So(err, ShouldBeNil)
+
+			So(condition.Queries, ShouldHaveLength, 2)
+			So(condition.Queries[0].RefID, ShouldEqual, "errors")
+			So(condition.Queries[0].Query.From, ShouldEqual, "5m")
+			So(condition.Queries[0].Query.DatasourceID, ShouldEqual, 1)
+			So(condition.Queries[0].Reducer.Type, ShouldEqual, "sum")
+			So(condition.Queries[1].RefID, ShouldEqual, "requests")
+			So(condition.Expression.Text, ShouldEqual, "$errors / $requests * 100")
+			So(condition.Operator, ShouldEqual, "and")
+		})
+
+		Convey("Should fail when the expression refers to a missing query", func() {
+			_, err := newMathCondition(ctx.model(`"$errors / $total"`), 0)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "$total")
+		})
+
+		Convey("Should fail on invalid query params", func() {
+			for _, params := range []interface{}{[]interface{}{}, []interface{}{1, "5m", "now"}, []interface{}{"", "5m", "now"}} {
+				model := ctx.model(`"$errors / $requests"`)
+				model.Get("queries").GetIndex(1).Get("query").Set("params", params)
+
+				_, err := newMathCondition(model, 2)
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "error in condition 2")
+			}
+		})
+
+		Convey("Should fail with the anomaly evaluator", func() {
+			model := ctx.model(`"$errors / $requests"`)
+			evaluator, err := simplejson.NewJson([]byte(`{"type": "anomaly", "params": [3]}`))
+			So(err, ShouldBeNil)
+			model.Set("evaluator", evaluator.Interface())
+
+			_, err = newMathCondition(model, 0)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "anomaly")
+		})
+
+		Convey("Should fire when the error ratio is above 5%", func() {
+			ctx.series["errors"] = tsdb.TimeSeriesSlice{tsdb.NewTimeSeries("errors", tsdb.NewTimeSeriesPointsFromArgs(4, 0, 3, 1))}
+			ctx.series["requests"] = tsdb.TimeSeriesSlice{tsdb.NewTimeSeries("requests", tsdb.NewTimeSeriesPointsFromArgs(60, 0, 40, 1))}
+
+			cr, err := ctx.exec(`"$errors / $requests * 100"`)
+			So(err, ShouldBeNil)
+			So(cr.Firing, ShouldBeTrue)
+			So(cr.EvalMatches, ShouldHaveLength, 1)
+			So(cr.EvalMatches[0].Value.Float64, ShouldAlmostEqual, 7)
+		})
+
+		Convey("Should not fire when the error ratio is below 5%", func() {
+			ctx.series["errors"] = tsdb.TimeSeriesSlice{tsdb.NewTimeSeries("errors", tsdb.NewTimeSeriesPointsFromArgs(1, 0))}
+			ctx.series["requests"] = tsdb.TimeSeriesSlice{tsdb.NewTimeSeries("requests", tsdb.NewTimeSeriesPointsFromArgs(100, 0))}
+
+			cr, err := ctx.exec(`"$errors / $requests * 100"`)
+			So(err, ShouldBeNil)
+			So(cr.Firing, ShouldBeFalse)
+			So(cr.NoDataFound, ShouldBeFalse)
+		})
+
+		Convey("Should match series by tags", func() {
+			ctx.series["errors"] = tsdb.TimeSeriesSlice{
+				{Name: "errors a", Tags: map[string]string{"host": "a"}, Points: tsdb.NewTimeSeriesPointsFromArgs(10, 0)},
+				{Name: "errors b", Tags: map[string]string{"host": "b"}, Points: tsdb.NewTimeSeriesPointsFromArgs(1, 0)},
+			}
+			ctx.series["requests"] = tsdb.TimeSeriesSlice{
+				{Name: "requests b", Tags: map[string]string{"host": "b"}, Points: tsdb.NewTimeSeriesPointsFromArgs(100, 0)},
+				{Name: "requests a", Tags: map[string]string{"host": "a"}, Points: tsdb.NewTimeSeriesPointsFromArgs(100, 0)},
+				{Name: "requests c", Tags: map[string]string{"host": "c"}, Points: tsdb.NewTimeSeriesPointsFromArgs(100, 0)},
+			}
+
+			cr, err := ctx.exec(`"$errors / $requests * 100"`)
+			So(err, ShouldBeNil)
+			So(cr.Firing, ShouldBeTrue)
+			So(cr.EvalMatches, ShouldHaveLength, 1)
+			So(cr.EvalMatches[0].Value, ShouldResemble, null.FloatFrom(10))
+			So(cr.EvalMatches[0].Tags, ShouldResemble, map[string]string{"host": "a"})
+			So(cr.EvalMatches[0].Metric, ShouldEqual, "$errors / $requests * 100 {host=a}")
+		})
+
+		Convey("Should combine a series without tags with every series", func() {
+			ctx.series["errors"] = tsdb.TimeSeriesSlice{
+				{Name: "errors a", Tags: map[string]string{"host": "a"}, Points: tsdb.NewTimeSeriesPointsFromArgs(10, 0)},
+				{Name: "errors b", Tags: map[string]string{"host": "b"}, Points: tsdb.NewTimeSeriesPointsFromArgs(6, 0)},
+			}
+			ctx.series["requests"] = tsdb.TimeSeriesSlice{tsdb.NewTimeSeries("requests", tsdb.NewTimeSeriesPointsFromArgs(100, 0))}
+
+			cr, err := ctx.exec(`"$errors / $requests * 100"`)
+			So(err, ShouldBeNil)
+			So(cr.Firing, ShouldBeTrue)
+			So(cr.EvalMatches, ShouldHaveLength, 2)
+		})
+
+		Convey("Should report no data when a query has no series", func() {
+			ctx.series["errors"] = tsdb.TimeSeriesSlice{tsdb.NewTimeSeries("errors", tsdb.NewTimeSeriesPointsFromArgs(1, 0))}
+
+			cr, err := ctx.exec(`"$errors / $requests * 100"`)
+			So(err, ShouldBeNil)
+			So(cr.Firing, ShouldBeFalse)
+			So(cr.NoDataFound, ShouldBeTrue)
+		})
+
+		Convey("Should report no data when dividing by zero", func() {
+			ctx.series["errors"] = tsdb.TimeSeriesSlice{tsdb.NewTimeSeries("errors", tsdb.NewTimeSeriesPointsFromArgs(1, 0))}
+			ctx.series["requests"] = tsdb.TimeSeriesSlice{tsdb.NewTimeSeries("requests", tsdb.NewTimeSeriesPointsFromArgs(0, 0))}
+
+			cr, err := ctx.exec(`"$errors / $requests * 100"`)
+			So(err, ShouldBeNil)
+			So(cr.Firing, ShouldBeFalse)
+			So(cr.NoDataFound, ShouldBeTrue)
+		})
+	})
+}
+
+type mathConditionTestContext struct {
+	series map[string]tsdb.TimeSeriesSlice
+	result *alerting.EvalContext
+}
+
+func (ctx *mathConditionTestContext) model(expression string) *simplejson.Json {
+	jsonModel, err := simplejson.NewJson([]byte(`{
+            "type": "math",
+            "queries": [
+              {
+                "query": {"params": ["errors", "5m", "now"], "datasourceId": 1, "model": {"target": "errors"}},
+                "reducer": {"type": "sum"}
+              },
+              {
+                "query": {"params": ["requests", "5m", "now"], "datasourceId": 1, "model": {"target": "requests"}},
+                "reducer": {"type": "sum"}
+              }
+            ],
+            "expression": ` + expression + `,
+            "evaluator": {"type": "gt", "params": [5]}
+          }`))
+	So(err, ShouldBeNil)
+
+	return jsonModel
+}
+
+func (ctx *mathConditionTestContext) exec(expression string) (*alerting.ConditionResult, error) {
+	condition, err := newMathCondition(ctx.model(expression), 0)
+	So(err, ShouldBeNil)
+
+	condition.HandleRequest = func(context context.Context, dsInfo *models.DataSource, req *tsdb.TsdbQuery) (*tsdb.Response, error) {
+		target := req.Queries[0].Model.Get("target").MustString()
+		return &tsdb.Response{
+			Results: map[string]*tsdb.QueryResult{
+				"A": {Series: ctx.series[target]},
+			},
+		}, nil
+	}
+
+	return condition.Eval(ctx.result)
+}
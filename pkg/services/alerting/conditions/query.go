@@ -52,13 +52,27 @@ func (c *QueryCondition) Eval(context *alerting.EvalContext) (*alerting.Conditio
 		return nil, err
 	}
 
+	var baselines map[string][]float64
+	anomaly, isAnomaly := c.Evaluator.(*anomalyEvaluator)
+	if isAnomaly {
+		if baselines, err = c.getBaselines(context, timeRange, anomaly); err != nil {
+			return nil, err
+		}
+	}
+
 	emptySeriesCount := 0
 	evalMatchCount := 0
 	var matches []*alerting.EvalMatch
 
 	for _, series := range seriesList {
 		reducedValue := c.Reducer.Reduce(series)
-		evalMatch := c.Evaluator.Eval(reducedValue)
+
+		var evalMatch bool
+		if isAnomaly {
+			evalMatch = anomaly.EvalBaseline(reducedValue, getSeriesBaseline(baselines, series, len(seriesList)))
+		} else {
+			evalMatch = c.Evaluator.Eval(reducedValue)
+		}
 
 		if !reducedValue.Valid {
 			emptySeriesCount++
@@ -106,6 +120,46 @@ func (c *QueryCondition) Eval(context *alerting.EvalContext) (*alerting.Conditio
 	}, nil
 }
 
+// getBaselines returns the reduced values of the series of the query over the time
+// ranges of the baseline of an anomaly evaluator, one per season, by series name and tags.
+// Reducing each season like the current time range keeps reducers like sum comparable.
+func (c *QueryCondition) getBaselines(context *alerting.EvalContext, timeRange *tsdb.TimeRange, evaluator *anomalyEvaluator) (map[string][]float64, error) {
+	baselines := make(map[string][]float64)
+
+	for _, shifted := range evaluator.shiftedTimeRanges(timeRange) {
+		seriesList, err := c.executeQuery(context, shifted)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, series := range seriesList {
+			reducedValue := c.Reducer.Reduce(series)
+			if reducedValue.Valid {
+				key := models.GetAlertInstanceLabelsHash(series.Name, series.Tags)
+				baselines[key] = append(baselines[key], reducedValue.Float64)
+			}
+		}
+	}
+
+	return baselines, nil
+}
+
+// getSeriesBaseline returns the baseline of a series. A query returning a single
+// series uses the only baseline even if the series name has changed.
+func getSeriesBaseline(baselines map[string][]float64, series *tsdb.TimeSeries, seriesCount int) []float64 {
+	if baseline, ok := baselines[models.GetAlertInstanceLabelsHash(series.Name, series.Tags)]; ok {
+		return baseline
+	}
+
+	if seriesCount == 1 && len(baselines) == 1 {
+		for _, baseline := range baselines {
+			return baseline
+		}
+	}
+
+	return nil
+}
+
 func (c *QueryCondition) executeQuery(context *alerting.EvalContext, timeRange *tsdb.TimeRange) (tsdb.TimeSeriesSlice, error) {
 	getDsInfo := &models.GetDataSourceByIdQuery{
 		Id:    c.Query.DatasourceID,
@@ -238,24 +292,18 @@ func newQueryCondition(model *simplejson.Json, index int) (*QueryCondition, erro
 	condition.Index = index
 	condition.HandleRequest = tsdb.HandleRequest
 
-	queryJSON := model.Get("query")
-
-	condition.Query.Model = queryJSON.Get("model")
-	condition.Query.From = queryJSON.Get("params").MustArray()[1].(string)
-	condition.Query.To = queryJSON.Get("params").MustArray()[2].(string)
-
-	if err := validateFromValue(condition.Query.From); err != nil {
-		return nil, err
-	}
-
-	if err := validateToValue(condition.Query.To); err != nil {
+	query, err := newAlertQuery(model.Get("query"), index)
+	if err != nil {
 		return nil, err
 	}
-
-	condition.Query.DatasourceID = queryJSON.Get("datasourceId").MustInt64()
+	condition.Query = *query
 
 	reducerJSON := model.Get("reducer")
-	condition.Reducer = newSimpleReducer(reducerJSON.Get("type").MustString())
+	reducer, err := newQueryReducer(reducerJSON)
+	if err != nil {
+		return nil, fmt.Errorf("error in condition %v: %v", index, err)
+	}
+	condition.Reducer = reducer
 
 	evaluatorJSON := model.Get("evaluator")
 	evaluator, err := NewAlertEvaluator(evaluatorJSON)
@@ -271,6 +319,50 @@ func newQueryCondition(model *simplejson.Json, index int) (*QueryCondition, erro
 	return &condition, nil
 }
 
+// newAlertQuery reads the query model, data source and time range of a condition query.
+func newAlertQuery(queryJSON *simplejson.Json, index int) (*AlertQuery, error) {
+	params, err := getQueryParams(queryJSON, index)
+	if err != nil {
+		return nil, err
+	}
+
+	query := &AlertQuery{}
+	query.Model = queryJSON.Get("model")
+	query.From = params[1]
+	query.To = params[2]
+
+	if err := validateFromValue(query.From); err != nil {
+		return nil, err
+	}
+
+	if err := validateToValue(query.To); err != nil {
+		return nil, err
+	}
+
+	query.DatasourceID = queryJSON.Get("datasourceId").MustInt64()
+
+	return query, nil
+}
+
+// getQueryParams returns the ref id, from and to params of a condition query.
+func getQueryParams(queryJSON *simplejson.Json, index int) ([]string, error) {
+	rawParams := queryJSON.Get("params").MustArray()
+	if len(rawParams) < 3 {
+		return nil, alerting.ValidationError{Reason: fmt.Sprintf("error in condition %v: query params must contain the query ref id, from and to", index)}
+	}
+
+	params := make([]string, 0, 3)
+	for _, rawParam := range rawParams[:3] {
+		param, ok := rawParam.(string)
+		if !ok {
+			return nil, alerting.ValidationError{Reason: fmt.Sprintf("error in condition %v: query params must be strings", index)}
+		}
+		params = append(params, param)
+	}
+
+	return params, nil
+}
+
 func validateFromValue(from string) error {
 	fromRaw := strings.Replace(from, "now-", "", 1)
 
@@ -115,6 +115,44 @@ func TestQueryCondition(t *testing.T) {
 				})
 			})
 
+			Convey("Anomaly evaluator", func() {
+				ctx.evaluator = `{"type": "anomaly", "params": [3, "1d", 2]}`
+				// the averages of host a are 100 and 110, the ones of host b 11 and 12
+				ctx.baselines = []tsdb.TimeSeriesSlice{
+					{
+						{Name: "test1", Tags: map[string]string{"host": "a"}, Points: tsdb.NewTimeSeriesPointsFromArgs(90, 0, 110, 1)},
+						{Name: "test1", Tags: map[string]string{"host": "b"}, Points: tsdb.NewTimeSeriesPointsFromArgs(10, 0, 12, 1)},
+					},
+					{
+						{Name: "test1", Tags: map[string]string{"host": "a"}, Points: tsdb.NewTimeSeriesPointsFromArgs(100, 0, 120, 1)},
+						{Name: "test1", Tags: map[string]string{"host": "b"}, Points: tsdb.NewTimeSeriesPointsFromArgs(11, 0, 13, 1)},
+					},
+				}
+
+				Convey("Should fire for series deviating from their baseline", func() {
+					ctx.series = tsdb.TimeSeriesSlice{
+						{Name: "test1", Tags: map[string]string{"host": "a"}, Points: tsdb.NewTimeSeriesPointsFromArgs(119, 0)},
+						{Name: "test1", Tags: map[string]string{"host": "b"}, Points: tsdb.NewTimeSeriesPointsFromArgs(119, 0)},
+					}
+					cr, err := ctx.exec()
+
+					So(err, ShouldBeNil)
+					So(cr.Firing, ShouldBeTrue)
+					So(cr.EvalMatches, ShouldHaveLength, 1)
+					So(cr.EvalMatches[0].Tags, ShouldResemble, map[string]string{"host": "b"})
+				})
+
+				Convey("Should not fire for series without baseline", func() {
+					ctx.series = tsdb.TimeSeriesSlice{
+						{Name: "test1", Tags: map[string]string{"host": "c"}, Points: tsdb.NewTimeSeriesPointsFromArgs(1000, 0)},
+					}
+					cr, err := ctx.exec()
+
+					So(err, ShouldBeNil)
+					So(cr.Firing, ShouldBeFalse)
+				})
+			})
+
 			Convey("Empty series", func() {
 				Convey("Should set Firing if eval match", func() {
 					ctx.evaluator = `{"type": "no_value", "params": []}`
@@ -161,6 +199,50 @@ func TestQueryCondition(t *testing.T) {
 				})
 			})
 		})
+
+		queryConditionScenario("Given sum() and anomaly", func(ctx *queryConditionTestContext) {
+			ctx.reducer = `{"type": "sum"}`
+			ctx.evaluator = `{"type": "anomaly", "params": [3, "1d", 3]}`
+
+			// the sums of the seasons are 100, 104 and 96
+			ctx.baselines = []tsdb.TimeSeriesSlice{
+				{tsdb.NewTimeSeries("test1", tsdb.NewTimeSeriesPointsFromArgs(10, 0, 20, 1, 30, 2, 40, 3))},
+				{tsdb.NewTimeSeries("test1", tsdb.NewTimeSeriesPointsFromArgs(14, 0, 20, 1, 30, 2, 40, 3))},
+				{tsdb.NewTimeSeries("test1", tsdb.NewTimeSeriesPointsFromArgs(6, 0, 20, 1, 30, 2, 40, 3))},
+			}
+
+			Convey("Should compare the sum with the sums of the seasons", func() {
+				ctx.series = tsdb.TimeSeriesSlice{tsdb.NewTimeSeries("test1", tsdb.NewTimeSeriesPointsFromArgs(12, 0, 20, 1, 30, 2, 40, 3))}
+				cr, err := ctx.exec()
+
+				So(err, ShouldBeNil)
+				So(cr.Firing, ShouldBeFalse)
+			})
+
+			Convey("Should fire when the sum deviates from the sums of the seasons", func() {
+				ctx.series = tsdb.TimeSeriesSlice{tsdb.NewTimeSeries("test1", tsdb.NewTimeSeriesPointsFromArgs(60, 0, 20, 1, 30, 2, 40, 3))}
+				cr, err := ctx.exec()
+
+				So(err, ShouldBeNil)
+				So(cr.Firing, ShouldBeTrue)
+			})
+		})
+
+		Convey("Should fail on invalid query params", func() {
+			for _, params := range []string{`[]`, `["A"]`, `["A", "5m", 1]`} {
+				jsonModel, err := simplejson.NewJson([]byte(`{
+					"type": "query",
+					"query": {"params": ` + params + `, "datasourceId": 1, "model": {}},
+					"reducer": {"type": "avg"},
+					"evaluator": {"type": "gt", "params": [100]}
+				}`))
+				So(err, ShouldBeNil)
+
+				_, err = newQueryCondition(jsonModel, 1)
+				So(err, ShouldHaveSameTypeAs, alerting.ValidationError{})
+				So(err.Error(), ShouldContainSubstring, "error in condition 1")
+			}
+		})
 	})
 }
 
@@ -168,6 +250,7 @@ type queryConditionTestContext struct {
 	reducer   string
 	evaluator string
 	series    tsdb.TimeSeriesSlice
+	baselines []tsdb.TimeSeriesSlice
 	frame     *data.Frame
 	result    *alerting.EvalContext
 	condition *QueryCondition
@@ -207,7 +290,23 @@ func (ctx *queryConditionTestContext) exec() (*alerting.ConditionResult, error)
 		}
 	}
 
+	season := 0
 	condition.HandleRequest = func(context context.Context, dsInfo *models.DataSource, req *tsdb.TsdbQuery) (*tsdb.Response, error) {
+		// the baseline of anomaly evaluators is queried season by season with absolute time ranges
+		if req.TimeRange.From != condition.Query.From {
+			var baseline tsdb.TimeSeriesSlice
+			if season < len(ctx.baselines) {
+				baseline = ctx.baselines[season]
+			}
+			season++
+
+			return &tsdb.Response{
+				Results: map[string]*tsdb.QueryResult{
+					"A": {Series: baseline},
+				},
+			}, nil
+		}
+
 		return &tsdb.Response{
 			Results: map[string]*tsdb.QueryResult{
 				"A": qr,
@@ -1,20 +1,49 @@
 package conditions
 
 import (
+	"encoding/json"
+	"fmt"
 	"math"
+	"strconv"
+	"strings"
 
 	"sort"
 
 	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/tsdb"
 )
 
+var reducerTypes = []string{
+	"avg",
+	"sum",
+	"min",
+	"max",
+	"count",
+	"last",
+	"median",
+	"diff",
+	"diff_abs",
+	"percent_diff",
+	"percent_diff_abs",
+	"count_non_null",
+	"percentile",
+	"stddev",
+	"variance",
+	"rate",
+	"increase",
+}
+
 // queryReducer reduces an timeserie to a nullable float
 type queryReducer struct {
 
 	// Type is how the timeserie should be reduced.
 	// Ex avg, sum, max, min, count
 	Type string
+
+	// Percentile is the percentile (0-100) used by the
+	// percentile reducer. Ex 95 for p95
+	Percentile float64
 }
 
 func (s *queryReducer) Reduce(series *tsdb.TimeSeries) null.Float {
@@ -112,6 +141,34 @@ func (s *queryReducer) Reduce(series *tsdb.TimeSeries) null.Float {
 		if value > 0 {
 			allNull = false
 		}
+	case "percentile":
+		values := validValues(series)
+		if len(values) > 0 {
+			allNull = false
+			value = percentile(values, s.Percentile)
+		}
+	case "stddev":
+		values := validValues(series)
+		if len(values) > 0 {
+			allNull = false
+			value = math.Sqrt(variance(values))
+		}
+	case "variance":
+		values := validValues(series)
+		if len(values) > 0 {
+			allNull = false
+			value = variance(values)
+		}
+	case "increase":
+		var ok bool
+		value, _, ok = calculateIncrease(series)
+		allNull = !ok
+	case "rate":
+		increase, seconds, ok := calculateIncrease(series)
+		if ok && seconds > 0 {
+			allNull = false
+			value = increase / seconds
+		}
 	}
 
 	if allNull {
@@ -125,6 +182,142 @@ func newSimpleReducer(t string) *queryReducer {
 	return &queryReducer{Type: t}
 }
 
+// newQueryReducer creates a validated `queryReducer` from the
+// reducer part of the condition json model. Percentiles can either
+// be specified as `{"type": "percentile", "params": [95]}` or using
+// the shorthand `{"type": "p95"}`.
+func newQueryReducer(model *simplejson.Json) (*queryReducer, error) {
+	typ := model.Get("type").MustString()
+	if typ == "" {
+		return nil, fmt.Errorf("Reducer missing type property")
+	}
+
+	if strings.HasPrefix(typ, "p") && typ != "percent_diff" && typ != "percent_diff_abs" && typ != "percentile" {
+		p, err := strconv.ParseFloat(strings.TrimPrefix(typ, "p"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("Reducer invalid reducer type: %s", typ)
+		}
+		return newPercentileReducer(p)
+	}
+
+	if !inSlice(typ, reducerTypes) {
+		return nil, fmt.Errorf("Reducer invalid reducer type: %s", typ)
+	}
+
+	if typ == "percentile" {
+		params := model.Get("params").MustArray()
+		if len(params) == 0 || params[0] == nil {
+			return nil, fmt.Errorf("Reducer 'percentile' is missing the percentile parameter")
+		}
+
+		var p float64
+		var err error
+		switch v := params[0].(type) {
+		case json.Number:
+			p, err = v.Float64()
+		case string:
+			p, err = strconv.ParseFloat(v, 64)
+		default:
+			err = fmt.Errorf("unexpected type %T", v)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Reducer has invalid percentile parameter")
+		}
+		return newPercentileReducer(p)
+	}
+
+	return newSimpleReducer(typ), nil
+}
+
+func newPercentileReducer(p float64) (*queryReducer, error) {
+	if math.IsNaN(p) || p < 0 || p > 100 {
+		return nil, fmt.Errorf("Reducer percentile must be between 0 and 100, got %v", p)
+	}
+	return &queryReducer{Type: "percentile", Percentile: p}, nil
+}
+
+func validValues(series *tsdb.TimeSeries) []float64 {
+	values := make([]float64, 0, len(series.Points))
+	for _, point := range series.Points {
+		if isValid(point[0]) {
+			values = append(values, point[0].Float64)
+		}
+	}
+	return values
+}
+
+// percentile returns the p-th percentile of values using linear
+// interpolation between the closest ranks. values must not be empty.
+func percentile(values []float64, p float64) float64 {
+	sort.Float64s(values)
+	if len(values) == 1 {
+		return values[0]
+	}
+
+	rank := p / 100 * float64(len(values)-1)
+	lower := math.Floor(rank)
+	upper := math.Ceil(rank)
+	if lower == upper {
+		return values[int(rank)]
+	}
+
+	return values[int(lower)] + (values[int(upper)]-values[int(lower)])*(rank-lower)
+}
+
+// variance returns the population variance of values.
+// values must not be empty.
+func variance(values []float64) float64 {
+	mean := float64(0)
+	for _, v := range values {
+		mean += v
+	}
+	mean = mean / float64(len(values))
+
+	sum := float64(0)
+	for _, v := range values {
+		sum += (v - mean) * (v - mean)
+	}
+	return sum / float64(len(values))
+}
+
+// calculateIncrease returns the increase of a counter between the oldest and the
+// newest valid point together with the elapsed time in seconds. A decrease between
+// two points is treated as a counter reset, in which case the counter is assumed to
+// have started again from zero. ok is false when less than two valid points exist.
+func calculateIncrease(series *tsdb.TimeSeries) (increase float64, seconds float64, ok bool) {
+	var (
+		prev      float64
+		firstTime float64
+		lastTime  float64
+		count     int
+	)
+
+	for _, point := range series.Points {
+		if !isValid(point[0]) || !point[1].Valid {
+			continue
+		}
+
+		current := point[0].Float64
+		if count == 0 {
+			firstTime = point[1].Float64
+		} else if current < prev {
+			increase += current
+		} else {
+			increase += current - prev
+		}
+
+		prev = current
+		lastTime = point[1].Float64
+		count++
+	}
+
+	if count < 2 {
+		return 0, 0, false
+	}
+
+	return increase, (lastTime - firstTime) / 1000, true
+}
+
 func calculateDiff(series *tsdb.TimeSeries, allNull bool, value float64, fn func(float64, float64) float64) (bool, float64) {
 	var (
 		points = series.Points
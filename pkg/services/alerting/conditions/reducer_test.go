@@ -5,8 +5,10 @@ import (
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/tsdb"
 )
 
@@ -398,6 +400,97 @@ func TestSimpleReducer(t *testing.T) {
 	})
 }
 
+func TestStatisticalReducers(t *testing.T) {
+	nan := math.NaN()
+
+	tcs := []struct {
+		name     string
+		reducer  *queryReducer
+		values   []float64
+		expected float64
+		valid    bool
+	}{
+		{name: "p50 of odd amount of numbers", reducer: &queryReducer{Type: "percentile", Percentile: 50}, values: []float64{3, 1, 2}, expected: 2, valid: true},
+		{name: "p50 interpolates", reducer: &queryReducer{Type: "percentile", Percentile: 50}, values: []float64{1, 2, 3, 4}, expected: 2.5, valid: true},
+		{name: "p95", reducer: &queryReducer{Type: "percentile", Percentile: 95}, values: []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21}, expected: 20, valid: true},
+		{name: "p0 is min", reducer: &queryReducer{Type: "percentile", Percentile: 0}, values: []float64{5, 1, 9}, expected: 1, valid: true},
+		{name: "p100 is max", reducer: &queryReducer{Type: "percentile", Percentile: 100}, values: []float64{5, 1, 9}, expected: 9, valid: true},
+		{name: "percentile of one value", reducer: &queryReducer{Type: "percentile", Percentile: 99}, values: []float64{7}, expected: 7, valid: true},
+		{name: "percentile ignores NaN", reducer: &queryReducer{Type: "percentile", Percentile: 100}, values: []float64{1, nan, 3}, expected: 3, valid: true},
+		{name: "percentile with only NaN", reducer: &queryReducer{Type: "percentile", Percentile: 50}, values: []float64{nan, nan}, valid: false},
+		{name: "stddev", reducer: newSimpleReducer("stddev"), values: []float64{2, 4, 4, 4, 5, 5, 7, 9}, expected: 2, valid: true},
+		{name: "stddev of one value", reducer: newSimpleReducer("stddev"), values: []float64{42}, expected: 0, valid: true},
+		{name: "stddev with only NaN", reducer: newSimpleReducer("stddev"), values: []float64{nan}, valid: false},
+		{name: "variance", reducer: newSimpleReducer("variance"), values: []float64{2, 4, 4, 4, 5, 5, 7, 9}, expected: 4, valid: true},
+		{name: "variance ignores NaN", reducer: newSimpleReducer("variance"), values: []float64{1, nan, 3}, expected: 1, valid: true},
+		{name: "increase", reducer: newSimpleReducer("increase"), values: []float64{10, 20, 35}, expected: 25, valid: true},
+		{name: "increase with counter reset", reducer: newSimpleReducer("increase"), values: []float64{10, 20, 5, 15}, expected: 25, valid: true},
+		{name: "increase of one value", reducer: newSimpleReducer("increase"), values: []float64{10}, valid: false},
+		{name: "rate", reducer: newSimpleReducer("rate"), values: []float64{0, 10, 20}, expected: 1, valid: true},
+		{name: "rate with counter reset", reducer: newSimpleReducer("rate"), values: []float64{100, 110, 10, 20}, expected: 1, valid: true},
+		{name: "rate ignores NaN", reducer: newSimpleReducer("rate"), values: []float64{0, nan, 20}, expected: 1, valid: true},
+		{name: "rate of one value", reducer: newSimpleReducer("rate"), values: []float64{10}, valid: false},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			series := &tsdb.TimeSeries{Name: "test time series"}
+			for i, v := range tc.values {
+				// points are 10 seconds apart
+				series.Points = append(series.Points, tsdb.NewTimePoint(null.FloatFrom(v), float64(i*10000)))
+			}
+
+			result := tc.reducer.Reduce(series)
+			require.Equal(t, tc.valid, result.Valid)
+			if tc.valid {
+				require.InDelta(t, tc.expected, result.Float64, 1e-9)
+			}
+		})
+	}
+}
+
+func TestNewQueryReducer(t *testing.T) {
+	tcs := []struct {
+		model      string
+		typ        string
+		percentile float64
+		err        string
+	}{
+		{model: `{"type": "avg"}`, typ: "avg"},
+		{model: `{"type": "count_non_null", "params": []}`, typ: "count_non_null"},
+		{model: `{"type": "stddev"}`, typ: "stddev"},
+		{model: `{"type": "variance"}`, typ: "variance"},
+		{model: `{"type": "rate"}`, typ: "rate"},
+		{model: `{"type": "increase"}`, typ: "increase"},
+		{model: `{"type": "percent_diff"}`, typ: "percent_diff"},
+		{model: `{"type": "percentile", "params": [95]}`, typ: "percentile", percentile: 95},
+		{model: `{"type": "percentile", "params": ["99.9"]}`, typ: "percentile", percentile: 99.9},
+		{model: `{"type": "p90"}`, typ: "percentile", percentile: 90},
+		{model: `{"type": "percentile"}`, err: "Reducer 'percentile' is missing the percentile parameter"},
+		{model: `{"type": "percentile", "params": [101]}`, err: "Reducer percentile must be between 0 and 100, got 101"},
+		{model: `{"type": "pfoo"}`, err: "Reducer invalid reducer type: pfoo"},
+		{model: `{"type": "foo"}`, err: "Reducer invalid reducer type: foo"},
+		{model: `{}`, err: "Reducer missing type property"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.model, func(t *testing.T) {
+			model, err := simplejson.NewJson([]byte(tc.model))
+			require.NoError(t, err)
+
+			reducer, err := newQueryReducer(model)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.typ, reducer.Type)
+			require.Equal(t, tc.percentile, reducer.Percentile)
+		})
+	}
+}
+
 func testReducer(reducerType string, datapoints ...float64) float64 {
 	reducer := newSimpleReducer(reducerType)
 	series := &tsdb.TimeSeries{
@@ -63,6 +63,12 @@ func (e *AlertEngine) Run(ctx context.Context) error {
 	alertGroup.Go(func() error { return e.runJobDispatcher(ctx) })
 
 	err := alertGroup.Wait()
+
+	// notifications collected in notification groups would be lost on shutdown
+	if handler, ok := e.resultHandler.(*defaultResultHandler); ok {
+		handler.flushNotifications()
+	}
+
 	return err
 }
 
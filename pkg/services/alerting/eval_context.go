@@ -32,6 +32,22 @@ type EvalContext struct {
 	NoDataFound     bool
 	PrevAlertState  models.AlertStateType
 
+	// Instance is set when the context describes a state change of
+	// a single series of a multi-dimensional alert rule.
+	Instance *models.AlertInstance
+
+	// Silence is set when notifications for this evaluation
+	// were suppressed by an active alert silence.
+	Silence *models.AlertSilence
+
+	// Grouped holds the evaluations summarized by a digest
+	// notification of a channel with a group interval.
+	Grouped []*EvalContext
+
+	// NotificationResults holds the outcome of the notification
+	// channels notified for this evaluation.
+	NotificationResults []*NotificationResult
+
 	Ctx context.Context
 }
 
@@ -94,6 +110,9 @@ func (c *EvalContext) GetDurationMs() float64 {
 
 // GetNotificationTitle returns the title of the alert rule including alert state.
 func (c *EvalContext) GetNotificationTitle() string {
+	if c.Instance != nil {
+		return "[" + c.GetStateModel().Text + "] " + c.Rule.Name + " " + formatInstanceLabels(c.Instance)
+	}
 	return "[" + c.GetStateModel().Text + "] " + c.Rule.Name
 }
 
@@ -120,6 +139,11 @@ func (c *EvalContext) GetRuleURL() (string, error) {
 		return setting.AppUrl, nil
 	}
 
+	// standalone alert rules have no panel to link to
+	if c.Rule.DashboardID == 0 {
+		return setting.AppUrl + "alerting/list", nil
+	}
+
 	ref, err := c.GetDashboardUID()
 	if err != nil {
 		return "", err
@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/models"
 )
 
@@ -204,3 +205,30 @@ func TestGetStateFromEvalContext(t *testing.T) {
 		assert.Equal(t, tc.expected, newState, "failed: %s \n expected '%s' have '%s'\n", tc.name, tc.expected, string(newState))
 	}
 }
+
+func TestPendingStateIsRestoredFromDB(t *testing.T) {
+	RegisterCondition("test", func(model *simplejson.Json, index int) (Condition, error) {
+		return &FakeCondition{}, nil
+	})
+
+	// the state date of a pending alert is when it entered the pending state
+	pendingSince := time.Now().Add(-time.Minute * 10)
+	rule, err := NewRuleFromDBAlert(&models.Alert{
+		Id:           1,
+		State:        models.AlertStatePending,
+		NewStateDate: pendingSince,
+		For:          time.Minute * 5,
+		Settings: simplejson.NewFromAny(map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "test"},
+			},
+		}),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, pendingSince, rule.LastStateChange)
+
+	evalContext := NewEvalContext(context.Background(), rule)
+	evalContext.Firing = true
+
+	assert.Equal(t, models.AlertStateAlerting, evalContext.GetNewState())
+}
@@ -30,8 +30,14 @@ func NewDashAlertExtractor(dash *models.Dashboard, orgID int64, user *models.Sig
 }
 
 func (e *DashAlertExtractor) lookupDatasourceID(dsName string) (*models.DataSource, error) {
+	return lookupDatasource(e.OrgID, dsName)
+}
+
+// lookupDatasource returns the data source with the name
+// or the default data source if the name is empty.
+func lookupDatasource(orgID int64, dsName string) (*models.DataSource, error) {
 	if dsName == "" {
-		query := &models.GetDataSourcesQuery{OrgId: e.OrgID}
+		query := &models.GetDataSourcesQuery{OrgId: orgID}
 		if err := bus.Dispatch(query); err != nil {
 			return nil, err
 		}
@@ -42,7 +48,7 @@ func (e *DashAlertExtractor) lookupDatasourceID(dsName string) (*models.DataSour
 			}
 		}
 	} else {
-		query := &models.GetDataSourceByNameQuery{Name: dsName, OrgId: e.OrgID}
+		query := &models.GetDataSourceByNameQuery{Name: dsName, OrgId: orgID}
 		if err := bus.Dispatch(query); err != nil {
 			return nil, err
 		}
@@ -64,6 +70,22 @@ func findPanelQueryByRefID(panel *simplejson.Json, refID string) *simplejson.Jso
 	return nil
 }
 
+// getConditionQueries returns the queries of an alert condition. Math
+// conditions hold several queries, the other conditions a single one.
+func getConditionQueries(jsonCondition *simplejson.Json) []*simplejson.Json {
+	queries := jsonCondition.Get("queries").MustArray()
+	if len(queries) == 0 {
+		return []*simplejson.Json{jsonCondition.Get("query")}
+	}
+
+	result := make([]*simplejson.Json, 0, len(queries))
+	for _, query := range queries {
+		result = append(result, simplejson.NewFromAny(query).Get("query"))
+	}
+
+	return result
+}
+
 func copyJSON(in *simplejson.Json) (*simplejson.Json, error) {
 	rawJSON, err := in.MarshalJSON()
 	if err != nil {
@@ -137,52 +159,51 @@ func (e *DashAlertExtractor) getAlertFromPanels(jsonWithPanels *simplejson.Json,
 		}
 
 		for _, condition := range jsonAlert.Get("conditions").MustArray() {
-			jsonCondition := simplejson.NewFromAny(condition)
-
-			jsonQuery := jsonCondition.Get("query")
-			queryRefID := jsonQuery.Get("params").MustArray()[0].(string)
-			panelQuery := findPanelQueryByRefID(panel, queryRefID)
+			for _, jsonQuery := range getConditionQueries(simplejson.NewFromAny(condition)) {
+				queryRefID := jsonQuery.Get("params").MustArray()[0].(string)
+				panelQuery := findPanelQueryByRefID(panel, queryRefID)
 
-			if panelQuery == nil {
-				reason := fmt.Sprintf("Alert on PanelId: %v refers to query(%s) that cannot be found", alert.PanelId, queryRefID)
-				return nil, ValidationError{Reason: reason}
-			}
-
-			dsName := ""
-			if panelQuery.Get("datasource").MustString() != "" {
-				dsName = panelQuery.Get("datasource").MustString()
-			} else if panel.Get("datasource").MustString() != "" {
-				dsName = panel.Get("datasource").MustString()
-			}
+				if panelQuery == nil {
+					reason := fmt.Sprintf("Alert on PanelId: %v refers to query(%s) that cannot be found", alert.PanelId, queryRefID)
+					return nil, ValidationError{Reason: reason}
+				}
 
-			datasource, err := e.lookupDatasourceID(dsName)
-			if err != nil {
-				e.log.Debug("Error looking up datasource", "error", err)
-				return nil, ValidationError{Reason: fmt.Sprintf("Data source used by alert rule not found, alertName=%v, datasource=%s", alert.Name, dsName)}
-			}
+				dsName := ""
+				if panelQuery.Get("datasource").MustString() != "" {
+					dsName = panelQuery.Get("datasource").MustString()
+				} else if panel.Get("datasource").MustString() != "" {
+					dsName = panel.Get("datasource").MustString()
+				}
 
-			dsFilterQuery := models.DatasourcesPermissionFilterQuery{
-				User:        e.User,
-				Datasources: []*models.DataSource{datasource},
-			}
+				datasource, err := e.lookupDatasourceID(dsName)
+				if err != nil {
+					e.log.Debug("Error looking up datasource", "error", err)
+					return nil, ValidationError{Reason: fmt.Sprintf("Data source used by alert rule not found, alertName=%v, datasource=%s", alert.Name, dsName)}
+				}
 
-			if err := bus.Dispatch(&dsFilterQuery); err != nil {
-				if err != bus.ErrHandlerNotFound {
-					return nil, err
+				dsFilterQuery := models.DatasourcesPermissionFilterQuery{
+					User:        e.User,
+					Datasources: []*models.DataSource{datasource},
 				}
-			} else {
-				if len(dsFilterQuery.Result) == 0 {
-					return nil, models.ErrDataSourceAccessDenied
+
+				if err := bus.Dispatch(&dsFilterQuery); err != nil {
+					if err != bus.ErrHandlerNotFound {
+						return nil, err
+					}
+				} else {
+					if len(dsFilterQuery.Result) == 0 {
+						return nil, models.ErrDataSourceAccessDenied
+					}
 				}
-			}
 
-			jsonQuery.SetPath([]string{"datasourceId"}, datasource.Id)
+				jsonQuery.SetPath([]string{"datasourceId"}, datasource.Id)
 
-			if interval, err := panel.Get("interval").String(); err == nil {
-				panelQuery.Set("interval", interval)
-			}
+				if interval, err := panel.Get("interval").String(); err == nil {
+					panelQuery.Set("interval", interval)
+				}
 
-			jsonQuery.Set("model", panelQuery.Interface())
+				jsonQuery.Set("model", panelQuery.Interface())
+			}
 		}
 
 		alert.Settings = jsonAlert
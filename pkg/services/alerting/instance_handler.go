@@ -0,0 +1,200 @@
+package alerting
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/annotations"
+)
+
+// instanceTransition is a state change of a single series
+// of a multi-dimensional alert rule.
+type instanceTransition struct {
+	metric       string
+	labels       map[string]string
+	prevState    models.AlertStateType
+	newState     models.AlertStateType
+	stateChanges int64
+	matches      []*EvalMatch
+}
+
+// handleInstances computes, persists and notifies the state changes of the
+// alert instances of a multi-dimensional rule. It returns false when the rule
+// state does not describe single series (no data, execution errors) and the
+// rule level notifications should be used instead.
+func (handler *defaultResultHandler) handleInstances(evalContext *EvalContext) bool {
+	ruleState := evalContext.Rule.State
+	if evalContext.IsTestRun || evalContext.Error != nil {
+		return false
+	}
+
+	if ruleState != models.AlertStateAlerting && ruleState != models.AlertStateOK && ruleState != models.AlertStatePending {
+		return false
+	}
+
+	// wait for the rule to leave pending before instances change state
+	if ruleState == models.AlertStatePending {
+		return true
+	}
+
+	query := &models.GetAlertInstancesQuery{OrgId: evalContext.Rule.OrgID, AlertId: evalContext.Rule.ID}
+	if err := bus.Dispatch(query); err != nil {
+		handler.log.Error("Failed to get alert instances", "ruleId", evalContext.Rule.ID, "error", err)
+		return true
+	}
+
+	for _, t := range getInstanceTransitions(evalContext, query.Result) {
+		handler.handleInstanceTransition(evalContext, t)
+	}
+
+	return true
+}
+
+func getInstanceTransitions(evalContext *EvalContext, existing []*models.AlertInstance) []*instanceTransition {
+	firing := map[string]*instanceTransition{}
+	var hashes []string
+
+	if evalContext.Rule.State == models.AlertStateAlerting {
+		for _, match := range evalContext.EvalMatches {
+			if match.Metric == "NoData" && len(match.Tags) == 0 {
+				continue
+			}
+
+			hash := models.GetAlertInstanceLabelsHash(match.Metric, match.Tags)
+			if t, ok := firing[hash]; ok {
+				t.matches = append(t.matches, match)
+				continue
+			}
+
+			firing[hash] = &instanceTransition{
+				metric:    match.Metric,
+				labels:    match.Tags,
+				prevState: models.AlertStateUnknown,
+				newState:  models.AlertStateAlerting,
+				matches:   []*EvalMatch{match},
+			}
+			hashes = append(hashes, hash)
+		}
+	}
+
+	var result []*instanceTransition
+	for _, instance := range existing {
+		if t, ok := firing[instance.LabelsHash]; ok {
+			t.prevState = instance.State
+			t.stateChanges = instance.StateChanges
+			continue
+		}
+
+		if instance.State == models.AlertStateAlerting {
+			result = append(result, &instanceTransition{
+				metric:       instance.Metric,
+				labels:       instance.Labels,
+				prevState:    instance.State,
+				newState:     models.AlertStateOK,
+				stateChanges: instance.StateChanges,
+			})
+		}
+	}
+
+	for _, hash := range hashes {
+		if t := firing[hash]; t.prevState != t.newState {
+			result = append(result, t)
+		}
+	}
+
+	return result
+}
+
+func (handler *defaultResultHandler) handleInstanceTransition(evalContext *EvalContext, t *instanceTransition) {
+	evalData := simplejson.New()
+	if len(t.matches) > 0 {
+		evalData.Set("evalMatches", simplejson.NewFromAny(t.matches))
+	}
+
+	cmd := &models.SetAlertInstanceStateCommand{
+		OrgId:        evalContext.Rule.OrgID,
+		AlertId:      evalContext.Rule.ID,
+		Metric:       t.metric,
+		Labels:       t.labels,
+		State:        t.newState,
+		EvalData:     evalData,
+		StateChanges: t.stateChanges,
+	}
+
+	if err := bus.Dispatch(cmd); err != nil {
+		if err == models.ErrAlertInstanceStateVersionConflict || err == models.ErrRequiresNewState {
+			handler.log.Debug("Alert instance already updated", "ruleId", evalContext.Rule.ID, "metric", t.metric)
+			return
+		}
+
+		handler.log.Error("Failed to save alert instance state", "ruleId", evalContext.Rule.ID, "metric", t.metric, "error", err)
+		return
+	}
+
+	handler.log.Info("New alert instance state change", "ruleId", evalContext.Rule.ID, "metric", t.metric, "newState", t.newState, "prev state", t.prevState)
+
+	annotationData := simplejson.New()
+	annotationData.Set("instance", map[string]interface{}{"metric": t.metric, "labels": t.labels})
+	if len(t.matches) > 0 {
+		annotationData.Set("evalMatches", simplejson.NewFromAny(t.matches))
+	}
+
+	item := annotations.Item{
+		OrgId:       evalContext.Rule.OrgID,
+		DashboardId: evalContext.Rule.DashboardID,
+		PanelId:     evalContext.Rule.PanelID,
+		AlertId:     evalContext.Rule.ID,
+		Text:        "",
+		NewState:    string(t.newState),
+		PrevState:   string(t.prevState),
+		Epoch:       time.Now().UnixNano() / int64(time.Millisecond),
+		Data:        annotationData,
+	}
+
+	instanceCtx := newInstanceEvalContext(evalContext, cmd.Result, t.prevState, t.matches)
+	handler.sendIfNeeded(instanceCtx)
+	handler.saveAnnotation(instanceCtx, &item)
+	handler.saveStateHistory(instanceCtx, t.metric, t.labels, "")
+
+	// reuse the rendered panel image for the other instances
+	evalContext.ImagePublicURL = instanceCtx.ImagePublicURL
+	evalContext.ImageOnDiskPath = instanceCtx.ImageOnDiskPath
+}
+
+// newInstanceEvalContext returns a copy of the evaluation context describing
+// the state change of a single alert instance.
+func newInstanceEvalContext(evalContext *EvalContext, instance *models.AlertInstance, prevState models.AlertStateType, matches []*EvalMatch) *EvalContext {
+	rule := *evalContext.Rule
+	rule.State = instance.State
+
+	instanceCtx := *evalContext
+	instanceCtx.Rule = &rule
+	instanceCtx.PrevAlertState = prevState
+	instanceCtx.Firing = instance.State == models.AlertStateAlerting
+	instanceCtx.EvalMatches = matches
+	instanceCtx.Instance = instance
+	instanceCtx.NotificationResults = nil
+	if instanceCtx.EvalMatches == nil {
+		instanceCtx.EvalMatches = make([]*EvalMatch, 0)
+	}
+
+	return &instanceCtx
+}
+
+func formatInstanceLabels(instance *models.AlertInstance) string {
+	if len(instance.Labels) == 0 {
+		return "(" + instance.Metric + ")"
+	}
+
+	pairs := make([]string, 0, len(instance.Labels))
+	for k, v := range instance.Labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
@@ -0,0 +1,108 @@
+package alerting
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+func TestGetInstanceTransitions(t *testing.T) {
+	hostA := map[string]string{"host": "a"}
+	hostB := map[string]string{"host": "b"}
+
+	existingInstance := func(labels map[string]string, state models.AlertStateType) *models.AlertInstance {
+		return &models.AlertInstance{
+			Metric:       "cpu",
+			Labels:       labels,
+			LabelsHash:   models.GetAlertInstanceLabelsHash("cpu", labels),
+			State:        state,
+			StateChanges: 3,
+		}
+	}
+
+	newContext := func(state models.AlertStateType, matches ...*EvalMatch) *EvalContext {
+		ctx := NewEvalContext(context.TODO(), &Rule{State: state, MultiDimensional: true})
+		ctx.EvalMatches = matches
+		return ctx
+	}
+
+	t.Run("new firing series becomes alerting", func(t *testing.T) {
+		ctx := newContext(models.AlertStateAlerting, &EvalMatch{Metric: "cpu", Tags: hostA, Value: null.FloatFrom(90)})
+
+		transitions := getInstanceTransitions(ctx, nil)
+
+		assert.Len(t, transitions, 1)
+		assert.Equal(t, hostA, transitions[0].labels)
+		assert.Equal(t, models.AlertStateUnknown, transitions[0].prevState)
+		assert.Equal(t, models.AlertStateAlerting, transitions[0].newState)
+		assert.Equal(t, int64(0), transitions[0].stateChanges)
+	})
+
+	t.Run("one series recovers while another starts firing", func(t *testing.T) {
+		ctx := newContext(models.AlertStateAlerting, &EvalMatch{Metric: "cpu", Tags: hostB, Value: null.FloatFrom(90)})
+
+		transitions := getInstanceTransitions(ctx, []*models.AlertInstance{existingInstance(hostA, models.AlertStateAlerting)})
+
+		assert.Len(t, transitions, 2)
+		assert.Equal(t, hostA, transitions[0].labels)
+		assert.Equal(t, models.AlertStateOK, transitions[0].newState)
+		assert.Equal(t, int64(3), transitions[0].stateChanges)
+		assert.Equal(t, hostB, transitions[1].labels)
+		assert.Equal(t, models.AlertStateAlerting, transitions[1].newState)
+	})
+
+	t.Run("series that keeps firing does not transition", func(t *testing.T) {
+		ctx := newContext(models.AlertStateAlerting, &EvalMatch{Metric: "cpu", Tags: hostA, Value: null.FloatFrom(90)})
+
+		transitions := getInstanceTransitions(ctx, []*models.AlertInstance{existingInstance(hostA, models.AlertStateAlerting)})
+
+		assert.Len(t, transitions, 0)
+	})
+
+	t.Run("previously ok series starts firing again", func(t *testing.T) {
+		ctx := newContext(models.AlertStateAlerting, &EvalMatch{Metric: "cpu", Tags: hostA, Value: null.FloatFrom(90)})
+
+		transitions := getInstanceTransitions(ctx, []*models.AlertInstance{existingInstance(hostA, models.AlertStateOK)})
+
+		assert.Len(t, transitions, 1)
+		assert.Equal(t, models.AlertStateOK, transitions[0].prevState)
+		assert.Equal(t, models.AlertStateAlerting, transitions[0].newState)
+		assert.Equal(t, int64(3), transitions[0].stateChanges)
+	})
+
+	t.Run("all series recover when rule is ok", func(t *testing.T) {
+		ctx := newContext(models.AlertStateOK)
+
+		transitions := getInstanceTransitions(ctx, []*models.AlertInstance{
+			existingInstance(hostA, models.AlertStateAlerting),
+			existingInstance(hostB, models.AlertStateOK),
+		})
+
+		assert.Len(t, transitions, 1)
+		assert.Equal(t, hostA, transitions[0].labels)
+		assert.Equal(t, models.AlertStateOK, transitions[0].newState)
+	})
+
+	t.Run("no data matches are ignored", func(t *testing.T) {
+		ctx := newContext(models.AlertStateAlerting, &EvalMatch{Metric: "NoData", Value: null.FloatFromPtr(nil)})
+
+		transitions := getInstanceTransitions(ctx, nil)
+
+		assert.Len(t, transitions, 0)
+	})
+}
+
+func TestInstanceNotificationTitle(t *testing.T) {
+	ctx := NewEvalContext(context.TODO(), &Rule{Name: "High CPU", State: models.AlertStateAlerting})
+	instance := &models.AlertInstance{Metric: "cpu", Labels: map[string]string{"host": "a", "dc": "eu"}, State: models.AlertStateOK}
+
+	instanceCtx := newInstanceEvalContext(ctx, instance, models.AlertStateAlerting, nil)
+
+	assert.Equal(t, "[OK] High CPU {dc=eu, host=a}", instanceCtx.GetNotificationTitle())
+	assert.Equal(t, models.AlertStateAlerting, instanceCtx.PrevAlertState)
+	assert.Equal(t, models.AlertStateAlerting, ctx.Rule.State)
+}
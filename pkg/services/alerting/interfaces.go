@@ -30,6 +30,15 @@ type Notifier interface {
 	GetSendReminder() bool
 	GetDisableResolveMessage() bool
 	GetFrequency() time.Duration
+
+	// GetGroupInterval returns how long notifications are collected
+	// into a digest before being sent, zero disables grouping.
+	GetGroupInterval() time.Duration
+	GetGroupBy() string
+
+	// GetRateLimit returns the maximum number of messages sent per interval,
+	// zero disables rate limiting.
+	GetRateLimit() (int, time.Duration)
 }
 
 type notifierState struct {
@@ -0,0 +1,285 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+const (
+	// GroupIntervalSetting is the notification setting holding how long
+	// notifications are collected before a digest is sent.
+	GroupIntervalSetting = "groupInterval"
+	// GroupBySetting is the notification setting holding the group key,
+	// either "dashboard" or "tag:<key>".
+	GroupBySetting = "groupBy"
+	// RateLimitMaxMessagesSetting is the notification setting holding the
+	// maximum number of messages sent per rate limit interval.
+	RateLimitMaxMessagesSetting = "rateLimitMaxMessages"
+	// RateLimitIntervalSetting is the notification setting holding the rate limit interval.
+	RateLimitIntervalSetting = "rateLimitInterval"
+
+	groupByDashboard = "dashboard"
+	groupByTagPrefix = "tag:"
+)
+
+// NotificationSendSettings controls how notifications of a channel are
+// grouped into digests and rate limited.
+type NotificationSendSettings struct {
+	GroupInterval     time.Duration
+	GroupBy           string
+	MaxMessages       int
+	RateLimitInterval time.Duration
+}
+
+// ParseNotificationSendSettings reads the grouping and rate
+// limit options of the notification settings.
+func ParseNotificationSendSettings(settings *simplejson.Json) (*NotificationSendSettings, error) {
+	result := &NotificationSendSettings{}
+	if settings == nil {
+		return result, nil
+	}
+
+	var err error
+	if result.GroupInterval, err = parseSettingDuration(settings, GroupIntervalSetting); err != nil {
+		return nil, err
+	}
+
+	result.GroupBy = settings.Get(GroupBySetting).MustString()
+	if result.GroupBy != "" && result.GroupBy != groupByDashboard && !strings.HasPrefix(result.GroupBy, groupByTagPrefix) {
+		return nil, fmt.Errorf("Invalid %s %q, must be %q or %q", GroupBySetting, result.GroupBy, groupByDashboard, groupByTagPrefix+"<key>")
+	}
+
+	if result.GroupBy == groupByTagPrefix {
+		return nil, fmt.Errorf("Invalid %s, tag key is missing", GroupBySetting)
+	}
+
+	if value, exist := settings.CheckGet(RateLimitMaxMessagesSetting); exist {
+		// the frontend stores numbers from text inputs as strings
+		maxMessages, err := value.Int()
+		if err != nil {
+			maxMessages, err = strconv.Atoi(value.MustString())
+		}
+		if err != nil || maxMessages < 0 {
+			return nil, fmt.Errorf("Invalid %s, must be a positive number", RateLimitMaxMessagesSetting)
+		}
+		result.MaxMessages = maxMessages
+	}
+
+	if result.RateLimitInterval, err = parseSettingDuration(settings, RateLimitIntervalSetting); err != nil {
+		return nil, err
+	}
+
+	if result.MaxMessages > 0 && result.RateLimitInterval == 0 {
+		return nil, fmt.Errorf("%s is required when %s is set", RateLimitIntervalSetting, RateLimitMaxMessagesSetting)
+	}
+
+	return result, nil
+}
+
+func parseSettingDuration(settings *simplejson.Json, key string) (time.Duration, error) {
+	text := settings.Get(key).MustString()
+	if text == "" {
+		return 0, nil
+	}
+
+	duration, err := time.ParseDuration(text)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("Invalid %s %q, must be a duration like 1m", key, text)
+	}
+
+	return duration, nil
+}
+
+// getNotificationGroupKey returns the key of the group the evaluation
+// belongs to for a notification channel.
+func getNotificationGroupKey(evalContext *EvalContext, notifier Notifier) string {
+	key := fmt.Sprintf("%d/%s", evalContext.Rule.OrgID, notifier.GetNotifierUID())
+
+	groupBy := notifier.GetGroupBy()
+	switch {
+	case groupBy == groupByDashboard:
+		key += fmt.Sprintf("/dashboard=%d", evalContext.Rule.DashboardID)
+	case strings.HasPrefix(groupBy, groupByTagPrefix):
+		tagKey := strings.TrimPrefix(groupBy, groupByTagPrefix)
+		for _, tag := range evalContext.Rule.AlertRuleTags {
+			if tag.Key == tagKey {
+				key += "/" + tagKey + "=" + tag.Value
+				break
+			}
+		}
+	}
+
+	// a digest has a single state, resolved alerts are not mixed with firing ones
+	if evalContext.Rule.State == models.AlertStateOK {
+		key += "/resolved"
+	}
+
+	return key
+}
+
+// notificationGroup collects the notifications of a channel
+// until its group interval has elapsed.
+type notificationGroup struct {
+	notifier Notifier
+	items    []*notifierStateContext
+	timer    *time.Timer
+}
+
+type notifierStateContext struct {
+	evalContext   *EvalContext
+	notifierState *notifierState
+}
+
+// notificationGrouper batches notifications by channel and group key.
+type notificationGrouper struct {
+	mu     sync.Mutex
+	groups map[string]*notificationGroup
+	flush  func(group *notificationGroup)
+}
+
+func newNotificationGrouper(flush func(group *notificationGroup)) *notificationGrouper {
+	return &notificationGrouper{
+		groups: make(map[string]*notificationGroup),
+		flush:  flush,
+	}
+}
+
+// add queues the notification in its group. The first notification of a group
+// schedules the group to be flushed after the group interval of the channel.
+func (g *notificationGrouper) add(evalContext *EvalContext, ns *notifierState) {
+	key := getNotificationGroupKey(evalContext, ns.notifier)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	group, exists := g.groups[key]
+	if !exists {
+		group = &notificationGroup{notifier: ns.notifier}
+		g.groups[key] = group
+
+		group.timer = time.AfterFunc(ns.notifier.GetGroupInterval(), func() {
+			g.mu.Lock()
+			delete(g.groups, key)
+			g.mu.Unlock()
+
+			g.flush(group)
+		})
+	}
+
+	group.items = append(group.items, &notifierStateContext{evalContext: evalContext, notifierState: ns})
+}
+
+// flushAll sends the notifications of all groups without waiting for their group interval,
+// so that collected notifications aren't lost when the alerting engine stops.
+func (g *notificationGrouper) flushAll() {
+	g.mu.Lock()
+	groups := make([]*notificationGroup, 0, len(g.groups))
+	for key, group := range g.groups {
+		// groups whose timer has already fired are being flushed
+		if group.timer.Stop() {
+			delete(g.groups, key)
+			groups = append(groups, group)
+		}
+	}
+	g.mu.Unlock()
+
+	for _, group := range groups {
+		g.flush(group)
+	}
+}
+
+// newDigestEvalContext returns an evaluation context summarizing several
+// alert notifications so that notifiers can send them as one message.
+func newDigestEvalContext(evalContexts []*EvalContext) *EvalContext {
+	first := evalContexts[0]
+	rule := *first.Rule
+	rule.Name = fmt.Sprintf("%d alerts", len(evalContexts))
+	rule.State = models.AlertStateOK
+
+	lines := make([]string, 0, len(evalContexts))
+	matches := make([]*EvalMatch, 0)
+	firing := false
+	for _, c := range evalContexts {
+		line := c.GetNotificationTitle()
+		if c.Rule.Message != "" {
+			line += ": " + c.Rule.Message
+		}
+		lines = append(lines, line)
+		matches = append(matches, c.EvalMatches...)
+		firing = firing || c.Firing
+
+		if stateSeverity(c.Rule.State) > stateSeverity(rule.State) {
+			rule.State = c.Rule.State
+		}
+	}
+	rule.Message = strings.Join(lines, "\n")
+
+	digest := NewEvalContext(context.Background(), &rule)
+	digest.PrevAlertState = first.PrevAlertState
+	digest.Firing = firing
+	digest.EvalMatches = matches
+	digest.dashboardRef = first.dashboardRef
+	digest.Grouped = evalContexts
+
+	return digest
+}
+
+func stateSeverity(state models.AlertStateType) int {
+	switch state {
+	case models.AlertStateAlerting:
+		return 3
+	case models.AlertStateNoData:
+		return 2
+	case models.AlertStatePending:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// notificationRateLimiter limits how many messages
+// a channel sends within its rate limit interval.
+type notificationRateLimiter struct {
+	mu    sync.Mutex
+	sends map[string][]time.Time
+}
+
+func newNotificationRateLimiter() *notificationRateLimiter {
+	return &notificationRateLimiter{sends: make(map[string][]time.Time)}
+}
+
+// allow returns true and records the send if the channel
+// has not reached its limit within the last interval.
+func (l *notificationRateLimiter) allow(orgID int64, notifier Notifier, now time.Time) bool {
+	maxMessages, interval := notifier.GetRateLimit()
+	if maxMessages <= 0 || interval <= 0 {
+		return true
+	}
+
+	key := fmt.Sprintf("%d/%s", orgID, notifier.GetNotifierUID())
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	recent := l.sends[key][:0]
+	for _, t := range l.sends[key] {
+		if now.Sub(t) < interval {
+			recent = append(recent, t)
+		}
+	}
+
+	if len(recent) >= maxMessages {
+		l.sends[key] = recent
+		return false
+	}
+
+	l.sends[key] = append(recent, now)
+	return true
+}
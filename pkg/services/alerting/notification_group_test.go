@@ -0,0 +1,188 @@
+package alerting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+func TestParseNotificationSendSettings(t *testing.T) {
+	tcs := []struct {
+		name     string
+		settings string
+		expected *NotificationSendSettings
+		err      bool
+	}{
+		{name: "empty", settings: `{}`, expected: &NotificationSendSettings{}},
+		{
+			name:     "grouping",
+			settings: `{"groupInterval": "1m", "groupBy": "tag:team"}`,
+			expected: &NotificationSendSettings{GroupInterval: time.Minute, GroupBy: "tag:team"},
+		},
+		{
+			name:     "rate limit",
+			settings: `{"rateLimitMaxMessages": 5, "rateLimitInterval": "10m"}`,
+			expected: &NotificationSendSettings{MaxMessages: 5, RateLimitInterval: 10 * time.Minute},
+		},
+		{
+			name:     "rate limit as string",
+			settings: `{"rateLimitMaxMessages": "5", "rateLimitInterval": "10m"}`,
+			expected: &NotificationSendSettings{MaxMessages: 5, RateLimitInterval: 10 * time.Minute},
+		},
+		{name: "invalid interval", settings: `{"groupInterval": "soon"}`, err: true},
+		{name: "invalid group by", settings: `{"groupBy": "panel"}`, err: true},
+		{name: "missing tag key", settings: `{"groupBy": "tag:"}`, err: true},
+		{name: "invalid max messages", settings: `{"rateLimitMaxMessages": "many", "rateLimitInterval": "1m"}`, err: true},
+		{name: "missing rate limit interval", settings: `{"rateLimitMaxMessages": 5}`, err: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			settings, err := simplejson.NewJson([]byte(tc.settings))
+			require.NoError(t, err)
+
+			result, err := ParseNotificationSendSettings(settings)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestGetNotificationGroupKey(t *testing.T) {
+	evalContext := NewEvalContext(context.Background(), &Rule{
+		OrgID:         1,
+		DashboardID:   2,
+		AlertRuleTags: []*models.Tag{{Key: "team", Value: "backend"}},
+	})
+
+	assert.Equal(t, "1/abc", getNotificationGroupKey(evalContext, &groupTestNotifier{uid: "abc"}))
+	assert.Equal(t, "1/abc/dashboard=2", getNotificationGroupKey(evalContext, &groupTestNotifier{uid: "abc", groupBy: "dashboard"}))
+	assert.Equal(t, "1/abc/team=backend", getNotificationGroupKey(evalContext, &groupTestNotifier{uid: "abc", groupBy: "tag:team"}))
+	assert.Equal(t, "1/abc", getNotificationGroupKey(evalContext, &groupTestNotifier{uid: "abc", groupBy: "tag:env"}))
+
+	evalContext.Rule.State = models.AlertStateOK
+	assert.Equal(t, "1/abc/dashboard=2/resolved", getNotificationGroupKey(evalContext, &groupTestNotifier{uid: "abc", groupBy: "dashboard"}))
+}
+
+func TestNotificationGrouper(t *testing.T) {
+	flushed := make(chan *notificationGroup, 2)
+	grouper := newNotificationGrouper(func(group *notificationGroup) {
+		flushed <- group
+	})
+
+	notifier := &groupTestNotifier{uid: "abc", groupInterval: 10 * time.Millisecond, groupBy: "dashboard"}
+	for _, dashboardID := range []int64{1, 1, 2} {
+		evalContext := NewEvalContext(context.Background(), &Rule{OrgID: 1, DashboardID: dashboardID})
+		grouper.add(evalContext, &notifierState{notifier: notifier})
+	}
+
+	sizes := map[int64]int{}
+	for i := 0; i < 2; i++ {
+		select {
+		case group := <-flushed:
+			sizes[group.items[0].evalContext.Rule.DashboardID] = len(group.items)
+		case <-time.After(time.Second):
+			t.Fatal("group was not flushed")
+		}
+	}
+
+	assert.Equal(t, map[int64]int{1: 2, 2: 1}, sizes)
+}
+
+func TestNotificationGrouperFlushAll(t *testing.T) {
+	var flushed []*notificationGroup
+	grouper := newNotificationGrouper(func(group *notificationGroup) {
+		flushed = append(flushed, group)
+	})
+
+	notifier := &groupTestNotifier{uid: "abc", groupInterval: time.Hour}
+	grouper.add(NewEvalContext(context.Background(), &Rule{OrgID: 1}), &notifierState{notifier: notifier})
+
+	grouper.flushAll()
+
+	require.Len(t, flushed, 1)
+	assert.Len(t, flushed[0].items, 1)
+	assert.Empty(t, grouper.groups)
+}
+
+func TestInitNotifierGrouping(t *testing.T) {
+	RegisterNotifier(&NotifierPlugin{Type: "test-digest", Factory: newTestNotifier, SupportsGrouping: true})
+	RegisterNotifier(&NotifierPlugin{Type: "test-incident", Factory: newTestNotifier})
+
+	settings := simplejson.NewFromAny(map[string]interface{}{"groupInterval": "1m"})
+
+	_, err := InitNotifier(&models.AlertNotification{Type: "test-digest", Settings: settings})
+	assert.NoError(t, err)
+
+	_, err = InitNotifier(&models.AlertNotification{Type: "test-incident", Settings: settings})
+	assert.EqualError(t, err, "Notification type test-incident does not support grouping notifications")
+
+	_, err = InitNotifier(&models.AlertNotification{Type: "test-incident", Settings: simplejson.New()})
+	assert.NoError(t, err)
+}
+
+func TestNewDigestEvalContext(t *testing.T) {
+	first := NewEvalContext(context.Background(), &Rule{Name: "cpu", Message: "cpu is high", State: models.AlertStateNoData})
+	second := NewEvalContext(context.Background(), &Rule{Name: "memory", State: models.AlertStateAlerting})
+	second.Firing = true
+	second.EvalMatches = []*EvalMatch{{Metric: "server1"}}
+
+	digest := newDigestEvalContext([]*EvalContext{first, second})
+
+	assert.Equal(t, "[Alerting] 2 alerts", digest.GetNotificationTitle())
+	assert.Equal(t, "[No Data] cpu: cpu is high\n[Alerting] memory", digest.Rule.Message)
+	assert.True(t, digest.Firing)
+	assert.Len(t, digest.EvalMatches, 1)
+	assert.Len(t, digest.Grouped, 2)
+	assert.Equal(t, "cpu", first.Rule.Name)
+}
+
+func TestNotificationRateLimiter(t *testing.T) {
+	limiter := newNotificationRateLimiter()
+	notifier := &groupTestNotifier{uid: "abc", maxMessages: 2, rateLimitInterval: time.Minute}
+	now := time.Now()
+
+	assert.True(t, limiter.allow(1, notifier, now))
+	assert.True(t, limiter.allow(1, notifier, now.Add(time.Second)))
+	assert.False(t, limiter.allow(1, notifier, now.Add(2*time.Second)))
+	assert.True(t, limiter.allow(2, notifier, now.Add(2*time.Second)), "limits are per organization")
+	assert.True(t, limiter.allow(1, notifier, now.Add(time.Minute+time.Second)))
+
+	assert.True(t, limiter.allow(1, &groupTestNotifier{uid: "unlimited"}, now))
+}
+
+type groupTestNotifier struct {
+	testNotifier
+	uid               string
+	groupInterval     time.Duration
+	groupBy           string
+	maxMessages       int
+	rateLimitInterval time.Duration
+}
+
+func (n *groupTestNotifier) GetNotifierUID() string {
+	return n.uid
+}
+
+func (n *groupTestNotifier) GetGroupInterval() time.Duration {
+	return n.groupInterval
+}
+
+func (n *groupTestNotifier) GetGroupBy() string {
+	return n.groupBy
+}
+
+func (n *groupTestNotifier) GetRateLimit() (int, time.Duration) {
+	return n.maxMessages, n.rateLimitInterval
+}
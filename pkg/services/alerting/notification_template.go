@@ -0,0 +1,104 @@
+package alerting
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+const (
+	// TitleTemplateSetting is the notification setting holding the title template.
+	TitleTemplateSetting = "titleTemplate"
+	// MessageTemplateSetting is the notification setting holding the message template.
+	MessageTemplateSetting = "messageTemplate"
+)
+
+// NotificationTemplateData is the data available in notification templates.
+type NotificationTemplateData struct {
+	// Title is the default notification title, ex "[Alerting] CPU usage".
+	Title       string
+	RuleID      int64
+	RuleName    string
+	Message     string
+	State       string
+	PrevState   string
+	RuleURL     string
+	ImageURL    string
+	Error       string
+	EvalMatches []*EvalMatch
+	Tags        map[string]string
+
+	// Alerts holds the data of each alert summarized by a digest notification.
+	Alerts []*NotificationTemplateData
+}
+
+// NewNotificationTemplateData returns the template data for an alert evaluation.
+func NewNotificationTemplateData(evalContext *EvalContext) *NotificationTemplateData {
+	data := &NotificationTemplateData{
+		Title:       evalContext.GetNotificationTitle(),
+		RuleID:      evalContext.Rule.ID,
+		RuleName:    evalContext.Rule.Name,
+		Message:     evalContext.Rule.Message,
+		State:       string(evalContext.Rule.State),
+		PrevState:   string(evalContext.PrevAlertState),
+		ImageURL:    evalContext.ImagePublicURL,
+		EvalMatches: evalContext.EvalMatches,
+		Tags:        make(map[string]string),
+	}
+
+	if ruleURL, err := evalContext.GetRuleURL(); err == nil {
+		data.RuleURL = ruleURL
+	}
+
+	if evalContext.Error != nil {
+		data.Error = evalContext.Error.Error()
+	}
+
+	for _, tag := range evalContext.Rule.AlertRuleTags {
+		data.Tags[tag.Key] = tag.Value
+	}
+
+	for _, grouped := range evalContext.Grouped {
+		data.Alerts = append(data.Alerts, NewNotificationTemplateData(grouped))
+	}
+
+	return data
+}
+
+// RenderNotificationTemplate renders a notification template for an alert evaluation.
+func RenderNotificationTemplate(text string, evalContext *EvalContext) (string, error) {
+	tmpl, err := template.New("notification").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, NewNotificationTemplateData(evalContext)); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// ValidateNotificationTemplates returns an error if the templates
+// of the notification settings cannot be parsed.
+func ValidateNotificationTemplates(settings *simplejson.Json) error {
+	if settings == nil {
+		return nil
+	}
+
+	for _, key := range []string{TitleTemplateSetting, MessageTemplateSetting} {
+		text := settings.Get(key).MustString()
+		if text == "" {
+			continue
+		}
+
+		if _, err := template.New(key).Parse(text); err != nil {
+			return fmt.Errorf("Invalid %s: %v", key, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package alerting
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+func TestRenderNotificationTemplate(t *testing.T) {
+	evalContext := NewEvalContext(context.Background(), &Rule{
+		ID:            1,
+		Name:          "CPU usage",
+		Message:       "CPU is too high",
+		State:         models.AlertStateAlerting,
+		AlertRuleTags: []*models.Tag{{Key: "team", Value: "backend"}},
+	})
+	evalContext.IsTestRun = true
+	evalContext.ImagePublicURL = "http://image.url/1.png"
+	evalContext.EvalMatches = []*EvalMatch{
+		{Metric: "server1", Value: null.FloatFrom(90)},
+		{Metric: "server2", Value: null.FloatFrom(95)},
+	}
+
+	tcs := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{name: "title", template: "{{.Title}}", expected: "[Alerting] CPU usage"},
+		{name: "rule and state", template: "{{.RuleName}} is {{.State}}", expected: "CPU usage is alerting"},
+		{name: "message", template: "{{.Message}}", expected: "CPU is too high"},
+		{name: "tags", template: "team {{.Tags.team}}", expected: "team backend"},
+		{name: "missing tag", template: "owner {{.Tags.owner}}", expected: "owner "},
+		{name: "image url", template: "{{.ImageURL}}", expected: "http://image.url/1.png"},
+		{
+			name:     "eval matches",
+			template: "{{range .EvalMatches}}{{.Metric}}={{.Value}} {{end}}",
+			expected: "server1=90.000 server2=95.000 ",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			text, err := RenderNotificationTemplate(tc.template, evalContext)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, text)
+		})
+	}
+
+	t.Run("invalid template", func(t *testing.T) {
+		_, err := RenderNotificationTemplate("{{.Title", evalContext)
+		require.Error(t, err)
+	})
+}
+
+func TestValidateNotificationTemplates(t *testing.T) {
+	settings := simplejson.New()
+	require.NoError(t, ValidateNotificationTemplates(settings))
+
+	settings.Set(TitleTemplateSetting, "{{.RuleName}}")
+	require.NoError(t, ValidateNotificationTemplates(settings))
+
+	settings.Set(MessageTemplateSetting, "{{range .EvalMatches}")
+	require.Error(t, ValidateNotificationTemplates(settings))
+}
+
+func TestNotificationPreview(t *testing.T) {
+	settings := simplejson.New()
+	settings.Set(TitleTemplateSetting, "{{.State}}: {{.RuleName}}")
+
+	cmd := &NotificationPreviewCommand{Name: "preview", Type: "email", Settings: settings}
+	require.NoError(t, handleNotificationPreviewCommand(cmd))
+	assert.Equal(t, "alerting: Test notification", cmd.Result.Title)
+	assert.Equal(t, "Someone is testing the alert notification within grafana.", cmd.Result.Message)
+}
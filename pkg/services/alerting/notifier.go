@@ -27,18 +27,53 @@ type NotifierPlugin struct {
 	Description     string          `json:"description"`
 	OptionsTemplate string          `json:"optionsTemplate"`
 	Factory         NotifierFactory `json:"-"`
+
+	// SupportsGrouping is set for notifiers that can send several alerts as one digest.
+	// Notifiers that create an incident per alert rule, e.g. PagerDuty, can't.
+	SupportsGrouping bool `json:"supportsGrouping"`
+}
+
+// Notification outcomes recorded in the alert state history.
+const (
+	NotificationStatusSent        = "sent"
+	NotificationStatusFailed      = "failed"
+	NotificationStatusSilenced    = "silenced"
+	NotificationStatusGrouped     = "grouped"
+	NotificationStatusRateLimited = "rate_limited"
+)
+
+// NotificationResult is the outcome of a notification channel for an evaluation.
+type NotificationResult struct {
+	UID    string `json:"uid"`
+	Type   string `json:"type"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+func addNotificationResult(evalContext *EvalContext, notifier Notifier, status string, err error) {
+	result := &NotificationResult{UID: notifier.GetNotifierUID(), Type: notifier.GetType(), Status: status}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	evalContext.NotificationResults = append(evalContext.NotificationResults, result)
 }
 
 func newNotificationService(renderService rendering.Service) *notificationService {
-	return &notificationService{
+	n := &notificationService{
 		log:           log.New("alerting.notifier"),
 		renderService: renderService,
+		rateLimiter:   newNotificationRateLimiter(),
 	}
+	n.grouper = newNotificationGrouper(n.sendGroup)
+	return n
 }
 
 type notificationService struct {
 	log           log.Logger
 	renderService rendering.Service
+	grouper       *notificationGrouper
+	rateLimiter   *notificationRateLimiter
 }
 
 func (n *notificationService) SendIfNeeded(evalCtx *EvalContext) error {
@@ -52,7 +87,28 @@ func (n *notificationService) SendIfNeeded(evalCtx *EvalContext) error {
 		return nil
 	}
 
-	if notifierStates.ShouldUploadImage() {
+	if !evalCtx.IsTestRun {
+		silence, err := getMatchingSilence(evalCtx)
+		if err != nil {
+			n.log.Error("Failed to get alert silences", "ruleId", evalCtx.Rule.ID, "error", err)
+		} else if silence != nil {
+			n.log.Info("Alert notifications silenced", "ruleId", evalCtx.Rule.ID, "silenceId", silence.Id)
+			evalCtx.Silence = silence
+			for _, ns := range notifierStates {
+				metrics.MAlertingNotificationSilenced.WithLabelValues(ns.notifier.GetType()).Inc()
+				addNotificationResult(evalCtx, ns.notifier, NotificationStatusSilenced, nil)
+			}
+			return nil
+		}
+	}
+
+	// alert instances of the same evaluation share the image of the first notification
+	imageRendered := evalCtx.Instance != nil && (evalCtx.ImagePublicURL != "" || evalCtx.ImageOnDiskPath != "")
+
+	// standalone alert rules have no panel to render
+	hasPanel := evalCtx.Rule.DashboardID != 0
+
+	if notifierStates.ShouldUploadImage() && hasPanel && !imageRendered {
 		// Create a copy of EvalContext and give it a new, shorter, timeout context to upload the image
 		uploadEvalCtx := *evalCtx
 		timeout := setting.AlertingNotificationTimeout / 2
@@ -72,20 +128,40 @@ func (n *notificationService) SendIfNeeded(evalCtx *EvalContext) error {
 }
 
 func (n *notificationService) sendAndMarkAsComplete(evalContext *EvalContext, notifierState *notifierState) error {
-	notifier := notifierState.notifier
+	sent, err := n.notify(evalContext, notifierState.notifier)
+	if err != nil || !sent {
+		return err
+	}
+
+	return n.markAsComplete(evalContext, notifierState)
+}
+
+// notify sends the notification unless the channel exceeded its rate limit,
+// it returns false if the notification was dropped.
+func (n *notificationService) notify(evalContext *EvalContext, notifier Notifier) (bool, error) {
+	if !evalContext.IsTestRun && !n.rateLimiter.allow(evalContext.Rule.OrgID, notifier, time.Now()) {
+		n.log.Warn("Notification dropped, channel exceeded its rate limit", "uid", notifier.GetNotifierUID(), "ruleId", evalContext.Rule.ID)
+		metrics.MAlertingNotificationDropped.WithLabelValues(notifier.GetType()).Inc()
+		addNotificationResult(evalContext, notifier, NotificationStatusRateLimited, nil)
+		return false, nil
+	}
 
 	n.log.Debug("Sending notification", "type", notifier.GetType(), "uid", notifier.GetNotifierUID(), "isDefault", notifier.GetIsDefault())
 	metrics.MAlertingNotificationSent.WithLabelValues(notifier.GetType()).Inc()
 
-	err := notifier.Notify(evalContext)
-
-	if err != nil {
+	if err := notifier.Notify(evalContext); err != nil {
 		n.log.Error("failed to send notification", "uid", notifier.GetNotifierUID(), "error", err)
 		metrics.MAlertingNotificationFailed.WithLabelValues(notifier.GetType()).Inc()
-		return err
+		addNotificationResult(evalContext, notifier, NotificationStatusFailed, err)
+		return false, err
 	}
 
-	if evalContext.IsTestRun {
+	addNotificationResult(evalContext, notifier, NotificationStatusSent, nil)
+	return true, nil
+}
+
+func (n *notificationService) markAsComplete(evalContext *EvalContext, notifierState *notifierState) error {
+	if evalContext.IsTestRun || evalContext.Instance != nil {
 		return nil
 	}
 
@@ -98,7 +174,7 @@ func (n *notificationService) sendAndMarkAsComplete(evalContext *EvalContext, no
 }
 
 func (n *notificationService) sendNotification(evalContext *EvalContext, notifierState *notifierState) error {
-	if !evalContext.IsTestRun {
+	if !evalContext.IsTestRun && evalContext.Instance == nil {
 		setPendingCmd := &models.SetAlertNotificationStateToPendingCommand{
 			Id:                           notifierState.state.Id,
 			Version:                      notifierState.state.Version,
@@ -119,9 +195,53 @@ func (n *notificationService) sendNotification(evalContext *EvalContext, notifie
 		notifierState.state.Version = setPendingCmd.ResultVersion
 	}
 
+	if !evalContext.IsTestRun && notifierState.notifier.GetGroupInterval() > 0 {
+		n.grouper.add(evalContext, notifierState)
+		addNotificationResult(evalContext, notifierState.notifier, NotificationStatusGrouped, nil)
+		return nil
+	}
+
 	return n.sendAndMarkAsComplete(evalContext, notifierState)
 }
 
+// sendGroup sends the notifications collected during the group interval
+// of a channel, as a single digest if there is more than one.
+func (n *notificationService) sendGroup(group *notificationGroup) {
+	evalContexts := make([]*EvalContext, 0, len(group.items))
+	for _, item := range group.items {
+		evalContexts = append(evalContexts, item.evalContext)
+	}
+
+	var sendCtx EvalContext
+	if len(evalContexts) == 1 {
+		sendCtx = *evalContexts[0]
+	} else {
+		sendCtx = *newDigestEvalContext(evalContexts)
+		metrics.MAlertingNotificationGrouped.WithLabelValues(group.notifier.GetType()).Add(float64(len(evalContexts)))
+	}
+
+	// the state history of the evaluations was saved when they were grouped
+	sendCtx.NotificationResults = nil
+
+	// the evaluation contexts have timed out while the group was collected
+	var cancel context.CancelFunc
+	sendCtx.Ctx, cancel = context.WithTimeout(context.Background(), setting.AlertingNotificationTimeout)
+	defer cancel()
+
+	sent, err := n.notify(&sendCtx, group.notifier)
+	if err != nil || !sent {
+		return
+	}
+
+	for _, item := range group.items {
+		itemCtx := *item.evalContext
+		itemCtx.Ctx = sendCtx.Ctx
+		if err := n.markAsComplete(&itemCtx, item.notifierState); err != nil {
+			n.log.Error("Failed to mark notification as complete", "uid", group.notifier.GetNotifierUID(), "ruleId", itemCtx.Rule.ID, "error", err)
+		}
+	}
+}
+
 func (n *notificationService) sendNotifications(evalContext *EvalContext, notifierStates notifierStateSlice) error {
 	for _, notifierState := range notifierStates {
 		err := n.sendNotification(evalContext, notifierState)
@@ -200,6 +320,19 @@ func (n *notificationService) getNeededNotifiers(orgID int64, notificationUids [
 			continue
 		}
 
+		// alert instances are deduplicated by their own state version
+		// so they don't share the notification state of the alert rule.
+		if evalContext.Instance != nil {
+			state := &models.AlertNotificationState{OrgId: evalContext.Rule.OrgID, AlertId: evalContext.Rule.ID, NotifierId: notification.Id}
+			if not.ShouldNotify(evalContext.Ctx, evalContext, state) {
+				result = append(result, &notifierState{
+					notifier: not,
+					state:    state,
+				})
+			}
+			continue
+		}
+
 		query := &models.GetOrCreateNotificationStateQuery{
 			NotifierId: notification.Id,
 			AlertId:    evalContext.Rule.ID,
@@ -230,6 +363,19 @@ func InitNotifier(model *models.AlertNotification) (Notifier, error) {
 		return nil, errors.New("Unsupported notification type")
 	}
 
+	if err := ValidateNotificationTemplates(model.Settings); err != nil {
+		return nil, err
+	}
+
+	sendSettings, err := ParseNotificationSendSettings(model.Settings)
+	if err != nil {
+		return nil, err
+	}
+
+	if sendSettings.GroupInterval > 0 && !notifierPlugin.SupportsGrouping {
+		return nil, fmt.Errorf("Notification type %s does not support grouping notifications", model.Type)
+	}
+
 	return notifierPlugin.Factory(model)
 }
 
@@ -56,6 +56,10 @@ func TestNotificationService(t *testing.T) {
 		require.Equalf(t, 0, scenarioCtx.renderCount, "expected render not to be called, but it was")
 		require.Equalf(t, 0, scenarioCtx.imageUploadCount, "expected image not to be uploaded, but it was")
 		require.Truef(t, evalCtx.Ctx.Value(notificationSent{}).(bool), "expected notification to be sent, but wasn't")
+
+		result := evalCtx.NotificationResults[len(evalCtx.NotificationResults)-1]
+		require.Equal(t, NotificationStatusSent, result.Status)
+		require.Equal(t, "test", result.Type)
 	})
 
 	notificationServiceScenario(t, "Given alert rule with upload image enabled and render times out should send notification", evalCtx, true, func(scenarioCtx *scenarioContext) {
@@ -299,6 +303,18 @@ func (n *testNotifier) GetFrequency() time.Duration {
 	return n.Frequency
 }
 
+func (n *testNotifier) GetGroupInterval() time.Duration {
+	return 0
+}
+
+func (n *testNotifier) GetGroupBy() string {
+	return ""
+}
+
+func (n *testNotifier) GetRateLimit() (int, time.Duration) {
+	return 0, 0
+}
+
 var _ Notifier = &testNotifier{}
 
 type testRenderService struct {
@@ -92,8 +92,8 @@ func (am *AlertmanagerNotifier) createAlert(evalContext *alerting.EvalContext, m
 	// Annotations (summary and description are very commonly used).
 	alertJSON.SetPath([]string{"annotations", "summary"}, evalContext.Rule.Name)
 	description := ""
-	if evalContext.Rule.Message != "" {
-		description += evalContext.Rule.Message
+	if message := am.GetMessage(evalContext); message != "" {
+		description += message
 	}
 	if evalContext.Error != nil {
 		if description != "" {
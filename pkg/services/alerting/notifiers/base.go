@@ -23,6 +23,12 @@ type NotifierBase struct {
 	SendReminder          bool
 	DisableResolveMessage bool
 	Frequency             time.Duration
+	TitleTemplate         string
+	MessageTemplate       string
+	GroupInterval         time.Duration
+	GroupBy               string
+	MaxMessages           int
+	RateLimitInterval     time.Duration
 
 	log log.Logger
 }
@@ -35,6 +41,12 @@ func NewNotifierBase(model *models.AlertNotification) NotifierBase {
 		uploadImage = value.MustBool()
 	}
 
+	// the settings are validated before the notifier is created
+	sendSettings, err := alerting.ParseNotificationSendSettings(model.Settings)
+	if err != nil {
+		sendSettings = &alerting.NotificationSendSettings{}
+	}
+
 	return NotifierBase{
 		UID:                   model.Uid,
 		Name:                  model.Name,
@@ -44,6 +56,12 @@ func NewNotifierBase(model *models.AlertNotification) NotifierBase {
 		SendReminder:          model.SendReminder,
 		DisableResolveMessage: model.DisableResolveMessage,
 		Frequency:             model.Frequency,
+		TitleTemplate:         model.Settings.Get(alerting.TitleTemplateSetting).MustString(),
+		MessageTemplate:       model.Settings.Get(alerting.MessageTemplateSetting).MustString(),
+		GroupInterval:         sendSettings.GroupInterval,
+		GroupBy:               sendSettings.GroupBy,
+		MaxMessages:           sendSettings.MaxMessages,
+		RateLimitInterval:     sendSettings.RateLimitInterval,
 		log:                   log.New("alerting.notifier." + model.Name),
 	}
 }
@@ -146,3 +164,46 @@ func (n *NotifierBase) GetDisableResolveMessage() bool {
 func (n *NotifierBase) GetFrequency() time.Duration {
 	return n.Frequency
 }
+
+// GetGroupInterval returns how long notifications are
+// collected before being sent as a digest.
+func (n *NotifierBase) GetGroupInterval() time.Duration {
+	return n.GroupInterval
+}
+
+// GetGroupBy returns the key notifications are grouped by.
+func (n *NotifierBase) GetGroupBy() string {
+	return n.GroupBy
+}
+
+// GetRateLimit returns the maximum number of messages
+// sent within the rate limit interval.
+func (n *NotifierBase) GetRateLimit() (int, time.Duration) {
+	return n.MaxMessages, n.RateLimitInterval
+}
+
+// GetTitle returns the notification title, rendered from the
+// title template if one is configured.
+func (n *NotifierBase) GetTitle(evalContext *alerting.EvalContext) string {
+	return n.render(n.TitleTemplate, evalContext.GetNotificationTitle(), evalContext)
+}
+
+// GetMessage returns the notification message, rendered from the
+// message template if one is configured.
+func (n *NotifierBase) GetMessage(evalContext *alerting.EvalContext) string {
+	return n.render(n.MessageTemplate, evalContext.Rule.Message, evalContext)
+}
+
+func (n *NotifierBase) render(tmpl string, fallback string, evalContext *alerting.EvalContext) string {
+	if tmpl == "" {
+		return fallback
+	}
+
+	text, err := alerting.RenderNotificationTemplate(tmpl, evalContext)
+	if err != nil {
+		n.log.Warn("Failed to render notification template", "error", err)
+		return fallback
+	}
+
+	return text
+}
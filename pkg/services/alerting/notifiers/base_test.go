@@ -217,5 +217,30 @@ func TestBaseNotifier(t *testing.T) {
 			base := NewNotifierBase(model)
 			So(base.DisableResolveMessage, ShouldBeFalse)
 		})
+
+		Convey("uses default title and message without templates", func() {
+			base := NewNotifierBase(model)
+			evalContext := alerting.NewEvalContext(context.Background(), &alerting.Rule{Name: "rule", Message: "msg", State: models.AlertStateAlerting})
+
+			So(base.GetTitle(evalContext), ShouldEqual, "[Alerting] rule")
+			So(base.GetMessage(evalContext), ShouldEqual, "msg")
+		})
+
+		Convey("renders title and message templates", func() {
+			bJSON.Set("titleTemplate", "{{.RuleName}} is {{.State}}")
+			bJSON.Set("messageTemplate", "{{.Message}} ({{.Tags.team}})")
+
+			base := NewNotifierBase(model)
+			evalContext := alerting.NewEvalContext(context.Background(), &alerting.Rule{
+				Name:          "rule",
+				Message:       "msg",
+				State:         models.AlertStateAlerting,
+				AlertRuleTags: []*models.Tag{{Key: "team", Value: "backend"}},
+			})
+			evalContext.IsTestRun = true
+
+			So(base.GetTitle(evalContext), ShouldEqual, "rule is alerting")
+			So(base.GetMessage(evalContext), ShouldEqual, "msg (backend)")
+		})
 	})
 }
@@ -26,11 +26,12 @@ const dingdingOptionsTemplate = `
 
 func init() {
 	alerting.RegisterNotifier(&alerting.NotifierPlugin{
-		Type:            "dingding",
-		Name:            "DingDing",
-		Description:     "Sends HTTP POST request to DingDing",
-		Factory:         newDingDingNotifier,
-		OptionsTemplate: dingdingOptionsTemplate,
+		Type:             "dingding",
+		Name:             "DingDing",
+		Description:      "Sends HTTP POST request to DingDing",
+		Factory:          newDingDingNotifier,
+		SupportsGrouping: true,
+		OptionsTemplate:  dingdingOptionsTemplate,
 	})
 
 }
@@ -100,9 +101,9 @@ func (dd *DingDingNotifier) genBody(evalContext *alerting.EvalContext, messageUR
 
 	dd.log.Info("messageUrl:" + messageURL)
 
-	message := evalContext.Rule.Message
+	message := dd.GetMessage(evalContext)
 	picURL := evalContext.ImagePublicURL
-	title := evalContext.GetNotificationTitle()
+	title := dd.GetTitle(evalContext)
 	if message == "" {
 		message = title
 	}
@@ -18,10 +18,11 @@ import (
 
 func init() {
 	alerting.RegisterNotifier(&alerting.NotifierPlugin{
-		Type:        "discord",
-		Name:        "Discord",
-		Description: "Sends notifications to Discord",
-		Factory:     newDiscordNotifier,
+		Type:             "discord",
+		Name:             "Discord",
+		Description:      "Sends notifications to Discord",
+		Factory:          newDiscordNotifier,
+		SupportsGrouping: true,
 		OptionsTemplate: `
       <h3 class="page-heading">Discord settings</h3>
       <div class="gf-form max-width-30">
@@ -103,11 +104,11 @@ func (dn *DiscordNotifier) Notify(evalContext *alerting.EvalContext) error {
 	color, _ := strconv.ParseInt(strings.TrimLeft(evalContext.GetStateModel().Color, "#"), 16, 0)
 
 	embed := simplejson.New()
-	embed.Set("title", evalContext.GetNotificationTitle())
+	embed.Set("title", dn.GetTitle(evalContext))
 	//Discord takes integer for color
 	embed.Set("color", color)
 	embed.Set("url", ruleURL)
-	embed.Set("description", evalContext.Rule.Message)
+	embed.Set("description", dn.GetMessage(evalContext))
 	embed.Set("type", "rich")
 	embed.Set("fields", fields)
 	embed.Set("footer", footer)
@@ -14,10 +14,11 @@ import (
 
 func init() {
 	alerting.RegisterNotifier(&alerting.NotifierPlugin{
-		Type:        "email",
-		Name:        "Email",
-		Description: "Sends notifications using Grafana server configured SMTP settings",
-		Factory:     NewEmailNotifier,
+		Type:             "email",
+		Name:             "Email",
+		Description:      "Sends notifications using Grafana server configured SMTP settings",
+		Factory:          NewEmailNotifier,
+		SupportsGrouping: true,
 		OptionsTemplate: `
 			<h3 class="page-heading">Email settings</h3>
 			<div class="gf-form">
@@ -89,13 +90,13 @@ func (en *EmailNotifier) Notify(evalContext *alerting.EvalContext) error {
 
 	cmd := &models.SendEmailCommandSync{
 		SendEmailCommand: models.SendEmailCommand{
-			Subject: evalContext.GetNotificationTitle(),
+			Subject: en.GetTitle(evalContext),
 			Data: map[string]interface{}{
-				"Title":         evalContext.GetNotificationTitle(),
+				"Title":         en.GetTitle(evalContext),
 				"State":         evalContext.Rule.State,
 				"Name":          evalContext.Rule.Name,
 				"StateModel":    evalContext.GetStateModel(),
-				"Message":       evalContext.Rule.Message,
+				"Message":       en.GetMessage(evalContext),
 				"Error":         error,
 				"RuleUrl":       ruleURL,
 				"ImageLink":     "",
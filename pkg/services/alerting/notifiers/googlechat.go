@@ -18,7 +18,8 @@ func init() {
 		Name: "Google Hangouts Chat",
 		Description: "Sends notifications to Google Hangouts Chat via webhooks based on the official JSON message " +
 			"format (https://developers.google.com/hangouts/chat/reference/message-formats/).",
-		Factory: newGoogleChatNotifier,
+		Factory:          newGoogleChatNotifier,
+		SupportsGrouping: true,
 		OptionsTemplate: `
       <h3 class="page-heading">Google Hangouts Chat settings</h3>
       <div class="gf-form max-width-30">
@@ -127,12 +128,12 @@ func (gcn *GoogleChatNotifier) Notify(evalContext *alerting.EvalContext) error {
 	}
 
 	widgets := []widget{}
-	if len(evalContext.Rule.Message) > 0 {
+	if message := gcn.GetMessage(evalContext); len(message) > 0 {
 		// add a text paragraph widget for the message if there is a message
 		// Google Chat API doesn't accept an empty text property
 		widgets = append(widgets, textParagraphWidget{
 			Text: text{
-				Text: evalContext.Rule.Message,
+				Text: message,
 			},
 		})
 	}
@@ -192,11 +193,11 @@ func (gcn *GoogleChatNotifier) Notify(evalContext *alerting.EvalContext) error {
 
 	// nest the required structs
 	res1D := &outerStruct{
-		FallbackText: evalContext.GetNotificationTitle(),
+		FallbackText: gcn.GetTitle(evalContext),
 		Cards: []card{
 			{
 				Header: header{
-					Title: evalContext.GetNotificationTitle(),
+					Title: gcn.GetTitle(evalContext),
 				},
 				Sections: []section{
 					{
@@ -15,10 +15,11 @@ import (
 
 func init() {
 	alerting.RegisterNotifier(&alerting.NotifierPlugin{
-		Type:        "hipchat",
-		Name:        "HipChat",
-		Description: "Sends notifications uto a HipChat Room",
-		Factory:     NewHipChatNotifier,
+		Type:             "hipchat",
+		Name:             "HipChat",
+		Description:      "Sends notifications uto a HipChat Room",
+		Factory:          NewHipChatNotifier,
+		SupportsGrouping: true,
 		OptionsTemplate: `
       <h3 class="page-heading">HipChat settings</h3>
 			      <div class="gf-form max-width-30">
@@ -117,11 +118,11 @@ func (hc *HipChatNotifier) Notify(evalContext *alerting.EvalContext) error {
 
 	message := ""
 	if evalContext.Rule.State != models.AlertStateOK { //don't add message when going back to alert state ok.
-		message += " " + evalContext.Rule.Message
+		message += " " + hc.GetMessage(evalContext)
 	}
 
 	if message == "" {
-		message = evalContext.GetNotificationTitle() + " in state " + evalContext.GetStateModel().Text
+		message = hc.GetTitle(evalContext) + " in state " + evalContext.GetStateModel().Text
 	}
 
 	//HipChat has a set list of colors
@@ -140,7 +141,7 @@ func (hc *HipChatNotifier) Notify(evalContext *alerting.EvalContext) error {
 		"style":       "application",
 		"url":         ruleURL,
 		"id":          "1",
-		"title":       evalContext.GetNotificationTitle(),
+		"title":       hc.GetTitle(evalContext),
 		"description": message,
 		"icon": map[string]interface{}{
 			"url": "https://grafana.com/assets/img/fav32.png",
@@ -77,7 +77,7 @@ func (kn *KafkaNotifier) Notify(evalContext *alerting.EvalContext) error {
 	bodyJSON := simplejson.New()
 	//get alert state in the kafka output issue #11401
 	bodyJSON.Set("alert_state", state)
-	bodyJSON.Set("description", evalContext.Rule.Name+" - "+evalContext.Rule.Message)
+	bodyJSON.Set("description", evalContext.Rule.Name+" - "+kn.GetMessage(evalContext))
 	bodyJSON.Set("client", "Grafana")
 	bodyJSON.Set("details", customData)
 	bodyJSON.Set("incident_key", "alertId-"+strconv.FormatInt(evalContext.Rule.ID, 10))
@@ -12,10 +12,11 @@ import (
 
 func init() {
 	alerting.RegisterNotifier(&alerting.NotifierPlugin{
-		Type:        "LINE",
-		Name:        "LINE",
-		Description: "Send notifications to LINE notify",
-		Factory:     NewLINENotifier,
+		Type:             "LINE",
+		Name:             "LINE",
+		Description:      "Send notifications to LINE notify",
+		Factory:          NewLINENotifier,
+		SupportsGrouping: true,
 		OptionsTemplate: `
     <div class="gf-form-group">
       <h3 class="page-heading">LINE notify settings</h3>
@@ -75,7 +76,7 @@ func (ln *LineNotifier) createAlert(evalContext *alerting.EvalContext) error {
 	}
 
 	form := url.Values{}
-	body := fmt.Sprintf("%s - %s\n%s", evalContext.Rule.Name, ruleURL, evalContext.Rule.Message)
+	body := fmt.Sprintf("%s - %s\n%s", evalContext.Rule.Name, ruleURL, ln.GetMessage(evalContext))
 	form.Add("message", body)
 
 	if ln.NeedsImage() && evalContext.ImagePublicURL != "" {
@@ -129,7 +129,7 @@ func (pn *PagerdutyNotifier) buildEventPayload(evalContext *alerting.EvalContext
 		}
 	}
 
-	summary := evalContext.Rule.Name + " - " + evalContext.Rule.Message
+	summary := evalContext.Rule.Name + " - " + pn.GetMessage(evalContext)
 	if len(summary) > 1024 {
 		summary = summary[0:1024]
 	}
@@ -43,10 +43,11 @@ func init() {
           'none'`
 
 	alerting.RegisterNotifier(&alerting.NotifierPlugin{
-		Type:        "pushover",
-		Name:        "Pushover",
-		Description: "Sends HTTP POST request to the Pushover API",
-		Factory:     NewPushoverNotifier,
+		Type:             "pushover",
+		Name:             "Pushover",
+		Description:      "Sends HTTP POST request to the Pushover API",
+		Factory:          NewPushoverNotifier,
+		SupportsGrouping: true,
 		OptionsTemplate: `
       <h3 class="page-heading">Pushover settings</h3>
       <div class="gf-form">
@@ -152,7 +153,7 @@ func (pn *PushoverNotifier) Notify(evalContext *alerting.EvalContext) error {
 		return err
 	}
 
-	message := evalContext.Rule.Message
+	message := pn.GetMessage(evalContext)
 	for idx, evt := range evalContext.EvalMatches {
 		message += fmt.Sprintf("\n<b>%s</b>: %v", evt.Metric, evt.Value)
 		if idx > 4 {
@@ -263,7 +264,7 @@ func (pn *PushoverNotifier) genPushoverBody(evalContext *alerting.EvalContext, m
 	}
 
 	// Add title
-	err = w.WriteField("title", evalContext.GetNotificationTitle())
+	err = w.WriteField("title", pn.GetTitle(evalContext))
 	if err != nil {
 		return nil, b, err
 	}
@@ -115,8 +115,8 @@ func (sn *SensuNotifier) Notify(evalContext *alerting.EvalContext) error {
 		bodyJSON.Set("imageUrl", evalContext.ImagePublicURL)
 	}
 
-	if evalContext.Rule.Message != "" {
-		bodyJSON.Set("output", evalContext.Rule.Message)
+	if message := sn.GetMessage(evalContext); message != "" {
+		bodyJSON.Set("output", message)
 	}
 
 	body, _ := bodyJSON.MarshalJSON()
@@ -21,10 +21,11 @@ import (
 
 func init() {
 	alerting.RegisterNotifier(&alerting.NotifierPlugin{
-		Type:        "slack",
-		Name:        "Slack",
-		Description: "Sends notifications to Slack via Slack Webhooks",
-		Factory:     NewSlackNotifier,
+		Type:             "slack",
+		Name:             "Slack",
+		Description:      "Sends notifications to Slack via Slack Webhooks",
+		Factory:          NewSlackNotifier,
+		SupportsGrouping: true,
 		OptionsTemplate: `
       <h3 class="page-heading">Slack settings</h3>
       <div class="gf-form max-width-30">
@@ -256,7 +257,7 @@ func (sn *SlackNotifier) Notify(evalContext *alerting.EvalContext) error {
 	}
 	msg := ""
 	if evalContext.Rule.State != models.AlertStateOK { //don't add message when going back to alert state ok.
-		msg = evalContext.Rule.Message
+		msg = sn.GetMessage(evalContext)
 	}
 	imageURL := ""
 	// default to file.upload API method if a token is provided
@@ -278,10 +279,10 @@ func (sn *SlackNotifier) Notify(evalContext *alerting.EvalContext) error {
 	}
 	attachment := map[string]interface{}{
 		"color":       evalContext.GetStateModel().Color,
-		"title":       evalContext.GetNotificationTitle(),
+		"title":       sn.GetTitle(evalContext),
 		"title_link":  ruleURL,
 		"text":        msg,
-		"fallback":    evalContext.GetNotificationTitle(),
+		"fallback":    sn.GetTitle(evalContext),
 		"fields":      fields,
 		"footer":      "Grafana v" + setting.BuildVersion,
 		"footer_icon": "https://grafana.com/assets/img/fav32.png",
@@ -291,7 +292,7 @@ func (sn *SlackNotifier) Notify(evalContext *alerting.EvalContext) error {
 		attachment["image_url"] = imageURL
 	}
 	body := map[string]interface{}{
-		"text":   evalContext.GetNotificationTitle(),
+		"text":   sn.GetTitle(evalContext),
 		"blocks": blocks,
 		"attachments": []map[string]interface{}{
 			attachment,
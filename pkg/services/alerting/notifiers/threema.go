@@ -17,10 +17,11 @@ var (
 
 func init() {
 	alerting.RegisterNotifier(&alerting.NotifierPlugin{
-		Type:        "threema",
-		Name:        "Threema Gateway",
-		Description: "Sends notifications to Threema using the Threema Gateway",
-		Factory:     NewThreemaNotifier,
+		Type:             "threema",
+		Name:             "Threema Gateway",
+		Description:      "Sends notifications to Threema using the Threema Gateway",
+		Factory:          NewThreemaNotifier,
+		SupportsGrouping: true,
 		OptionsTemplate: `
       <h3 class="page-heading">Threema Gateway settings</h3>
       <p>
@@ -141,8 +142,8 @@ func (notifier *ThreemaNotifier) Notify(evalContext *alerting.EvalContext) error
 
 	// Build message
 	message := fmt.Sprintf("%s%s\n\n*State:* %s\n*Message:* %s\n",
-		stateEmoji, evalContext.GetNotificationTitle(),
-		evalContext.Rule.Name, evalContext.Rule.Message)
+		stateEmoji, notifier.GetTitle(evalContext),
+		evalContext.Rule.Name, notifier.GetMessage(evalContext))
 	ruleURL, err := evalContext.GetRuleURL()
 	if err == nil {
 		message = message + fmt.Sprintf("*URL:* %s\n", ruleURL)
@@ -26,6 +26,8 @@ func newRuleReader() *defaultRuleReader {
 	return ruleReader
 }
 
+// fetch loads the alerts extracted from dashboards and the alerts
+// derived from standalone alert rules, which have no dashboard.
 func (arr *defaultRuleReader) fetch() []*Rule {
 	cmd := &models.GetAllAlertsQuery{}
 
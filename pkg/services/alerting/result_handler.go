@@ -31,6 +31,11 @@ func newResultHandler(renderService rendering.Service) *defaultResultHandler {
 	}
 }
 
+// flushNotifications sends the notifications collected in notification groups.
+func (handler *defaultResultHandler) flushNotifications() {
+	handler.notifier.grouper.flushAll()
+}
+
 func (handler *defaultResultHandler) handle(evalContext *EvalContext) error {
 	executionError := ""
 	annotationData := simplejson.New()
@@ -46,6 +51,8 @@ func (handler *defaultResultHandler) handle(evalContext *EvalContext) error {
 		annotationData.Set("noData", true)
 	}
 
+	var stateAnnotation *annotations.Item
+
 	metrics.MAlertingResultState.WithLabelValues(string(evalContext.Rule.State)).Inc()
 	if evalContext.shouldUpdateAlertState() {
 		handler.log.Info("New state change", "ruleId", evalContext.Rule.ID, "newState", evalContext.Rule.State, "prev state", evalContext.PrevAlertState)
@@ -81,8 +88,7 @@ func (handler *defaultResultHandler) handle(evalContext *EvalContext) error {
 			evalContext.Rule.LastStateChange = time.Now()
 		}
 
-		// save annotation
-		item := annotations.Item{
+		stateAnnotation = &annotations.Item{
 			OrgId:       evalContext.Rule.OrgID,
 			DashboardId: evalContext.Rule.DashboardID,
 			PanelId:     evalContext.Rule.PanelID,
@@ -93,13 +99,62 @@ func (handler *defaultResultHandler) handle(evalContext *EvalContext) error {
 			Epoch:       time.Now().UnixNano() / int64(time.Millisecond),
 			Data:        annotationData,
 		}
+	}
 
-		annotationRepo := annotations.GetRepository()
-		if err := annotationRepo.Save(&item); err != nil {
-			handler.log.Error("Failed to save annotation for new alert state", "error", err)
-		}
+	// multi-dimensional rules notify per alert instance unless the rule
+	// itself ended up in a state that does not describe single series.
+	if !evalContext.Rule.MultiDimensional || !handler.handleInstances(evalContext) {
+		handler.sendIfNeeded(evalContext)
+	}
+
+	// the annotation and history are saved after notifying so they can tell if notifications were silenced
+	if stateAnnotation != nil {
+		handler.saveAnnotation(evalContext, stateAnnotation)
+		handler.saveStateHistory(evalContext, "", nil, executionError)
+	}
+
+	return nil
+}
+
+func (handler *defaultResultHandler) saveAnnotation(evalContext *EvalContext, item *annotations.Item) {
+	if evalContext.Silence != nil {
+		item.Data.Set("silenced", true)
+		item.Data.Set("silenceId", evalContext.Silence.Id)
 	}
 
+	annotationRepo := annotations.GetRepository()
+	if err := annotationRepo.Save(item); err != nil {
+		handler.log.Error("Failed to save annotation for new alert state", "error", err)
+	}
+}
+
+// saveStateHistory records the state transition described by the evaluation
+// context, metric and labels are set for transitions of alert instances.
+func (handler *defaultResultHandler) saveStateHistory(evalContext *EvalContext, metric string, labels map[string]string, executionError string) {
+	notifications := evalContext.NotificationResults
+	if notifications == nil {
+		notifications = make([]*NotificationResult, 0)
+	}
+
+	cmd := &models.AddAlertStateHistoryCommand{
+		OrgId:          evalContext.Rule.OrgID,
+		AlertId:        evalContext.Rule.ID,
+		Metric:         metric,
+		Labels:         labels,
+		PrevState:      evalContext.PrevAlertState,
+		NewState:       evalContext.Rule.State,
+		EvalMatches:    simplejson.NewFromAny(evalContext.EvalMatches),
+		ConditionEvals: evalContext.ConditionEvals,
+		Error:          executionError,
+		Notifications:  simplejson.NewFromAny(notifications),
+	}
+
+	if err := bus.Dispatch(cmd); err != nil {
+		handler.log.Error("Failed to save alert state history", "ruleId", evalContext.Rule.ID, "error", err)
+	}
+}
+
+func (handler *defaultResultHandler) sendIfNeeded(evalContext *EvalContext) {
 	if err := handler.notifier.SendIfNeeded(evalContext); err != nil {
 		if xerrors.Is(err, context.Canceled) {
 			handler.log.Debug("handler.notifier.SendIfNeeded returned context.Canceled")
@@ -109,6 +164,4 @@ func (handler *defaultResultHandler) handle(evalContext *EvalContext) error {
 			handler.log.Error("handler.notifier.SendIfNeeded failed", "err", err)
 		}
 	}
-
-	return nil
 }
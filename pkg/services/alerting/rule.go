@@ -37,6 +37,7 @@ type Rule struct {
 	Conditions          []Condition
 	Notifications       []string
 	AlertRuleTags       []*models.Tag
+	MultiDimensional    bool
 
 	StateChanges int64
 }
@@ -127,6 +128,7 @@ func NewRuleFromDBAlert(ruleDef *models.Alert) (*Rule, error) {
 	model.NoDataState = models.NoDataOption(ruleDef.Settings.Get("noDataState").MustString("no_data"))
 	model.ExecutionErrorState = models.ExecutionErrorOption(ruleDef.Settings.Get("executionErrorState").MustString("alerting"))
 	model.StateChanges = ruleDef.StateChanges
+	model.MultiDimensional = ruleDef.Settings.Get("multiDimensional").MustBool(false)
 
 	model.Frequency = ruleDef.Frequency
 	// frequency cannot be zero since that would not execute the alert rule.
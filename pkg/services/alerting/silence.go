@@ -0,0 +1,80 @@
+package alerting
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// getMatchingSilence returns the first active silence matching the alert rule
+// or nil if notifications for the rule should be sent.
+func getMatchingSilence(evalCtx *EvalContext) (*models.AlertSilence, error) {
+	query := &models.GetAlertSilencesQuery{OrgId: evalCtx.Rule.OrgID, ActiveAt: time.Now()}
+	if err := bus.Dispatch(query); err != nil {
+		return nil, err
+	}
+
+	var folderUID *string
+	for _, silence := range query.Result {
+		if silence.AlertName != "" && silence.AlertName != evalCtx.Rule.Name {
+			continue
+		}
+
+		if !silence.MatchesTags(evalCtx.Rule.AlertRuleTags) {
+			continue
+		}
+
+		// dashboard and folder matchers never match standalone alert rules
+		if (silence.DashboardUid != "" || silence.FolderUid != "") && evalCtx.Rule.DashboardID == 0 {
+			continue
+		}
+
+		if silence.DashboardUid != "" {
+			ref, err := evalCtx.GetDashboardUID()
+			if err != nil {
+				return nil, err
+			}
+
+			if ref.Uid != silence.DashboardUid {
+				continue
+			}
+		}
+
+		if silence.FolderUid != "" {
+			if folderUID == nil {
+				uid, err := getDashboardFolderUID(evalCtx.Rule.OrgID, evalCtx.Rule.DashboardID)
+				if err != nil {
+					return nil, err
+				}
+				folderUID = &uid
+			}
+
+			if *folderUID != silence.FolderUid {
+				continue
+			}
+		}
+
+		return silence, nil
+	}
+
+	return nil, nil
+}
+
+func getDashboardFolderUID(orgID int64, dashboardID int64) (string, error) {
+	dashQuery := &models.GetDashboardQuery{Id: dashboardID, OrgId: orgID}
+	if err := bus.Dispatch(dashQuery); err != nil {
+		return "", err
+	}
+
+	if dashQuery.Result.FolderId == 0 {
+		return "", nil
+	}
+
+	folderQuery := &models.GetDashboardQuery{Id: dashQuery.Result.FolderId, OrgId: orgID}
+	if err := bus.Dispatch(folderQuery); err != nil {
+		return "", err
+	}
+
+	return folderQuery.Result.Uid, nil
+}
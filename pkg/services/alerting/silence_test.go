@@ -0,0 +1,80 @@
+package alerting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+func TestGetMatchingSilence(t *testing.T) {
+	silences := []*models.AlertSilence{}
+
+	bus.AddHandler("test", func(query *models.GetAlertSilencesQuery) error {
+		query.Result = silences
+		return nil
+	})
+	bus.AddHandler("test", func(query *models.GetDashboardRefByIdQuery) error {
+		query.Result = &models.DashboardRef{Uid: "dash-uid", Slug: "dash"}
+		return nil
+	})
+	bus.AddHandler("test", func(query *models.GetDashboardQuery) error {
+		if query.Id == 1 {
+			query.Result = &models.Dashboard{Id: 1, Uid: "dash-uid", FolderId: 2}
+		} else {
+			query.Result = &models.Dashboard{Id: 2, Uid: "folder-uid", IsFolder: true}
+		}
+		return nil
+	})
+
+	evalCtx := NewEvalContext(context.TODO(), &Rule{
+		OrgID:         1,
+		DashboardID:   1,
+		Name:          "High CPU",
+		AlertRuleTags: []*models.Tag{{Key: "team", Value: "ops"}, {Key: "env", Value: "prod"}},
+	})
+
+	tcs := []struct {
+		name    string
+		silence *models.AlertSilence
+		matches bool
+	}{
+		{name: "alert name", silence: &models.AlertSilence{AlertName: "High CPU"}, matches: true},
+		{name: "other alert name", silence: &models.AlertSilence{AlertName: "Low disk"}, matches: false},
+		{name: "dashboard uid", silence: &models.AlertSilence{DashboardUid: "dash-uid"}, matches: true},
+		{name: "other dashboard uid", silence: &models.AlertSilence{DashboardUid: "other"}, matches: false},
+		{name: "folder uid", silence: &models.AlertSilence{FolderUid: "folder-uid"}, matches: true},
+		{name: "other folder uid", silence: &models.AlertSilence{FolderUid: "other"}, matches: false},
+		{name: "tags", silence: &models.AlertSilence{Tags: map[string]string{"team": "ops"}}, matches: true},
+		{name: "tag with other value", silence: &models.AlertSilence{Tags: map[string]string{"team": "dev"}}, matches: false},
+		{name: "all matchers", silence: &models.AlertSilence{AlertName: "High CPU", DashboardUid: "dash-uid", Tags: map[string]string{"env": "prod"}}, matches: true},
+		{name: "one matcher not matching", silence: &models.AlertSilence{AlertName: "High CPU", DashboardUid: "other"}, matches: false},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			silences = []*models.AlertSilence{tc.silence}
+
+			silence, err := getMatchingSilence(evalCtx)
+			assert.NoError(t, err)
+			if tc.matches {
+				assert.Equal(t, tc.silence, silence)
+			} else {
+				assert.Nil(t, silence)
+			}
+		})
+	}
+}
+
+func TestAlertSilenceIsActive(t *testing.T) {
+	now := time.Now()
+	silence := &models.AlertSilence{StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)}
+
+	assert.True(t, silence.IsActive(now))
+	assert.False(t, silence.IsActive(now.Add(-2*time.Hour)))
+	assert.False(t, silence.IsActive(now.Add(time.Hour)))
+}
@@ -20,12 +20,29 @@ type NotificationTestCommand struct {
 	Settings *simplejson.Json
 }
 
+// NotificationPreviewCommand renders the title and message
+// of an alert notification for a test alert.
+type NotificationPreviewCommand struct {
+	Name     string
+	Type     string
+	Settings *simplejson.Json
+
+	Result *NotificationPreview
+}
+
+// NotificationPreview is the rendered title and message of a notification.
+type NotificationPreview struct {
+	Title   string `json:"title"`
+	Message string `json:"message"`
+}
+
 var (
 	logger = log.New("alerting.testnotification")
 )
 
 func init() {
 	bus.AddHandler("alerting", handleNotificationTestCommand)
+	bus.AddHandler("alerting", handleNotificationPreviewCommand)
 }
 
 func handleNotificationTestCommand(cmd *NotificationTestCommand) error {
@@ -47,6 +64,41 @@ func handleNotificationTestCommand(cmd *NotificationTestCommand) error {
 	return notifier.sendNotifications(createTestEvalContext(cmd), notifierStateSlice{{notifier: notifiers}})
 }
 
+func handleNotificationPreviewCommand(cmd *NotificationPreviewCommand) error {
+	if cmd.Settings == nil {
+		cmd.Settings = simplejson.New()
+	}
+
+	if err := ValidateNotificationTemplates(cmd.Settings); err != nil {
+		return err
+	}
+
+	evalContext := createTestEvalContext(&NotificationTestCommand{Name: cmd.Name, Type: cmd.Type, Settings: cmd.Settings})
+	preview := &NotificationPreview{
+		Title:   evalContext.GetNotificationTitle(),
+		Message: evalContext.Rule.Message,
+	}
+
+	if tmpl := cmd.Settings.Get(TitleTemplateSetting).MustString(); tmpl != "" {
+		title, err := RenderNotificationTemplate(tmpl, evalContext)
+		if err != nil {
+			return fmt.Errorf("Failed to render %s: %v", TitleTemplateSetting, err)
+		}
+		preview.Title = title
+	}
+
+	if tmpl := cmd.Settings.Get(MessageTemplateSetting).MustString(); tmpl != "" {
+		message, err := RenderNotificationTemplate(tmpl, evalContext)
+		if err != nil {
+			return fmt.Errorf("Failed to render %s: %v", MessageTemplateSetting, err)
+		}
+		preview.Message = message
+	}
+
+	cmd.Result = preview
+	return nil
+}
+
 func createTestEvalContext(cmd *NotificationTestCommand) *EvalContext {
 	testRule := &Rule{
 		DashboardID: 1,
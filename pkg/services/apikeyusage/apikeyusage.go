@@ -0,0 +1,87 @@
+package apikeyusage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/registry"
+)
+
+const flushInterval = time.Minute
+
+// ApiKeyUsageService records when and from where API keys and service account tokens
+// were last used. Usages are collected in memory and written once per flush interval,
+// so that busy keys don't cause a database write on every request.
+type ApiKeyUsageService struct {
+	Bus bus.Bus `inject:""`
+
+	log     log.Logger
+	mu      sync.Mutex
+	pending map[int64]*models.ApiKeyUsage
+}
+
+func init() {
+	registry.RegisterService(&ApiKeyUsageService{})
+}
+
+func (s *ApiKeyUsageService) Init() error {
+	s.log = log.New("apikeyusage")
+	s.pending = make(map[int64]*models.ApiKeyUsage)
+	s.Bus.AddEventListener(s.handleApiKeyUsed)
+	return nil
+}
+
+func (s *ApiKeyUsageService) Run(ctx context.Context) error {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.flush()
+		case <-ctx.Done():
+			s.flush()
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *ApiKeyUsageService) handleApiKeyUsed(evt *events.ApiKeyUsed) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if usage, ok := s.pending[evt.Id]; ok && usage.LastUsedAt.After(evt.Timestamp) {
+		return nil
+	}
+
+	s.pending[evt.Id] = &models.ApiKeyUsage{Id: evt.Id, LastUsedAt: evt.Timestamp, LastUsedIp: evt.ClientIp}
+	return nil
+}
+
+func (s *ApiKeyUsageService) flush() {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[int64]*models.ApiKeyUsage)
+	s.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	cmd := models.UpdateApiKeysLastUsedCommand{Usages: make([]*models.ApiKeyUsage, 0, len(pending))}
+	for _, usage := range pending {
+		cmd.Usages = append(cmd.Usages, usage)
+	}
+
+	if err := s.Bus.Dispatch(&cmd); err != nil {
+		s.log.Error("Failed to update API key last used", "keys", len(cmd.Usages), "error", err)
+		return
+	}
+
+	s.log.Debug("Updated API key last used", "keys", len(cmd.Usages))
+}
@@ -0,0 +1,52 @@
+package apikeyusage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApiKeyUsageService(t *testing.T) {
+	var updates []*models.UpdateApiKeysLastUsedCommand
+
+	b := bus.New()
+	b.AddHandler(func(cmd *models.UpdateApiKeysLastUsedCommand) error {
+		updates = append(updates, cmd)
+		return nil
+	})
+
+	s := &ApiKeyUsageService{Bus: b}
+	require.NoError(t, s.Init())
+
+	now := time.Now()
+	require.NoError(t, b.Publish(&events.ApiKeyUsed{Timestamp: now, Id: 1, ClientIp: "10.0.0.1"}))
+	require.NoError(t, b.Publish(&events.ApiKeyUsed{Timestamp: now.Add(time.Second), Id: 1, ClientIp: "10.0.0.2"}))
+	require.NoError(t, b.Publish(&events.ApiKeyUsed{Timestamp: now.Add(-time.Second), Id: 1, ClientIp: "10.0.0.3"}))
+	require.NoError(t, b.Publish(&events.ApiKeyUsed{Timestamp: now, Id: 2, ClientIp: "10.0.0.4"}))
+
+	t.Run("Should write the latest usage of every key at once", func(t *testing.T) {
+		s.flush()
+
+		require.Len(t, updates, 1)
+		usages := map[int64]*models.ApiKeyUsage{}
+		for _, usage := range updates[0].Usages {
+			usages[usage.Id] = usage
+		}
+
+		require.Len(t, usages, 2)
+		assert.Equal(t, now.Add(time.Second), usages[1].LastUsedAt)
+		assert.Equal(t, "10.0.0.2", usages[1].LastUsedIp)
+		assert.Equal(t, "10.0.0.4", usages[2].LastUsedIp)
+	})
+
+	t.Run("Should not write when no key was used", func(t *testing.T) {
+		s.flush()
+
+		assert.Len(t, updates, 1)
+	})
+}
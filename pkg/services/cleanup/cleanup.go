@@ -40,7 +40,16 @@ func (srv *CleanUpService) Run(ctx context.Context) error {
 			srv.cleanUpTmpFiles()
 			srv.deleteExpiredSnapshots()
 			srv.deleteExpiredDashboardVersions()
-			err := srv.ServerLockService.LockAndExecute(ctx, "delete old login attempts",
+			srv.deleteOldAlertStateHistory()
+			srv.deleteUnusedApiKeys()
+			err := srv.ServerLockService.LockAndExecute(ctx, "delete expired alert silences",
+				time.Minute*10, func() {
+					srv.deleteExpiredAlertSilences()
+				})
+			if err != nil {
+				srv.log.Error("failed to lock and execute cleanup of expired alert silences", "error", err)
+			}
+			err = srv.ServerLockService.LockAndExecute(ctx, "delete old login attempts",
 				time.Minute*10, func() {
 					srv.deleteOldLoginAttempts()
 				})
@@ -101,6 +110,21 @@ func (srv *CleanUpService) deleteExpiredSnapshots() {
 	}
 }
 
+func (srv *CleanUpService) deleteUnusedApiKeys() {
+	if srv.Cfg.ApiKeyDeleteUnusedAfterDays <= 0 {
+		return
+	}
+
+	cmd := models.DeleteUnusedApiKeysCommand{
+		UnusedSince: time.Now().AddDate(0, 0, -srv.Cfg.ApiKeyDeleteUnusedAfterDays),
+	}
+	if err := bus.Dispatch(&cmd); err != nil {
+		srv.log.Error("Failed to delete unused api keys", "error", err.Error())
+	} else if cmd.DeletedRows > 0 {
+		srv.log.Info("Deleted unused api keys", "rows affected", cmd.DeletedRows)
+	}
+}
+
 func (srv *CleanUpService) deleteExpiredDashboardVersions() {
 	cmd := models.DeleteExpiredVersionsCommand{}
 	if err := bus.Dispatch(&cmd); err != nil {
@@ -110,6 +134,36 @@ func (srv *CleanUpService) deleteExpiredDashboardVersions() {
 	}
 }
 
+func (srv *CleanUpService) deleteExpiredAlertSilences() {
+	if setting.AlertingExpiredSilenceRetention <= 0 {
+		return
+	}
+
+	cmd := models.DeleteExpiredAlertSilencesCommand{
+		OlderThan: time.Now().Add(-setting.AlertingExpiredSilenceRetention),
+	}
+	if err := bus.Dispatch(&cmd); err != nil {
+		srv.log.Error("Failed to delete expired alert silences", "error", err.Error())
+	} else {
+		srv.log.Debug("Deleted expired alert silences", "rows affected", cmd.DeletedRows)
+	}
+}
+
+func (srv *CleanUpService) deleteOldAlertStateHistory() {
+	if setting.AlertingStateHistoryRetention <= 0 {
+		return
+	}
+
+	cmd := models.DeleteOldAlertStateHistoryCommand{
+		OlderThan: time.Now().Add(-setting.AlertingStateHistoryRetention),
+	}
+	if err := bus.Dispatch(&cmd); err != nil {
+		srv.log.Error("Failed to delete old alert state history", "error", err.Error())
+	} else {
+		srv.log.Debug("Deleted old alert state history", "rows affected", cmd.DeletedRows)
+	}
+}
+
 func (srv *CleanUpService) deleteOldLoginAttempts() {
 	if srv.Cfg.DisableBruteForceLoginProtection {
 		return
@@ -426,6 +426,18 @@ func (server *Server) buildGrafanaUser(user *ldap.Entry) (*models.ExternalUserIn
 		}
 	}
 
+	// only sync teams when team mappings are configured, so that
+	// memberships managed by other means are left alone
+	if len(server.Config.Teams) > 0 {
+		extUser.Teams = []*models.ExternalTeam{}
+
+		for _, team := range server.Config.Teams {
+			if isMemberOf(memberOf, team.GroupDN) {
+				extUser.Teams = append(extUser.Teams, &models.ExternalTeam{OrgId: team.OrgId, Name: team.TeamName})
+			}
+		}
+	}
+
 	return extUser, nil
 }
 
@@ -115,6 +115,70 @@ func TestLDAPPrivateMethods(t *testing.T) {
 			So(err, ShouldBeNil)
 			So(result[0].Name, ShouldEqual, "Roel")
 		})
+
+		Convey("with team mappings", func() {
+			server := &Server{
+				Config: &ServerConfig{
+					Attr: AttributeMap{
+						Username: "username",
+						MemberOf: "memberof",
+					},
+					SearchBaseDNs: []string{"BaseDNHere"},
+					Teams: []*GroupToTeam{
+						{GroupDN: "admins", OrgId: 1, TeamName: "Admins"},
+						{GroupDN: "editors", OrgId: 1, TeamName: "Editors"},
+						{GroupDN: "*", OrgId: 2, TeamName: "Everyone"},
+					},
+				},
+				Connection: &MockConnection{},
+				log:        log.New("test-logger"),
+			}
+
+			entry := ldap.Entry{
+				DN: "dn",
+				Attributes: []*ldap.EntryAttribute{
+					{Name: "username", Values: []string{"roelgerrits"}},
+					{Name: "memberof", Values: []string{"admins"}},
+				},
+			}
+			users := []*ldap.Entry{&entry}
+
+			result, err := server.serializeUsers(users)
+
+			So(err, ShouldBeNil)
+			So(result[0].Teams, ShouldResemble, []*models.ExternalTeam{
+				{OrgId: 1, Name: "Admins"},
+				{OrgId: 2, Name: "Everyone"},
+			})
+		})
+
+		Convey("without team mappings", func() {
+			server := &Server{
+				Config: &ServerConfig{
+					Attr: AttributeMap{
+						Username: "username",
+						MemberOf: "memberof",
+					},
+					SearchBaseDNs: []string{"BaseDNHere"},
+				},
+				Connection: &MockConnection{},
+				log:        log.New("test-logger"),
+			}
+
+			entry := ldap.Entry{
+				DN: "dn",
+				Attributes: []*ldap.EntryAttribute{
+					{Name: "username", Values: []string{"roelgerrits"}},
+					{Name: "memberof", Values: []string{"admins"}},
+				},
+			}
+			users := []*ldap.Entry{&entry}
+
+			result, err := server.serializeUsers(users)
+
+			So(err, ShouldBeNil)
+			So(result[0].Teams, ShouldBeNil)
+		})
 	})
 
 	Convey("validateGrafanaUser()", t, func() {
@@ -41,6 +41,7 @@ type ServerConfig struct {
 	GroupSearchBaseDNs             []string `toml:"group_search_base_dns"`
 
 	Groups []*GroupToOrgRole `toml:"group_mappings"`
+	Teams  []*GroupToTeam    `toml:"team_mappings"`
 }
 
 // AttributeMap is a struct representation for LDAP "attributes" setting
@@ -64,6 +65,14 @@ type GroupToOrgRole struct {
 	OrgRole models.RoleType `toml:"org_role"`
 }
 
+// GroupToTeam is a struct representation of LDAP
+// config "team_mappings" setting
+type GroupToTeam struct {
+	GroupDN  string `toml:"group_dn"`
+	OrgId    int64  `toml:"org_id"`
+	TeamName string `toml:"team_name"`
+}
+
 // logger for all LDAP stuff
 var logger = log.New("ldap")
 
@@ -152,6 +161,17 @@ func readConfig(configFile string) (*Config, error) {
 				groupMap.OrgId = 1
 			}
 		}
+
+		for _, teamMap := range server.Teams {
+			err = assertNotEmptyCfg(teamMap.TeamName, "team_name")
+			if err != nil {
+				return nil, errutil.Wrap("Failed to validate team_mappings section", err)
+			}
+
+			if teamMap.OrgId == 0 {
+				teamMap.OrgId = 1
+			}
+		}
 	}
 
 	return result, nil
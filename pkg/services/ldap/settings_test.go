@@ -11,6 +11,8 @@ func TestReadingLDAPSettings(t *testing.T) {
 	config, err := readConfig("testdata/ldap.toml")
 	assert.Nil(t, err, "No error when reading ldap config")
 	assert.EqualValues(t, "127.0.0.1", config.Servers[0].Host)
+	assert.EqualValues(t, "Admins", config.Servers[0].Teams[0].TeamName)
+	assert.EqualValues(t, 1, config.Servers[0].Teams[0].OrgId, "Team mappings default to the main org")
 }
 
 func TestReadingLDAPSettingsWithEnvVariable(t *testing.T) {
@@ -0,0 +1,273 @@
+package ldapsync
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/registry"
+	"github.com/grafana/grafana/pkg/services/multildap"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// minSyncInterval is the minimal time between two syncs, it makes sure
+// only one server in a HA setup runs the scheduled sync
+const minSyncInterval = time.Minute * 5
+
+const usersPageSize = 500
+
+// prevSyncCacheKey is the remote cache key of the result of the last sync, the remote
+// cache shares it between the servers of a HA setup and keeps it across restarts
+const prevSyncCacheKey = "ldap-sync-result"
+
+const prevSyncExpiration = time.Hour * 24 * 30
+
+var (
+	getLDAPConfig = multildap.GetConfig
+	newLDAP       = multildap.New
+)
+
+// SyncInfo reports the schedule of the LDAP sync and the result of its last run
+type SyncInfo struct {
+	Enabled  bool        `json:"enabled"`
+	Schedule string      `json:"schedule"`
+	NextSync *time.Time  `json:"nextSync"`
+	PrevSync *SyncResult `json:"prevSync"`
+}
+
+// SyncResult is the result of a single LDAP sync run
+type SyncResult struct {
+	Instance       string        `json:"instance"`
+	Started        time.Time     `json:"started"`
+	Elapsed        string        `json:"elapsed"`
+	UpdatedUserIds []int64       `json:"UpdatedUserIds"`
+	MissingUserIds []int64       `json:"MissingUserIds"`
+	FailedUsers    []*FailedUser `json:"FailedUsers"`
+	Error          string        `json:"error,omitempty"`
+}
+
+// FailedUser is an LDAP user that couldn't be synced
+type FailedUser struct {
+	Login string `json:"Login"`
+	Error string `json:"Error"`
+}
+
+// LDAPSyncService periodically syncs all users that have logged in with LDAP against the
+// LDAP servers, so that changes in LDAP are applied without waiting for the users to log in.
+// Users that are no longer found in LDAP are disabled and logged out.
+type LDAPSyncService struct {
+	Bus               bus.Bus                       `inject:""`
+	ServerLockService *serverlock.ServerLockService `inject:""`
+	AuthTokenService  models.UserTokenService       `inject:""`
+	RemoteCache       *remotecache.RemoteCache      `inject:""`
+
+	log      log.Logger
+	schedule cron.Schedule
+	mu       sync.Mutex
+	nextSync *time.Time
+}
+
+func init() {
+	registry.RegisterService(&LDAPSyncService{})
+	remotecache.Register(&SyncResult{})
+}
+
+func (s *LDAPSyncService) IsDisabled() bool {
+	return !setting.LDAPEnabled || !setting.LDAPActiveSyncEnabled
+}
+
+func (s *LDAPSyncService) Init() error {
+	s.log = log.New("ldapsync")
+
+	parser := cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+	schedule, err := parser.Parse(setting.LDAPSyncCron)
+	if err != nil {
+		return fmt.Errorf("invalid LDAP sync_cron %q: %w", setting.LDAPSyncCron, err)
+	}
+
+	s.schedule = schedule
+	return nil
+}
+
+func (s *LDAPSyncService) Run(ctx context.Context) error {
+	for {
+		next := s.schedule.Next(time.Now())
+
+		s.mu.Lock()
+		s.nextSync = &next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-timer.C:
+			err := s.ServerLockService.LockAndExecute(ctx, "ldap sync", minSyncInterval, func() {
+				s.sync(ctx)
+			})
+			if err != nil {
+				s.log.Error("Failed to lock and execute LDAP sync", "error", err)
+			}
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// SyncInfo returns the schedule and the result of the last sync, whichever server ran it.
+func (s *LDAPSyncService) SyncInfo() *SyncInfo {
+	s.mu.Lock()
+	info := &SyncInfo{
+		Enabled:  !s.IsDisabled(),
+		Schedule: setting.LDAPSyncCron,
+		NextSync: s.nextSync,
+	}
+	s.mu.Unlock()
+
+	if !info.Enabled {
+		return info
+	}
+
+	prevSync, err := s.RemoteCache.Get(prevSyncCacheKey)
+	if err != nil {
+		if err != remotecache.ErrCacheItemNotFound {
+			s.log.Error("Failed to get the result of the last LDAP sync", "error", err)
+		}
+		return info
+	}
+
+	if result, ok := prevSync.(*SyncResult); ok {
+		info.PrevSync = result
+	}
+
+	return info
+}
+
+func (s *LDAPSyncService) sync(ctx context.Context) {
+	result := &SyncResult{
+		Instance:       setting.InstanceName,
+		Started:        time.Now(),
+		UpdatedUserIds: []int64{},
+		MissingUserIds: []int64{},
+		FailedUsers:    []*FailedUser{},
+	}
+
+	s.log.Info("Starting LDAP sync")
+	if err := s.syncUsers(ctx, result); err != nil {
+		s.log.Error("LDAP sync failed", "error", err)
+		result.Error = err.Error()
+	}
+	result.Elapsed = time.Since(result.Started).String()
+
+	s.log.Info("LDAP sync done", "updated", len(result.UpdatedUserIds), "missing", len(result.MissingUserIds),
+		"failed", len(result.FailedUsers), "elapsed", result.Elapsed)
+
+	if err := s.RemoteCache.Set(prevSyncCacheKey, result, prevSyncExpiration); err != nil {
+		s.log.Error("Failed to store the result of the LDAP sync", "error", err)
+	}
+}
+
+func (s *LDAPSyncService) syncUsers(ctx context.Context, result *SyncResult) error {
+	ldapConfig, err := getLDAPConfig()
+	if err != nil {
+		return err
+	}
+
+	ldapServer := newLDAP(ldapConfig.Servers)
+
+	// users of a server that can't be reached would look like they were removed from LDAP
+	statuses, err := ldapServer.Ping()
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		if !status.Available {
+			return fmt.Errorf("LDAP server %s:%d is not available: %v", status.Host, status.Port, status.Error)
+		}
+	}
+
+	users, err := s.getLDAPUsers()
+	if err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		return nil
+	}
+
+	logins := make([]string, 0, len(users))
+	for _, user := range users {
+		logins = append(logins, user.Login)
+	}
+
+	ldapUsers, err := ldapServer.Users(logins)
+	if err != nil {
+		return err
+	}
+
+	ldapUsersByLogin := make(map[string]*models.ExternalUserInfo, len(ldapUsers))
+	for _, ldapUser := range ldapUsers {
+		ldapUsersByLogin[strings.ToLower(ldapUser.Login)] = ldapUser
+	}
+
+	for _, user := range users {
+		ldapUser, ok := ldapUsersByLogin[strings.ToLower(user.Login)]
+		if !ok {
+			if user.Login == setting.AdminUser {
+				s.log.Warn("Refusing to disable Grafana super admin missing in LDAP", "login", user.Login)
+				continue
+			}
+			if !user.IsDisabled {
+				result.MissingUserIds = append(result.MissingUserIds, user.Id)
+			}
+			continue
+		}
+
+		upsertCmd := &models.UpsertUserCommand{
+			ExternalUser:  ldapUser,
+			SignupAllowed: setting.LDAPAllowSignup,
+		}
+		if err := s.Bus.Dispatch(upsertCmd); err != nil {
+			s.log.Error("Failed to sync user with LDAP", "login", user.Login, "error", err)
+			result.FailedUsers = append(result.FailedUsers, &FailedUser{Login: user.Login, Error: err.Error()})
+			continue
+		}
+
+		result.UpdatedUserIds = append(result.UpdatedUserIds, user.Id)
+	}
+
+	if len(result.MissingUserIds) == 0 {
+		return nil
+	}
+
+	s.log.Info("Disabling users missing in LDAP", "userIds", result.MissingUserIds)
+	if err := s.Bus.Dispatch(&models.BatchDisableUsersCommand{UserIds: result.MissingUserIds, IsDisabled: true}); err != nil {
+		return err
+	}
+
+	return s.AuthTokenService.BatchRevokeAllUserTokens(ctx, result.MissingUserIds)
+}
+
+// getLDAPUsers returns all users that have logged in with LDAP.
+func (s *LDAPSyncService) getLDAPUsers() ([]*models.UserSearchHitDTO, error) {
+	var users []*models.UserSearchHitDTO
+
+	for page := 1; ; page++ {
+		query := &models.SearchUsersQuery{AuthModule: models.AuthModuleLDAP, Page: page, Limit: usersPageSize}
+		if err := s.Bus.Dispatch(query); err != nil {
+			return nil, err
+		}
+
+		users = append(users, query.Result.Users...)
+		if len(query.Result.Users) < usersPageSize {
+			return users, nil
+		}
+	}
+}
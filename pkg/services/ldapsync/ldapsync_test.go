@@ -0,0 +1,158 @@
+package ldapsync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/auth"
+	"github.com/grafana/grafana/pkg/services/ldap"
+	"github.com/grafana/grafana/pkg/services/multildap"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ldapMock struct {
+	multildap.IMultiLDAP
+
+	statuses []*multildap.ServerStatus
+	users    []*models.ExternalUserInfo
+}
+
+func (m *ldapMock) Ping() ([]*multildap.ServerStatus, error) {
+	return m.statuses, nil
+}
+
+func (m *ldapMock) Users(logins []string) ([]*models.ExternalUserInfo, error) {
+	return m.users, nil
+}
+
+func setupSyncService(t *testing.T, ldapServer *ldapMock) (*LDAPSyncService, *[]int64, *[]int64, *[]int64) {
+	t.Helper()
+
+	getLDAPConfig = func() (*ldap.Config, error) {
+		return &ldap.Config{}, nil
+	}
+	newLDAP = func(_ []*ldap.ServerConfig) multildap.IMultiLDAP {
+		return ldapServer
+	}
+	ldapEnabled, syncEnabled := setting.LDAPEnabled, setting.LDAPActiveSyncEnabled
+	setting.LDAPEnabled, setting.LDAPActiveSyncEnabled = true, true
+	t.Cleanup(func() {
+		getLDAPConfig = multildap.GetConfig
+		newLDAP = multildap.New
+		setting.LDAPEnabled, setting.LDAPActiveSyncEnabled = ldapEnabled, syncEnabled
+	})
+
+	var upserted, disabled, revoked []int64
+
+	b := bus.New()
+	b.AddHandler(func(query *models.SearchUsersQuery) error {
+		query.Result.Users = []*models.UserSearchHitDTO{
+			{Id: 1, Login: "found"},
+			{Id: 2, Login: "missing"},
+			{Id: 3, Login: "failing"},
+			{Id: 4, Login: "disabled", IsDisabled: true},
+			{Id: 5, Login: setting.AdminUser},
+		}
+		return nil
+	})
+	b.AddHandler(func(cmd *models.UpsertUserCommand) error {
+		if cmd.ExternalUser.Login == "failing" {
+			return errors.New("upsert failed")
+		}
+		upserted = append(upserted, cmd.ExternalUser.UserId)
+		return nil
+	})
+	b.AddHandler(func(cmd *models.BatchDisableUsersCommand) error {
+		disabled = append(disabled, cmd.UserIds...)
+		return nil
+	})
+
+	tokenService := auth.NewFakeUserAuthTokenService()
+	tokenService.BatchRevokedTokenProvider = func(ctx context.Context, userIds []int64) error {
+		revoked = append(revoked, userIds...)
+		return nil
+	}
+
+	s := &LDAPSyncService{
+		Bus:              b,
+		AuthTokenService: tokenService,
+		RemoteCache:      remotecache.NewFakeStore(t),
+		log:              log.New("ldapsync.test"),
+	}
+	return s, &upserted, &disabled, &revoked
+}
+
+func TestLDAPSync(t *testing.T) {
+	t.Run("Should update found users and disable missing users", func(t *testing.T) {
+		ldapServer := &ldapMock{
+			statuses: []*multildap.ServerStatus{{Host: "localhost", Port: 389, Available: true}},
+			users: []*models.ExternalUserInfo{
+				{Login: "Found", UserId: 1},
+				{Login: "failing", UserId: 3},
+			},
+		}
+		s, upserted, disabled, revoked := setupSyncService(t, ldapServer)
+
+		s.sync(context.Background())
+
+		result := s.SyncInfo().PrevSync
+		require.NotNil(t, result)
+		assert.Empty(t, result.Error)
+		assert.Equal(t, []int64{1}, result.UpdatedUserIds)
+		assert.Equal(t, []int64{2}, result.MissingUserIds)
+		require.Len(t, result.FailedUsers, 1)
+		assert.Equal(t, "failing", result.FailedUsers[0].Login)
+
+		assert.Equal(t, []int64{1}, *upserted)
+		assert.Equal(t, []int64{2}, *disabled)
+		assert.Equal(t, []int64{2}, *revoked)
+	})
+
+	t.Run("Should not sync when an LDAP server is unavailable", func(t *testing.T) {
+		ldapServer := &ldapMock{
+			statuses: []*multildap.ServerStatus{
+				{Host: "localhost", Port: 389, Available: true},
+				{Host: "localhost", Port: 636, Available: false, Error: errors.New("connection refused")},
+			},
+		}
+		s, upserted, disabled, revoked := setupSyncService(t, ldapServer)
+
+		s.sync(context.Background())
+
+		result := s.SyncInfo().PrevSync
+		require.NotNil(t, result)
+		assert.Contains(t, result.Error, "localhost:636 is not available")
+		assert.Empty(t, *upserted)
+		assert.Empty(t, *disabled)
+		assert.Empty(t, *revoked)
+	})
+
+	t.Run("Should report the last sync on every server", func(t *testing.T) {
+		ldapServer := &ldapMock{
+			statuses: []*multildap.ServerStatus{{Host: "localhost", Port: 389, Available: true}},
+			users:    []*models.ExternalUserInfo{{Login: "found", UserId: 1}},
+		}
+		s, _, _, _ := setupSyncService(t, ldapServer)
+
+		instanceName := setting.InstanceName
+		setting.InstanceName = "grafana-1"
+		t.Cleanup(func() { setting.InstanceName = instanceName })
+
+		other := &LDAPSyncService{RemoteCache: s.RemoteCache, log: log.New("ldapsync.test")}
+		assert.Nil(t, other.SyncInfo().PrevSync)
+
+		s.sync(context.Background())
+
+		result := other.SyncInfo().PrevSync
+		require.NotNil(t, result)
+		assert.Equal(t, "grafana-1", result.Instance)
+		assert.Equal(t, []int64{1}, result.UpdatedUserIds)
+	})
+}
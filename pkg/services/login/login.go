@@ -108,6 +108,10 @@ func (ls *LoginService) UpsertUser(cmd *models.UpsertUserCommand) error {
 		}
 	}
 
+	if err := syncTeams(cmd.Result, extUser); err != nil {
+		return err
+	}
+
 	err := ls.Bus.Dispatch(&models.SyncTeamsCommand{
 		User:         cmd.Result,
 		ExternalUser: extUser,
@@ -250,3 +254,60 @@ func syncOrgRoles(user *models.User, extUser *models.ExternalUserInfo) error {
 
 	return nil
 }
+
+// syncTeams adds the user to the teams the auth provider maps it to, and removes it from
+// teams it was added to by an auth provider before but no longer is a member of.
+// Team memberships that were added by hand are never removed.
+func syncTeams(user *models.User, extUser *models.ExternalUserInfo) error {
+	// don't sync teams if the auth provider doesn't map any
+	if extUser.Teams == nil {
+		return nil
+	}
+
+	teamOrgIds := map[int64]int64{}
+	for _, team := range extUser.Teams {
+		query := &models.SearchTeamsQuery{OrgId: team.OrgId, Name: team.Name, Limit: 1, Page: 1}
+		if err := bus.Dispatch(query); err != nil {
+			return err
+		}
+
+		if len(query.Result.Teams) == 0 {
+			logger.Warn("Team in team mapping not found", "orgId", team.OrgId, "team", team.Name)
+			continue
+		}
+
+		teamOrgIds[query.Result.Teams[0].Id] = team.OrgId
+	}
+
+	membersQuery := &models.GetTeamMembersQuery{UserId: user.Id}
+	if err := bus.Dispatch(membersQuery); err != nil {
+		return err
+	}
+
+	memberOf := map[int64]bool{}
+	for _, member := range membersQuery.Result {
+		memberOf[member.TeamId] = true
+
+		if _, ok := teamOrgIds[member.TeamId]; ok || !member.External {
+			continue
+		}
+
+		cmd := &models.RemoveTeamMemberCommand{OrgId: member.OrgId, TeamId: member.TeamId, UserId: user.Id}
+		if err := bus.Dispatch(cmd); err != nil && err != models.ErrTeamMemberNotFound {
+			return err
+		}
+	}
+
+	for teamId, orgId := range teamOrgIds {
+		if memberOf[teamId] {
+			continue
+		}
+
+		cmd := &models.AddTeamMemberCommand{OrgId: orgId, TeamId: teamId, UserId: user.Id, External: true}
+		if err := bus.Dispatch(cmd); err != nil && err != models.ErrTeamMemberAlreadyAdded {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -75,6 +75,68 @@ func Test_syncOrgRoles_whenTryingToRemoveLastOrgLogsError(t *testing.T) {
 	require.Equal(t, models.ErrLastOrgAdmin.Error(), logOutput)
 }
 
+func Test_syncTeams(t *testing.T) {
+	user := createSimpleUser()
+
+	bus.ClearBusHandlers()
+	defer bus.ClearBusHandlers()
+	bus.AddHandler("test", func(q *models.SearchTeamsQuery) error {
+		teamIds := map[string]int64{"Admins": 1, "Editors": 2}
+		q.Result.Teams = []*models.TeamDTO{}
+		if id, ok := teamIds[q.Name]; ok {
+			q.Result.Teams = append(q.Result.Teams, &models.TeamDTO{Id: id, OrgId: q.OrgId, Name: q.Name})
+		}
+		return nil
+	})
+	bus.AddHandler("test", func(q *models.GetTeamMembersQuery) error {
+		q.Result = []*models.TeamMemberDTO{
+			{OrgId: 1, TeamId: 1, UserId: user.Id, External: true},
+			{OrgId: 1, TeamId: 3, UserId: user.Id, External: true},
+			{OrgId: 1, TeamId: 4, UserId: user.Id},
+		}
+		return nil
+	})
+
+	var added []*models.AddTeamMemberCommand
+	bus.AddHandler("test", func(cmd *models.AddTeamMemberCommand) error {
+		added = append(added, cmd)
+		return nil
+	})
+	var removed []*models.RemoveTeamMemberCommand
+	bus.AddHandler("test", func(cmd *models.RemoveTeamMemberCommand) error {
+		removed = append(removed, cmd)
+		return nil
+	})
+
+	t.Run("Should add and remove external team memberships", func(t *testing.T) {
+		added, removed = nil, nil
+		externalUser := createSimpleExternalUser()
+		externalUser.Teams = []*models.ExternalTeam{
+			{OrgId: 1, Name: "Admins"},
+			{OrgId: 1, Name: "Editors"},
+			{OrgId: 1, Name: "Missing"},
+		}
+
+		err := syncTeams(&user, &externalUser)
+		require.NoError(t, err)
+		require.Len(t, added, 1)
+		require.Equal(t, int64(2), added[0].TeamId)
+		require.True(t, added[0].External)
+		require.Len(t, removed, 1)
+		require.Equal(t, int64(3), removed[0].TeamId)
+	})
+
+	t.Run("Should not sync teams when the auth provider doesn't map any", func(t *testing.T) {
+		added, removed = nil, nil
+		externalUser := createSimpleExternalUser()
+
+		err := syncTeams(&user, &externalUser)
+		require.NoError(t, err)
+		require.Empty(t, added)
+		require.Empty(t, removed)
+	})
+}
+
 func createSimpleUser() models.User {
 	user := models.User{
 		Id: 1,
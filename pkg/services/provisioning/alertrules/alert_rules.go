@@ -0,0 +1,111 @@
+package alertrules
+
+import (
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// Provision creates, updates and deletes the alert rules
+// defined in the provisioning files of the directory.
+func Provision(configDirectory string) error {
+	ap := newAlertRuleProvisioner(log.New("provisioning.alertrules"))
+	return ap.applyChanges(configDirectory)
+}
+
+type AlertRuleProvisioner struct {
+	log         log.Logger
+	cfgProvider *configReader
+}
+
+func newAlertRuleProvisioner(log log.Logger) AlertRuleProvisioner {
+	return AlertRuleProvisioner{
+		log:         log,
+		cfgProvider: &configReader{log: log},
+	}
+}
+
+func (ap *AlertRuleProvisioner) apply(cfg *alertRulesAsConfig) error {
+	if err := ap.deleteAlertRules(cfg.DeleteAlertRules); err != nil {
+		return err
+	}
+
+	if err := ap.mergeAlertRules(cfg.AlertRules); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func resolveOrgId(orgId int64, orgName string) (int64, error) {
+	if orgId == 0 && orgName != "" {
+		getOrg := &models.GetOrgByNameQuery{Name: orgName}
+		if err := bus.Dispatch(getOrg); err != nil {
+			return 0, err
+		}
+		return getOrg.Result.Id, nil
+	}
+
+	if orgId < 0 {
+		return 1, nil
+	}
+
+	return orgId, nil
+}
+
+func (ap *AlertRuleProvisioner) deleteAlertRules(rulesToDelete []*deleteAlertRuleConfig) error {
+	for _, rule := range rulesToDelete {
+		ap.log.Info("Deleting alert rule", "uid", rule.Uid)
+
+		orgId, err := resolveOrgId(rule.OrgId, rule.OrgName)
+		if err != nil {
+			return err
+		}
+
+		cmd := &models.DeleteAlertRuleCommand{OrgId: orgId, Uid: rule.Uid, Overwrite: true}
+		if err := bus.Dispatch(cmd); err != nil && err != models.ErrAlertRuleNotFound {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ap *AlertRuleProvisioner) mergeAlertRules(rulesToMerge []*alertRuleFromConfig) error {
+	for _, rule := range rulesToMerge {
+		orgId, err := resolveOrgId(rule.OrgId, rule.OrgName)
+		if err != nil {
+			return err
+		}
+
+		ap.log.Debug("saving alert rule from configuration", "name", rule.Name, "uid", rule.Uid)
+		cmd := &models.SaveAlertRuleCommand{
+			Uid:         rule.Uid,
+			OrgId:       orgId,
+			Settings:    rule.SettingsToJson(),
+			Overwrite:   true,
+			Provisioned: true,
+		}
+
+		if err := bus.Dispatch(cmd); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ap *AlertRuleProvisioner) applyChanges(configPath string) error {
+	configs, err := ap.cfgProvider.readConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	for _, cfg := range configs {
+		if err := ap.apply(cfg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+package alertrules
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"gopkg.in/yaml.v2"
+)
+
+type configReader struct {
+	log log.Logger
+}
+
+func (cr *configReader) readConfig(path string) ([]*alertRulesAsConfig, error) {
+	var alertRules []*alertRulesAsConfig
+	cr.log.Debug("Looking for alert rule provisioning files", "path", path)
+
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		cr.log.Error("Can't read alert rule provisioning files from directory", "path", path, "error", err)
+		return alertRules, nil
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml") {
+			cr.log.Debug("Parsing alert rules provisioning file", "path", path, "file.Name", file.Name())
+			rules, err := cr.parseAlertRuleConfig(path, file)
+			if err != nil {
+				return nil, err
+			}
+
+			if rules != nil {
+				alertRules = append(alertRules, rules)
+			}
+		}
+	}
+
+	cr.log.Debug("Validating alert rules")
+	if err = validateRequiredField(alertRules); err != nil {
+		return nil, err
+	}
+
+	checkOrgIdAndOrgName(alertRules)
+
+	return alertRules, nil
+}
+
+func (cr *configReader) parseAlertRuleConfig(path string, file os.FileInfo) (*alertRulesAsConfig, error) {
+	filename, _ := filepath.Abs(filepath.Join(path, file.Name()))
+	yamlFile, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg *alertRulesAsConfigV0
+	err = yaml.Unmarshal(yamlFile, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg.mapToAlertRuleFromConfig(), nil
+}
+
+func checkOrgIdAndOrgName(alertRules []*alertRulesAsConfig) {
+	for i := range alertRules {
+		for _, rule := range alertRules[i].AlertRules {
+			if rule.OrgId < 1 {
+				if rule.OrgName == "" {
+					rule.OrgId = 1
+				} else {
+					rule.OrgId = 0
+				}
+			}
+		}
+
+		for _, rule := range alertRules[i].DeleteAlertRules {
+			if rule.OrgId < 1 {
+				if rule.OrgName == "" {
+					rule.OrgId = 1
+				} else {
+					rule.OrgId = 0
+				}
+			}
+		}
+	}
+}
+
+func validateRequiredField(alertRules []*alertRulesAsConfig) error {
+	for i := range alertRules {
+		var errStrings []string
+		for index, rule := range alertRules[i].AlertRules {
+			if rule.Name == "" {
+				errStrings = append(
+					errStrings,
+					fmt.Sprintf("Added alert rule item %d in configuration doesn't contain required field name", index+1),
+				)
+			}
+
+			if rule.Uid == "" {
+				errStrings = append(
+					errStrings,
+					fmt.Sprintf("Added alert rule item %d in configuration doesn't contain required field uid", index+1),
+				)
+			}
+
+			if len(rule.Conditions) == 0 {
+				errStrings = append(
+					errStrings,
+					fmt.Sprintf("Added alert rule item %d in configuration doesn't contain required field conditions", index+1),
+				)
+			}
+
+			for conditionIndex, condition := range rule.Conditions {
+				if !hasValidQueryParams(condition) {
+					errStrings = append(
+						errStrings,
+						fmt.Sprintf("Added alert rule item %d in configuration has invalid query params in condition %d, expected ref id, from and to", index+1, conditionIndex+1),
+					)
+				}
+			}
+		}
+
+		for index, rule := range alertRules[i].DeleteAlertRules {
+			if rule.Uid == "" {
+				errStrings = append(
+					errStrings,
+					fmt.Sprintf("Deleted alert rule item %d in configuration doesn't contain required field uid", index+1),
+				)
+			}
+		}
+
+		if len(errStrings) != 0 {
+			return fmt.Errorf(strings.Join(errStrings, "\n"))
+		}
+	}
+
+	return nil
+}
+
+// hasValidQueryParams checks that the queries of a condition have the ref id, from and to params.
+// Math conditions hold several queries, the other conditions a single one.
+func hasValidQueryParams(condition map[string]interface{}) bool {
+	queries, _ := condition["queries"].([]interface{})
+	if len(queries) == 0 {
+		queries = []interface{}{condition}
+	}
+
+	for _, rawQuery := range queries {
+		queryContainer, _ := rawQuery.(map[string]interface{})
+		query, _ := queryContainer["query"].(map[string]interface{})
+		params, _ := query["params"].([]interface{})
+		if len(params) < 3 {
+			return false
+		}
+
+		for _, param := range params[:3] {
+			if _, ok := param.(string); !ok {
+				return false
+			}
+		}
+	}
+
+	return true
+}
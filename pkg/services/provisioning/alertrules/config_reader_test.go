@@ -0,0 +1,109 @@
+package alertrules
+
+import (
+	"os"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	_ "github.com/grafana/grafana/pkg/services/alerting/conditions"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var (
+	correctProperties = "./testdata/test-configs/correct-properties"
+	noRequiredFields  = "./testdata/test-configs/no-required-fields"
+	invalidParams     = "./testdata/test-configs/invalid-query-params"
+)
+
+func TestAlertRulesAsConfig(t *testing.T) {
+	logger := log.New("fake.log")
+
+	Convey("Testing alert rules as configuration", t, func() {
+		sqlstore.InitTestDB(t)
+
+		Convey("Can read correct properties", func() {
+			_ = os.Setenv("TEST_VAR", "90")
+			cfgProvider := &configReader{log: logger}
+			cfg, err := cfgProvider.readConfig(correctProperties)
+			_ = os.Unsetenv("TEST_VAR")
+			So(err, ShouldBeNil)
+			So(cfg, ShouldHaveLength, 1)
+
+			rules := cfg[0].AlertRules
+			So(rules, ShouldHaveLength, 1)
+
+			rule := rules[0]
+			So(rule.Name, ShouldEqual, "High CPU usage")
+			So(rule.Uid, ShouldEqual, "cpu-usage")
+			So(rule.OrgId, ShouldEqual, 1)
+			So(rule.Message, ShouldEqual, "CPU usage is above 90%")
+			So(rule.Frequency, ShouldEqual, "1m")
+			So(rule.For, ShouldEqual, "5m")
+			So(rule.NoDataState, ShouldEqual, "keep_state")
+			So(rule.Notifications, ShouldResemble, []string{"notifier1"})
+			So(rule.Tags, ShouldResemble, map[string]string{"team": "backend"})
+			So(rule.Conditions, ShouldHaveLength, 1)
+
+			settings := rule.SettingsToJson()
+			So(settings.Get("notifications").GetIndex(0).Get("uid").MustString(), ShouldEqual, "notifier1")
+			So(settings.Get("alertRuleTags").Get("team").MustString(), ShouldEqual, "backend")
+			So(settings.Get("conditions").GetIndex(0).Get("query").Get("datasource").MustString(), ShouldEqual, "Graphite")
+
+			So(cfg[0].DeleteAlertRules, ShouldHaveLength, 1)
+			So(cfg[0].DeleteAlertRules[0].Uid, ShouldEqual, "old-rule")
+		})
+
+		Convey("Should fail on missing required fields", func() {
+			cfgProvider := &configReader{log: logger}
+			_, err := cfgProvider.readConfig(noRequiredFields)
+			So(err, ShouldNotBeNil)
+
+			errString := err.Error()
+			So(errString, ShouldContainSubstring, "Added alert rule item 1 in configuration doesn't contain required field name")
+			So(errString, ShouldContainSubstring, "Added alert rule item 2 in configuration doesn't contain required field uid")
+			So(errString, ShouldContainSubstring, "Added alert rule item 2 in configuration doesn't contain required field conditions")
+			So(errString, ShouldContainSubstring, "Deleted alert rule item 1 in configuration doesn't contain required field uid")
+		})
+
+		Convey("Should fail on invalid query params", func() {
+			cfgProvider := &configReader{log: logger}
+			_, err := cfgProvider.readConfig(invalidParams)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "Added alert rule item 1 in configuration has invalid query params in condition 1")
+		})
+
+		Convey("Can provision alert rules", func() {
+			err := bus.Dispatch(&models.AddDataSourceCommand{OrgId: 1, Name: "Graphite", Type: "graphite", Access: models.DS_ACCESS_PROXY})
+			So(err, ShouldBeNil)
+
+			notification := &models.CreateAlertNotificationCommand{OrgId: 1, Uid: "notifier1", Name: "slack", Type: "slack", Settings: nil}
+			So(bus.Dispatch(notification), ShouldBeNil)
+
+			ap := newAlertRuleProvisioner(logger)
+			err = ap.applyChanges(correctProperties)
+			So(err, ShouldBeNil)
+
+			query := &models.GetAlertRuleByUidQuery{OrgId: 1, Uid: "cpu-usage"}
+			So(bus.Dispatch(query), ShouldBeNil)
+			So(query.Result.Provisioned, ShouldBeTrue)
+
+			alertQuery := &models.GetAlertByIdQuery{Id: query.Result.AlertId}
+			So(bus.Dispatch(alertQuery), ShouldBeNil)
+			So(alertQuery.Result.Name, ShouldEqual, "High CPU usage")
+			So(alertQuery.Result.Frequency, ShouldEqual, 60)
+
+			Convey("Provisioning again updates the rule", func() {
+				err = ap.applyChanges(correctProperties)
+				So(err, ShouldBeNil)
+
+				rules := &models.GetAlertRulesQuery{OrgId: 1}
+				So(bus.Dispatch(rules), ShouldBeNil)
+				So(rules.Result, ShouldHaveLength, 1)
+				So(rules.Result[0].Version, ShouldEqual, 2)
+			})
+		})
+	})
+}
@@ -0,0 +1,147 @@
+package alertrules
+
+import (
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+// alertRulesAsConfig is normalized data object for alert rules config data. Any config version should be mappable
+// to this type.
+type alertRulesAsConfig struct {
+	AlertRules       []*alertRuleFromConfig
+	DeleteAlertRules []*deleteAlertRuleConfig
+}
+
+type deleteAlertRuleConfig struct {
+	Uid     string
+	OrgId   int64
+	OrgName string
+}
+
+type alertRuleFromConfig struct {
+	Uid                 string
+	OrgId               int64
+	OrgName             string
+	Name                string
+	Message             string
+	Frequency           string
+	For                 string
+	NoDataState         string
+	ExecutionErrorState string
+	Notifications       []string
+	Tags                map[string]string
+	Conditions          []map[string]interface{}
+}
+
+// alertRulesAsConfigV0 is mapping for zero version configs. This is mapped to its normalised version.
+type alertRulesAsConfigV0 struct {
+	AlertRules       []*alertRuleFromConfigV0   `json:"alert_rules" yaml:"alert_rules"`
+	DeleteAlertRules []*deleteAlertRuleConfigV0 `json:"delete_alert_rules" yaml:"delete_alert_rules"`
+}
+
+type deleteAlertRuleConfigV0 struct {
+	Uid     values.StringValue `json:"uid" yaml:"uid"`
+	OrgId   values.Int64Value  `json:"org_id" yaml:"org_id"`
+	OrgName values.StringValue `json:"org_name" yaml:"org_name"`
+}
+
+type alertRuleFromConfigV0 struct {
+	Uid                 values.StringValue    `json:"uid" yaml:"uid"`
+	OrgId               values.Int64Value     `json:"org_id" yaml:"org_id"`
+	OrgName             values.StringValue    `json:"org_name" yaml:"org_name"`
+	Name                values.StringValue    `json:"name" yaml:"name"`
+	Message             values.StringValue    `json:"message" yaml:"message"`
+	Frequency           values.StringValue    `json:"frequency" yaml:"frequency"`
+	For                 values.StringValue    `json:"for" yaml:"for"`
+	NoDataState         values.StringValue    `json:"no_data_state" yaml:"no_data_state"`
+	ExecutionErrorState values.StringValue    `json:"execution_error_state" yaml:"execution_error_state"`
+	Notifications       []values.StringValue  `json:"notifications" yaml:"notifications"`
+	Tags                values.StringMapValue `json:"tags" yaml:"tags"`
+	Conditions          []values.JSONValue    `json:"conditions" yaml:"conditions"`
+}
+
+// SettingsToJson returns the alert rule definition, in the
+// same format as the alert of a graph panel.
+func (rule alertRuleFromConfig) SettingsToJson() *simplejson.Json {
+	settings := simplejson.New()
+	settings.Set("name", rule.Name)
+	settings.Set("message", rule.Message)
+	settings.Set("frequency", rule.Frequency)
+	settings.Set("for", rule.For)
+
+	if rule.NoDataState != "" {
+		settings.Set("noDataState", rule.NoDataState)
+	}
+
+	if rule.ExecutionErrorState != "" {
+		settings.Set("executionErrorState", rule.ExecutionErrorState)
+	}
+
+	notifications := make([]interface{}, 0, len(rule.Notifications))
+	for _, uid := range rule.Notifications {
+		notifications = append(notifications, map[string]interface{}{"uid": uid})
+	}
+	settings.Set("notifications", notifications)
+
+	if len(rule.Tags) > 0 {
+		tags := make(map[string]interface{}, len(rule.Tags))
+		for key, value := range rule.Tags {
+			tags[key] = value
+		}
+		settings.Set("alertRuleTags", tags)
+	}
+
+	conditions := make([]interface{}, 0, len(rule.Conditions))
+	for _, condition := range rule.Conditions {
+		conditions = append(conditions, condition)
+	}
+	settings.Set("conditions", conditions)
+
+	return settings
+}
+
+// mapToAlertRuleFromConfig maps config syntax to normalized alertRulesAsConfig object. Every version
+// of the config syntax should have this function.
+func (cfg *alertRulesAsConfigV0) mapToAlertRuleFromConfig() *alertRulesAsConfig {
+	r := &alertRulesAsConfig{}
+	if cfg == nil {
+		return r
+	}
+
+	for _, rule := range cfg.AlertRules {
+		notifications := make([]string, 0, len(rule.Notifications))
+		for _, uid := range rule.Notifications {
+			notifications = append(notifications, uid.Value())
+		}
+
+		conditions := make([]map[string]interface{}, 0, len(rule.Conditions))
+		for _, condition := range rule.Conditions {
+			conditions = append(conditions, condition.Value())
+		}
+
+		r.AlertRules = append(r.AlertRules, &alertRuleFromConfig{
+			Uid:                 rule.Uid.Value(),
+			OrgId:               rule.OrgId.Value(),
+			OrgName:             rule.OrgName.Value(),
+			Name:                rule.Name.Value(),
+			Message:             rule.Message.Value(),
+			Frequency:           rule.Frequency.Value(),
+			For:                 rule.For.Value(),
+			NoDataState:         rule.NoDataState.Value(),
+			ExecutionErrorState: rule.ExecutionErrorState.Value(),
+			Notifications:       notifications,
+			Tags:                rule.Tags.Value(),
+			Conditions:          conditions,
+		})
+	}
+
+	for _, rule := range cfg.DeleteAlertRules {
+		r.DeleteAlertRules = append(r.DeleteAlertRules, &deleteAlertRuleConfig{
+			Uid:     rule.Uid.Value(),
+			OrgId:   rule.OrgId.Value(),
+			OrgName: rule.OrgName.Value(),
+		})
+	}
+
+	return r
+}
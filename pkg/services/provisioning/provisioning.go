@@ -9,6 +9,7 @@ import (
 	"github.com/grafana/grafana/pkg/util/errutil"
 
 	"github.com/grafana/grafana/pkg/registry"
+	"github.com/grafana/grafana/pkg/services/provisioning/alertrules"
 	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
 	"github.com/grafana/grafana/pkg/services/provisioning/datasources"
 	"github.com/grafana/grafana/pkg/services/provisioning/notifiers"
@@ -18,6 +19,7 @@ import (
 type ProvisioningService interface {
 	ProvisionDatasources() error
 	ProvisionNotifications() error
+	ProvisionAlertRules() error
 	ProvisionDashboards() error
 	GetDashboardProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
@@ -30,6 +32,7 @@ func init() {
 		},
 		notifiers.Provision,
 		datasources.Provision,
+		alertrules.Provision,
 	))
 }
 
@@ -37,12 +40,14 @@ func NewProvisioningServiceImpl(
 	newDashboardProvisioner dashboards.DashboardProvisionerFactory,
 	provisionNotifiers func(string) error,
 	provisionDatasources func(string) error,
+	provisionAlertRules func(string) error,
 ) *provisioningServiceImpl {
 	return &provisioningServiceImpl{
 		log:                     log.New("provisioning"),
 		newDashboardProvisioner: newDashboardProvisioner,
 		provisionNotifiers:      provisionNotifiers,
 		provisionDatasources:    provisionDatasources,
+		provisionAlertRules:     provisionAlertRules,
 	}
 }
 
@@ -54,6 +59,7 @@ type provisioningServiceImpl struct {
 	dashboardProvisioner    dashboards.DashboardProvisioner
 	provisionNotifiers      func(string) error
 	provisionDatasources    func(string) error
+	provisionAlertRules     func(string) error
 	mutex                   sync.Mutex
 }
 
@@ -68,6 +74,11 @@ func (ps *provisioningServiceImpl) Init() error {
 		return err
 	}
 
+	err = ps.ProvisionAlertRules()
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -113,6 +124,12 @@ func (ps *provisioningServiceImpl) ProvisionNotifications() error {
 	return errutil.Wrap("Alert notification provisioning error", err)
 }
 
+func (ps *provisioningServiceImpl) ProvisionAlertRules() error {
+	alertRulesPath := path.Join(ps.Cfg.ProvisioningPath, "alert_rules")
+	err := ps.provisionAlertRules(alertRulesPath)
+	return errutil.Wrap("Alert rule provisioning error", err)
+}
+
 func (ps *provisioningServiceImpl) ProvisionDashboards() error {
 	dashboardPath := path.Join(ps.Cfg.ProvisioningPath, "dashboards")
 	dashProvisioner, err := ps.newDashboardProvisioner(dashboardPath)
@@ -3,6 +3,7 @@ package provisioning
 type Calls struct {
 	ProvisionDatasources                []interface{}
 	ProvisionNotifications              []interface{}
+	ProvisionAlertRules                 []interface{}
 	ProvisionDashboards                 []interface{}
 	GetDashboardProvisionerResolvedPath []interface{}
 	GetAllowUIUpdatesFromConfig         []interface{}
@@ -12,6 +13,7 @@ type ProvisioningServiceMock struct {
 	Calls                                   *Calls
 	ProvisionDatasourcesFunc                func() error
 	ProvisionNotificationsFunc              func() error
+	ProvisionAlertRulesFunc                 func() error
 	ProvisionDashboardsFunc                 func() error
 	GetDashboardProvisionerResolvedPathFunc func(name string) string
 	GetAllowUIUpdatesFromConfigFunc         func(name string) bool
@@ -39,6 +41,14 @@ func (mock *ProvisioningServiceMock) ProvisionNotifications() error {
 	return nil
 }
 
+func (mock *ProvisioningServiceMock) ProvisionAlertRules() error {
+	mock.Calls.ProvisionAlertRules = append(mock.Calls.ProvisionAlertRules, nil)
+	if mock.ProvisionAlertRulesFunc != nil {
+		return mock.ProvisionAlertRulesFunc()
+	}
+	return nil
+}
+
 func (mock *ProvisioningServiceMock) ProvisionDashboards() error {
 	mock.Calls.ProvisionDashboards = append(mock.Calls.ProvisionDashboards, nil)
 	if mock.ProvisionDashboardsFunc != nil {
@@ -0,0 +1,22 @@
+package querycache
+
+import (
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/registry"
+	"github.com/grafana/grafana/pkg/tsdb"
+)
+
+// QueryCacheService makes the remote cache the storage of the
+// query results of data sources with query caching enabled.
+type QueryCacheService struct {
+	RemoteCache *remotecache.RemoteCache `inject:""`
+}
+
+func init() {
+	registry.RegisterService(&QueryCacheService{})
+}
+
+func (s *QueryCacheService) Init() error {
+	tsdb.SetQueryCache(s.RemoteCache)
+	return nil
+}
@@ -68,6 +68,14 @@ func deleteAlertByIdInternal(alertId int64, reason string, sess *DBSession) erro
 		return err
 	}
 
+	if _, err := sess.Exec("DELETE FROM alert_instance WHERE alert_id = ?", alertId); err != nil {
+		return err
+	}
+
+	if _, err := sess.Exec("DELETE FROM alert_state_history WHERE alert_id = ?", alertId); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -87,7 +95,7 @@ func HandleAlertsQuery(query *models.GetAlertsQuery) error {
 		dashboard.uid as dashboard_uid,
 		dashboard.slug as dashboard_slug
 		FROM alert
-		INNER JOIN dashboard on dashboard.id = alert.dashboard_id `)
+		LEFT JOIN dashboard on dashboard.id = alert.dashboard_id `)
 
 	builder.Write(`WHERE alert.org_id = ?`, query.OrgId)
 
@@ -125,7 +133,10 @@ func HandleAlertsQuery(query *models.GetAlertsQuery) error {
 	}
 
 	if query.User.OrgRole != models.ROLE_ADMIN {
-		builder.writeDashboardPermissionFilter(query.User, models.PERMISSION_VIEW)
+		// standalone alert rules have no dashboard and are visible to the whole org
+		builder.Write(` AND (alert.dashboard_id = 0 OR `)
+		builder.writeDashboardPermissionCondition(query.User, models.PERMISSION_VIEW)
+		builder.Write(`)`)
 	}
 
 	builder.Write(" ORDER BY name ASC")
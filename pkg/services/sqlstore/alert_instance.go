@@ -0,0 +1,108 @@
+package sqlstore
+
+import (
+	"strings"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+func init() {
+	bus.AddHandler("sql", SetAlertInstanceState)
+	bus.AddHandler("sql", GetAlertInstances)
+	bus.AddHandler("sql", DeleteAlertInstance)
+}
+
+func SetAlertInstanceState(cmd *models.SetAlertInstanceStateCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		labelsHash := models.GetAlertInstanceLabelsHash(cmd.Metric, cmd.Labels)
+
+		instance := &models.AlertInstance{}
+		has, err := sess.Where("alert_id = ? AND labels_hash = ?", cmd.AlertId, labelsHash).Get(instance)
+		if err != nil {
+			return err
+		}
+
+		if !has {
+			if cmd.StateChanges != 0 {
+				return models.ErrAlertInstanceStateVersionConflict
+			}
+
+			instance = &models.AlertInstance{
+				OrgId:        cmd.OrgId,
+				AlertId:      cmd.AlertId,
+				LabelsHash:   labelsHash,
+				Metric:       cmd.Metric,
+				Labels:       cmd.Labels,
+				State:        cmd.State,
+				EvalData:     cmd.EvalData,
+				NewStateDate: timeNow(),
+				StateChanges: 1,
+				Created:      timeNow(),
+				Updated:      timeNow(),
+			}
+
+			if _, err := sess.Insert(instance); err != nil {
+				return err
+			}
+
+			cmd.Result = instance
+			return nil
+		}
+
+		if instance.StateChanges != cmd.StateChanges {
+			return models.ErrAlertInstanceStateVersionConflict
+		}
+
+		if instance.State == cmd.State {
+			return models.ErrRequiresNewState
+		}
+
+		instance.State = cmd.State
+		instance.EvalData = cmd.EvalData
+		instance.NewStateDate = timeNow()
+		instance.Updated = timeNow()
+		instance.StateChanges++
+
+		affected, err := sess.Where("id = ? AND state_changes = ?", instance.Id, cmd.StateChanges).
+			Cols("state", "eval_data", "new_state_date", "updated", "state_changes").
+			Update(instance)
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return models.ErrAlertInstanceStateVersionConflict
+		}
+
+		cmd.Result = instance
+		return nil
+	})
+}
+
+func GetAlertInstances(query *models.GetAlertInstancesQuery) error {
+	sess := x.Where("org_id = ? AND alert_id = ?", query.OrgId, query.AlertId)
+
+	if len(query.State) > 0 && query.State[0] != "all" {
+		params := make([]interface{}, 0, len(query.State))
+		for _, v := range query.State {
+			params = append(params, v)
+		}
+		sess.And("state IN (?"+strings.Repeat(",?", len(query.State)-1)+")", params...)
+	}
+
+	instances := make([]*models.AlertInstance, 0)
+	if err := sess.Asc("metric").Find(&instances); err != nil {
+		return err
+	}
+
+	query.Result = instances
+	return nil
+}
+
+func DeleteAlertInstance(cmd *models.DeleteAlertInstanceCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		_, err := sess.Exec("DELETE FROM alert_instance WHERE org_id = ? AND alert_id = ? AND labels_hash = ?", cmd.OrgId, cmd.AlertId, cmd.LabelsHash)
+		return err
+	})
+}
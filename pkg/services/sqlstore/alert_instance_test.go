@@ -0,0 +1,73 @@
+package sqlstore
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertInstanceDataAccess(t *testing.T) {
+	Convey("Testing alert instance data access", t, func() {
+		InitTestDB(t)
+
+		labels := map[string]string{"host": "a"}
+		cmd := &models.SetAlertInstanceStateCommand{
+			OrgId:   1,
+			AlertId: 1,
+			Metric:  "cpu",
+			Labels:  labels,
+			State:   models.AlertStateAlerting,
+		}
+
+		err := SetAlertInstanceState(cmd)
+		So(err, ShouldBeNil)
+		So(cmd.Result.StateChanges, ShouldEqual, 1)
+
+		Convey("Can get alert instances", func() {
+			query := &models.GetAlertInstancesQuery{OrgId: 1, AlertId: 1}
+			err := GetAlertInstances(query)
+			So(err, ShouldBeNil)
+			So(query.Result, ShouldHaveLength, 1)
+			So(query.Result[0].Labels, ShouldResemble, labels)
+			So(query.Result[0].State, ShouldEqual, models.AlertStateAlerting)
+
+			Convey("Can filter by state", func() {
+				query := &models.GetAlertInstancesQuery{OrgId: 1, AlertId: 1, State: []string{"ok"}}
+				err := GetAlertInstances(query)
+				So(err, ShouldBeNil)
+				So(query.Result, ShouldHaveLength, 0)
+			})
+		})
+
+		Convey("Can change state with the current version", func() {
+			cmd := &models.SetAlertInstanceStateCommand{OrgId: 1, AlertId: 1, Metric: "cpu", Labels: labels, State: models.AlertStateOK, StateChanges: 1}
+			err := SetAlertInstanceState(cmd)
+			So(err, ShouldBeNil)
+			So(cmd.Result.StateChanges, ShouldEqual, 2)
+			So(cmd.Result.State, ShouldEqual, models.AlertStateOK)
+		})
+
+		Convey("Cannot change state with an outdated version", func() {
+			cmd := &models.SetAlertInstanceStateCommand{OrgId: 1, AlertId: 1, Metric: "cpu", Labels: labels, State: models.AlertStateOK, StateChanges: 0}
+			err := SetAlertInstanceState(cmd)
+			So(err, ShouldEqual, models.ErrAlertInstanceStateVersionConflict)
+		})
+
+		Convey("Cannot set the same state twice", func() {
+			cmd := &models.SetAlertInstanceStateCommand{OrgId: 1, AlertId: 1, Metric: "cpu", Labels: labels, State: models.AlertStateAlerting, StateChanges: 1}
+			err := SetAlertInstanceState(cmd)
+			So(err, ShouldEqual, models.ErrRequiresNewState)
+		})
+
+		Convey("Can delete alert instance", func() {
+			err := DeleteAlertInstance(&models.DeleteAlertInstanceCommand{OrgId: 1, AlertId: 1, LabelsHash: cmd.Result.LabelsHash})
+			So(err, ShouldBeNil)
+
+			query := &models.GetAlertInstancesQuery{OrgId: 1, AlertId: 1}
+			err = GetAlertInstances(query)
+			So(err, ShouldBeNil)
+			So(query.Result, ShouldHaveLength, 0)
+		})
+	})
+}
@@ -0,0 +1,207 @@
+package sqlstore
+
+import (
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func init() {
+	bus.AddHandler("sql", StoreAlertRule)
+	bus.AddHandler("sql", DeleteAlertRule)
+	bus.AddHandler("sql", GetAlertRuleByUid)
+	bus.AddHandler("sql", GetAlertRules)
+}
+
+func StoreAlertRule(cmd *models.StoreAlertRuleCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		if cmd.Alert.Name == "" {
+			return models.ErrAlertRuleMissingName
+		}
+
+		rule := &models.AlertRule{}
+		exists := false
+		if cmd.Uid != "" {
+			var err error
+			if exists, err = sess.Where("org_id = ? AND uid = ?", cmd.OrgId, cmd.Uid).Get(rule); err != nil {
+				return err
+			}
+		}
+
+		if exists && rule.Provisioned && !cmd.Overwrite {
+			return models.ErrAlertRuleProvisioned
+		}
+
+		sameName := &models.AlertRule{}
+		has, err := sess.Where("org_id = ? AND name = ?", cmd.OrgId, cmd.Alert.Name).Get(sameName)
+		if err != nil {
+			return err
+		}
+
+		if has && (!exists || sameName.Id != rule.Id) {
+			return models.ErrAlertRuleWithSameNameExists
+		}
+
+		existingAlerts := make([]*models.Alert, 0)
+		if exists {
+			existing := &models.Alert{}
+			has, err := sess.ID(rule.AlertId).Get(existing)
+			if err != nil {
+				return err
+			}
+
+			if has {
+				existingAlerts = append(existingAlerts, existing)
+			}
+		} else {
+			if cmd.Uid == "" {
+				if cmd.Uid, err = generateNewAlertRuleUid(sess, cmd.OrgId); err != nil {
+					return err
+				}
+			}
+
+			rule = &models.AlertRule{
+				OrgId:     cmd.OrgId,
+				Uid:       cmd.Uid,
+				Created:   timeNow(),
+				CreatedBy: cmd.UserId,
+			}
+		}
+
+		// standalone rules are evaluated as alerts without dashboard and panel
+		alert := cmd.Alert
+		alert.OrgId = cmd.OrgId
+		alert.DashboardId = 0
+		alert.PanelId = 0
+
+		saveAlerts := &models.SaveAlertsCommand{OrgId: cmd.OrgId, UserId: cmd.UserId, Alerts: []*models.Alert{alert}}
+		if err := updateAlerts(existingAlerts, saveAlerts, sess); err != nil {
+			return err
+		}
+
+		rule.AlertId = alert.Id
+		rule.Name = alert.Name
+		rule.Settings = cmd.Settings
+		rule.Provisioned = cmd.Provisioned
+		rule.Version++
+		rule.Updated = timeNow()
+		rule.UpdatedBy = cmd.UserId
+
+		if exists {
+			if _, err := sess.ID(rule.Id).AllCols().Update(rule); err != nil {
+				return err
+			}
+		} else {
+			if _, err := sess.Insert(rule); err != nil {
+				return err
+			}
+		}
+
+		cmd.Result = rule
+		return nil
+	})
+}
+
+func generateNewAlertRuleUid(sess *DBSession, orgId int64) (string, error) {
+	for i := 0; i < 3; i++ {
+		uid := util.GenerateShortUID()
+		exists, err := sess.Where("org_id = ? AND uid = ?", orgId, uid).Get(&models.AlertRule{})
+		if err != nil {
+			return "", err
+		}
+
+		if !exists {
+			return uid, nil
+		}
+	}
+
+	return "", models.ErrAlertRuleFailedGenerateUniqueUid
+}
+
+func DeleteAlertRule(cmd *models.DeleteAlertRuleCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		rule := &models.AlertRule{}
+		has, err := sess.Where("org_id = ? AND uid = ?", cmd.OrgId, cmd.Uid).Get(rule)
+		if err != nil {
+			return err
+		}
+
+		if !has {
+			return models.ErrAlertRuleNotFound
+		}
+
+		if rule.Provisioned && !cmd.Overwrite {
+			return models.ErrAlertRuleProvisioned
+		}
+
+		if err := deleteAlertByIdInternal(rule.AlertId, "Alert rule deleted", sess); err != nil {
+			return err
+		}
+
+		_, err = sess.Exec("DELETE FROM alert_rule WHERE id = ?", rule.Id)
+		return err
+	})
+}
+
+func GetAlertRuleByUid(query *models.GetAlertRuleByUidQuery) error {
+	rule := &models.AlertRule{}
+	has, err := x.Where("org_id = ? AND uid = ?", query.OrgId, query.Uid).Get(rule)
+	if err != nil {
+		return err
+	}
+
+	if !has {
+		return models.ErrAlertRuleNotFound
+	}
+
+	query.Result = rule
+	return nil
+}
+
+func GetAlertRules(query *models.GetAlertRulesQuery) error {
+	rules := make([]*models.AlertRule, 0)
+	if err := x.Where("org_id = ?", query.OrgId).Asc("name").Find(&rules); err != nil {
+		return err
+	}
+
+	alertIds := make([]int64, 0, len(rules))
+	for _, rule := range rules {
+		alertIds = append(alertIds, rule.AlertId)
+	}
+
+	alerts := make(map[int64]*models.Alert)
+	if len(alertIds) > 0 {
+		var result []*models.Alert
+		if err := x.In("id", alertIds).Find(&result); err != nil {
+			return err
+		}
+
+		for _, alert := range result {
+			alerts[alert.Id] = alert
+		}
+	}
+
+	query.Result = make([]*models.AlertRuleDTO, 0, len(rules))
+	for _, rule := range rules {
+		dto := &models.AlertRuleDTO{
+			Id:          rule.Id,
+			Uid:         rule.Uid,
+			AlertId:     rule.AlertId,
+			Name:        rule.Name,
+			Settings:    rule.Settings,
+			Provisioned: rule.Provisioned,
+			Version:     rule.Version,
+			Created:     rule.Created,
+			Updated:     rule.Updated,
+		}
+
+		if alert, ok := alerts[rule.AlertId]; ok {
+			dto.State = alert.State
+			dto.NewStateDate = alert.NewStateDate
+		}
+
+		query.Result = append(query.Result, dto)
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package sqlstore
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func newTestStoreAlertRuleCommand(uid string, name string) *models.StoreAlertRuleCommand {
+	settings := simplejson.NewFromAny(map[string]interface{}{"name": name})
+
+	return &models.StoreAlertRuleCommand{
+		Uid:      uid,
+		OrgId:    1,
+		UserId:   2,
+		Settings: settings,
+		Alert: &models.Alert{
+			Name:      name,
+			Message:   "message",
+			Frequency: 60,
+			Settings:  simplejson.NewFromAny(map[string]interface{}{"alertRuleTags": map[string]interface{}{"team": "ops"}}),
+		},
+	}
+}
+
+func TestAlertRuleDataAccess(t *testing.T) {
+	Convey("Testing alert rule data access", t, func() {
+		InitTestDB(t)
+
+		cmd := newTestStoreAlertRuleCommand("", "High CPU")
+		err := StoreAlertRule(cmd)
+		So(err, ShouldBeNil)
+		So(cmd.Result.Uid, ShouldNotBeEmpty)
+		So(cmd.Result.AlertId, ShouldNotEqual, 0)
+		So(cmd.Result.Version, ShouldEqual, 1)
+
+		Convey("Creates the alert evaluated by the scheduler", func() {
+			query := &models.GetAlertByIdQuery{Id: cmd.Result.AlertId}
+			err := GetAlertById(query)
+			So(err, ShouldBeNil)
+			So(query.Result.Name, ShouldEqual, "High CPU")
+			So(query.Result.DashboardId, ShouldEqual, 0)
+			So(query.Result.State, ShouldEqual, models.AlertStateUnknown)
+		})
+
+		Convey("Can get rule by uid", func() {
+			query := &models.GetAlertRuleByUidQuery{OrgId: 1, Uid: cmd.Result.Uid}
+			err := GetAlertRuleByUid(query)
+			So(err, ShouldBeNil)
+			So(query.Result.Name, ShouldEqual, "High CPU")
+			So(query.Result.Settings.Get("name").MustString(), ShouldEqual, "High CPU")
+
+			query = &models.GetAlertRuleByUidQuery{OrgId: 2, Uid: cmd.Result.Uid}
+			err = GetAlertRuleByUid(query)
+			So(err, ShouldEqual, models.ErrAlertRuleNotFound)
+		})
+
+		Convey("Can list rules with state", func() {
+			err := SetAlertState(&models.SetAlertStateCommand{AlertId: cmd.Result.AlertId, OrgId: 1, State: models.AlertStateAlerting})
+			So(err, ShouldBeNil)
+
+			query := &models.GetAlertRulesQuery{OrgId: 1}
+			err = GetAlertRules(query)
+			So(err, ShouldBeNil)
+			So(query.Result, ShouldHaveLength, 1)
+			So(query.Result[0].State, ShouldEqual, models.AlertStateAlerting)
+		})
+
+		Convey("Rules are listed with the dashboard alerts", func() {
+			adminQuery := &models.GetAlertsQuery{OrgId: 1, User: &models.SignedInUser{OrgRole: models.ROLE_ADMIN}}
+			err := HandleAlertsQuery(adminQuery)
+			So(err, ShouldBeNil)
+			So(adminQuery.Result, ShouldHaveLength, 1)
+			So(adminQuery.Result[0].Name, ShouldEqual, "High CPU")
+			So(adminQuery.Result[0].DashboardId, ShouldEqual, 0)
+			So(adminQuery.Result[0].DashboardUid, ShouldBeEmpty)
+
+			viewerQuery := &models.GetAlertsQuery{OrgId: 1, User: &models.SignedInUser{OrgRole: models.ROLE_VIEWER, OrgId: 1}}
+			err = HandleAlertsQuery(viewerQuery)
+			So(err, ShouldBeNil)
+			So(viewerQuery.Result, ShouldHaveLength, 1)
+		})
+
+		Convey("Updating keeps the alert and its state", func() {
+			err := SetAlertState(&models.SetAlertStateCommand{AlertId: cmd.Result.AlertId, OrgId: 1, State: models.AlertStateAlerting})
+			So(err, ShouldBeNil)
+
+			update := newTestStoreAlertRuleCommand(cmd.Result.Uid, "High CPU usage")
+			err = StoreAlertRule(update)
+			So(err, ShouldBeNil)
+			So(update.Result.Id, ShouldEqual, cmd.Result.Id)
+			So(update.Result.AlertId, ShouldEqual, cmd.Result.AlertId)
+			So(update.Result.Version, ShouldEqual, 2)
+
+			query := &models.GetAlertByIdQuery{Id: cmd.Result.AlertId}
+			err = GetAlertById(query)
+			So(err, ShouldBeNil)
+			So(query.Result.Name, ShouldEqual, "High CPU usage")
+			So(query.Result.State, ShouldEqual, models.AlertStateAlerting)
+		})
+
+		Convey("Cannot create a rule with the same name", func() {
+			err := StoreAlertRule(newTestStoreAlertRuleCommand("", "High CPU"))
+			So(err, ShouldEqual, models.ErrAlertRuleWithSameNameExists)
+		})
+
+		Convey("Provisioned rules can only be changed with overwrite", func() {
+			provisioned := newTestStoreAlertRuleCommand("provisioned", "Provisioned")
+			provisioned.Provisioned = true
+			provisioned.Overwrite = true
+			So(StoreAlertRule(provisioned), ShouldBeNil)
+
+			err := StoreAlertRule(newTestStoreAlertRuleCommand("provisioned", "Provisioned"))
+			So(err, ShouldEqual, models.ErrAlertRuleProvisioned)
+
+			err = DeleteAlertRule(&models.DeleteAlertRuleCommand{OrgId: 1, Uid: "provisioned"})
+			So(err, ShouldEqual, models.ErrAlertRuleProvisioned)
+
+			err = DeleteAlertRule(&models.DeleteAlertRuleCommand{OrgId: 1, Uid: "provisioned", Overwrite: true})
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Deleting removes the alert", func() {
+			err := DeleteAlertRule(&models.DeleteAlertRuleCommand{OrgId: 1, Uid: cmd.Result.Uid})
+			So(err, ShouldBeNil)
+
+			err = GetAlertById(&models.GetAlertByIdQuery{Id: cmd.Result.AlertId})
+			So(err, ShouldNotBeNil)
+
+			err = DeleteAlertRule(&models.DeleteAlertRuleCommand{OrgId: 1, Uid: cmd.Result.Uid})
+			So(err, ShouldEqual, models.ErrAlertRuleNotFound)
+		})
+	})
+}
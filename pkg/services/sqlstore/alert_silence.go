@@ -0,0 +1,155 @@
+package sqlstore
+
+import (
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+func init() {
+	bus.AddHandler("sql", CreateAlertSilence)
+	bus.AddHandler("sql", UpdateAlertSilence)
+	bus.AddHandler("sql", DeleteAlertSilence)
+	bus.AddHandler("sql", GetAlertSilenceById)
+	bus.AddHandler("sql", GetAlertSilences)
+	bus.AddHandler("sql", DeleteExpiredAlertSilences)
+}
+
+func validateAlertSilence(silence *models.AlertSilence) error {
+	if silence.AlertName == "" && silence.DashboardUid == "" && silence.FolderUid == "" && len(silence.Tags) == 0 {
+		return models.ErrAlertSilenceMissingMatcher
+	}
+
+	if !silence.EndsAt.After(silence.StartsAt) {
+		return models.ErrAlertSilenceInvalidDuration
+	}
+
+	return nil
+}
+
+func CreateAlertSilence(cmd *models.CreateAlertSilenceCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		silence := &models.AlertSilence{
+			OrgId:        cmd.OrgId,
+			Comment:      cmd.Comment,
+			AlertName:    cmd.AlertName,
+			DashboardUid: cmd.DashboardUid,
+			FolderUid:    cmd.FolderUid,
+			Tags:         cmd.Tags,
+			StartsAt:     cmd.StartsAt,
+			EndsAt:       cmd.EndsAt,
+			CreatedBy:    cmd.CreatedBy,
+			Created:      timeNow(),
+			Updated:      timeNow(),
+		}
+
+		if silence.StartsAt.IsZero() {
+			silence.StartsAt = silence.Created
+		}
+
+		if err := validateAlertSilence(silence); err != nil {
+			return err
+		}
+
+		if _, err := sess.Insert(silence); err != nil {
+			return err
+		}
+
+		cmd.Result = silence
+		return nil
+	})
+}
+
+func UpdateAlertSilence(cmd *models.UpdateAlertSilenceCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		silence := &models.AlertSilence{}
+		has, err := sess.Where("id = ? AND org_id = ?", cmd.Id, cmd.OrgId).Get(silence)
+		if err != nil {
+			return err
+		}
+
+		if !has {
+			return models.ErrAlertSilenceNotFound
+		}
+
+		silence.Comment = cmd.Comment
+		silence.AlertName = cmd.AlertName
+		silence.DashboardUid = cmd.DashboardUid
+		silence.FolderUid = cmd.FolderUid
+		silence.Tags = cmd.Tags
+		silence.EndsAt = cmd.EndsAt
+		// updates without start keep the start of the silence
+		if !cmd.StartsAt.IsZero() {
+			silence.StartsAt = cmd.StartsAt
+		}
+		silence.Updated = timeNow()
+
+		if err := validateAlertSilence(silence); err != nil {
+			return err
+		}
+
+		if _, err := sess.ID(silence.Id).AllCols().Update(silence); err != nil {
+			return err
+		}
+
+		cmd.Result = silence
+		return nil
+	})
+}
+
+func DeleteAlertSilence(cmd *models.DeleteAlertSilenceCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		res, err := sess.Exec("DELETE FROM alert_silence WHERE id = ? AND org_id = ?", cmd.Id, cmd.OrgId)
+		if err != nil {
+			return err
+		}
+
+		if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+			return models.ErrAlertSilenceNotFound
+		}
+
+		return nil
+	})
+}
+
+func GetAlertSilenceById(query *models.GetAlertSilenceByIdQuery) error {
+	silence := &models.AlertSilence{}
+	has, err := x.Where("id = ? AND org_id = ?", query.Id, query.OrgId).Get(silence)
+	if err != nil {
+		return err
+	}
+
+	if !has {
+		return models.ErrAlertSilenceNotFound
+	}
+
+	query.Result = silence
+	return nil
+}
+
+func GetAlertSilences(query *models.GetAlertSilencesQuery) error {
+	sess := x.Where("org_id = ?", query.OrgId)
+
+	if !query.ActiveAt.IsZero() {
+		sess.And("starts_at <= ? AND ends_at > ?", query.ActiveAt, query.ActiveAt)
+	}
+
+	silences := make([]*models.AlertSilence, 0)
+	if err := sess.Desc("ends_at").Find(&silences); err != nil {
+		return err
+	}
+
+	query.Result = silences
+	return nil
+}
+
+func DeleteExpiredAlertSilences(cmd *models.DeleteExpiredAlertSilencesCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		res, err := sess.Exec("DELETE FROM alert_silence WHERE ends_at < ?", cmd.OlderThan)
+		if err != nil {
+			return err
+		}
+
+		cmd.DeletedRows, _ = res.RowsAffected()
+		return nil
+	})
+}
@@ -0,0 +1,110 @@
+package sqlstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertSilenceDataAccess(t *testing.T) {
+	Convey("Testing alert silence data access", t, func() {
+		InitTestDB(t)
+
+		now := time.Now()
+		cmd := &models.CreateAlertSilenceCommand{
+			OrgId:     1,
+			Comment:   "maintenance",
+			AlertName: "High CPU",
+			Tags:      map[string]string{"team": "ops"},
+			StartsAt:  now.Add(-time.Hour),
+			EndsAt:    now.Add(time.Hour),
+		}
+
+		err := CreateAlertSilence(cmd)
+		So(err, ShouldBeNil)
+		So(cmd.Result.Id, ShouldNotEqual, 0)
+
+		Convey("Can get silence by id", func() {
+			query := &models.GetAlertSilenceByIdQuery{OrgId: 1, Id: cmd.Result.Id}
+			err := GetAlertSilenceById(query)
+			So(err, ShouldBeNil)
+			So(query.Result.AlertName, ShouldEqual, "High CPU")
+			So(query.Result.Tags, ShouldResemble, map[string]string{"team": "ops"})
+		})
+
+		Convey("Cannot get silence of another org", func() {
+			query := &models.GetAlertSilenceByIdQuery{OrgId: 2, Id: cmd.Result.Id}
+			err := GetAlertSilenceById(query)
+			So(err, ShouldEqual, models.ErrAlertSilenceNotFound)
+		})
+
+		Convey("Can get active silences", func() {
+			query := &models.GetAlertSilencesQuery{OrgId: 1, ActiveAt: now}
+			err := GetAlertSilences(query)
+			So(err, ShouldBeNil)
+			So(query.Result, ShouldHaveLength, 1)
+
+			query = &models.GetAlertSilencesQuery{OrgId: 1, ActiveAt: now.Add(2 * time.Hour)}
+			err = GetAlertSilences(query)
+			So(err, ShouldBeNil)
+			So(query.Result, ShouldHaveLength, 0)
+		})
+
+		Convey("Can update silence", func() {
+			update := &models.UpdateAlertSilenceCommand{
+				OrgId:        1,
+				Id:           cmd.Result.Id,
+				DashboardUid: "abc",
+				StartsAt:     now,
+				EndsAt:       now.Add(time.Minute),
+			}
+			err := UpdateAlertSilence(update)
+			So(err, ShouldBeNil)
+			So(update.Result.AlertName, ShouldEqual, "")
+			So(update.Result.DashboardUid, ShouldEqual, "abc")
+		})
+
+		Convey("Updating silence without start keeps its start", func() {
+			update := &models.UpdateAlertSilenceCommand{
+				OrgId:     1,
+				Id:        cmd.Result.Id,
+				AlertName: "High CPU",
+				EndsAt:    now.Add(2 * time.Hour),
+			}
+			err := UpdateAlertSilence(update)
+			So(err, ShouldBeNil)
+
+			query := &models.GetAlertSilenceByIdQuery{OrgId: 1, Id: cmd.Result.Id}
+			err = GetAlertSilenceById(query)
+			So(err, ShouldBeNil)
+			So(query.Result.StartsAt.Unix(), ShouldEqual, cmd.Result.StartsAt.Unix())
+		})
+
+		Convey("Cannot create silence without matchers", func() {
+			err := CreateAlertSilence(&models.CreateAlertSilenceCommand{OrgId: 1, EndsAt: now.Add(time.Hour)})
+			So(err, ShouldEqual, models.ErrAlertSilenceMissingMatcher)
+		})
+
+		Convey("Cannot create silence ending before it starts", func() {
+			err := CreateAlertSilence(&models.CreateAlertSilenceCommand{OrgId: 1, AlertName: "a", StartsAt: now, EndsAt: now.Add(-time.Hour)})
+			So(err, ShouldEqual, models.ErrAlertSilenceInvalidDuration)
+		})
+
+		Convey("Can delete silence", func() {
+			err := DeleteAlertSilence(&models.DeleteAlertSilenceCommand{OrgId: 1, Id: cmd.Result.Id})
+			So(err, ShouldBeNil)
+
+			err = DeleteAlertSilence(&models.DeleteAlertSilenceCommand{OrgId: 1, Id: cmd.Result.Id})
+			So(err, ShouldEqual, models.ErrAlertSilenceNotFound)
+		})
+
+		Convey("Can delete expired silences", func() {
+			expired := &models.DeleteExpiredAlertSilencesCommand{OlderThan: now.Add(2 * time.Hour)}
+			err := DeleteExpiredAlertSilences(expired)
+			So(err, ShouldBeNil)
+			So(expired.DeletedRows, ShouldEqual, 1)
+		})
+	})
+}
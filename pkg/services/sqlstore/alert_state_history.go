@@ -0,0 +1,109 @@
+package sqlstore
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	"xorm.io/xorm"
+)
+
+func init() {
+	bus.AddHandler("sql", AddAlertStateHistory)
+	bus.AddHandler("sql", GetAlertStateHistory)
+	bus.AddHandler("sql", DeleteOldAlertStateHistory)
+}
+
+func AddAlertStateHistory(cmd *models.AddAlertStateHistoryCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		item := &models.AlertStateHistory{
+			OrgId:          cmd.OrgId,
+			AlertId:        cmd.AlertId,
+			Metric:         cmd.Metric,
+			Labels:         cmd.Labels,
+			PrevState:      cmd.PrevState,
+			NewState:       cmd.NewState,
+			EvalMatches:    cmd.EvalMatches,
+			ConditionEvals: cmd.ConditionEvals,
+			Error:          cmd.Error,
+			Notifications:  cmd.Notifications,
+			Created:        timeNow(),
+		}
+
+		if _, err := sess.Insert(item); err != nil {
+			return err
+		}
+
+		cmd.Result = item
+		return nil
+	})
+}
+
+func GetAlertStateHistory(query *models.GetAlertStateHistoryQuery) error {
+	if query.PerPage <= 0 {
+		query.PerPage = 100
+	}
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+
+	filter := func() *xorm.Session {
+		sess := x.Where("org_id = ? AND alert_id = ?", query.OrgId, query.AlertId)
+		if query.From > 0 {
+			sess.And("created >= ?", time.Unix(0, query.From*int64(time.Millisecond)))
+		}
+		if query.To > 0 {
+			sess.And("created <= ?", time.Unix(0, query.To*int64(time.Millisecond)))
+		}
+		return sess
+	}
+
+	total, err := filter().Count(&models.AlertStateHistory{})
+	if err != nil {
+		return err
+	}
+
+	items := make([]*models.AlertStateHistory, 0)
+	offset := query.PerPage * (query.Page - 1)
+	if err := filter().Desc("created").Desc("id").Limit(query.PerPage, offset).Find(&items); err != nil {
+		return err
+	}
+
+	result := &models.AlertStateHistorySearchResult{
+		TotalCount: total,
+		History:    make([]*models.AlertStateHistoryDTO, 0, len(items)),
+		Page:       query.Page,
+		PerPage:    query.PerPage,
+	}
+
+	for _, item := range items {
+		result.History = append(result.History, &models.AlertStateHistoryDTO{
+			Id:             item.Id,
+			AlertId:        item.AlertId,
+			Metric:         item.Metric,
+			Labels:         item.Labels,
+			PrevState:      item.PrevState,
+			NewState:       item.NewState,
+			EvalMatches:    item.EvalMatches,
+			ConditionEvals: item.ConditionEvals,
+			Error:          item.Error,
+			Notifications:  item.Notifications,
+			Time:           item.Created.UnixNano() / int64(time.Millisecond),
+		})
+	}
+
+	query.Result = result
+	return nil
+}
+
+func DeleteOldAlertStateHistory(cmd *models.DeleteOldAlertStateHistoryCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		res, err := sess.Exec("DELETE FROM alert_state_history WHERE created < ?", cmd.OlderThan)
+		if err != nil {
+			return err
+		}
+
+		cmd.DeletedRows, _ = res.RowsAffected()
+		return nil
+	})
+}
@@ -0,0 +1,94 @@
+package sqlstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertStateHistoryDataAccess(t *testing.T) {
+	Convey("Testing alert state history data access", t, func() {
+		InitTestDB(t)
+
+		start := time.Date(2020, 5, 14, 10, 0, 0, 0, time.UTC)
+		oldTimeNow := timeNow
+		Reset(func() { timeNow = oldTimeNow })
+
+		states := []models.AlertStateType{models.AlertStatePending, models.AlertStateAlerting, models.AlertStateOK}
+		prevState := models.AlertStateOK
+		for i, state := range states {
+			created := start.Add(time.Duration(i) * time.Hour)
+			timeNow = func() time.Time { return created }
+
+			cmd := &models.AddAlertStateHistoryCommand{
+				OrgId:          1,
+				AlertId:        1,
+				PrevState:      prevState,
+				NewState:       state,
+				EvalMatches:    simplejson.NewFromAny([]interface{}{map[string]interface{}{"metric": "cpu", "value": 95}}),
+				ConditionEvals: "true = true",
+				Notifications:  simplejson.NewFromAny([]interface{}{map[string]interface{}{"uid": "notifier1", "status": "sent"}}),
+			}
+			So(AddAlertStateHistory(cmd), ShouldBeNil)
+			prevState = state
+		}
+
+		err := AddAlertStateHistory(&models.AddAlertStateHistoryCommand{OrgId: 1, AlertId: 2, NewState: models.AlertStateAlerting})
+		So(err, ShouldBeNil)
+
+		Convey("Can get history of an alert newest first", func() {
+			query := &models.GetAlertStateHistoryQuery{OrgId: 1, AlertId: 1}
+			So(GetAlertStateHistory(query), ShouldBeNil)
+
+			So(query.Result.TotalCount, ShouldEqual, 3)
+			So(query.Result.History, ShouldHaveLength, 3)
+			So(query.Result.History[0].NewState, ShouldEqual, models.AlertStateOK)
+			So(query.Result.History[0].PrevState, ShouldEqual, models.AlertStateAlerting)
+			So(query.Result.History[0].Time, ShouldEqual, start.Add(2*time.Hour).UnixNano()/int64(time.Millisecond))
+			So(query.Result.History[0].ConditionEvals, ShouldEqual, "true = true")
+			So(query.Result.History[0].Notifications.GetIndex(0).Get("status").MustString(), ShouldEqual, "sent")
+			So(query.Result.History[0].EvalMatches.GetIndex(0).Get("metric").MustString(), ShouldEqual, "cpu")
+		})
+
+		Convey("Can page history", func() {
+			query := &models.GetAlertStateHistoryQuery{OrgId: 1, AlertId: 1, Page: 2, PerPage: 2}
+			So(GetAlertStateHistory(query), ShouldBeNil)
+
+			So(query.Result.TotalCount, ShouldEqual, 3)
+			So(query.Result.History, ShouldHaveLength, 1)
+			So(query.Result.History[0].NewState, ShouldEqual, models.AlertStatePending)
+		})
+
+		Convey("Can filter history by time", func() {
+			query := &models.GetAlertStateHistoryQuery{
+				OrgId:   1,
+				AlertId: 1,
+				From:    start.Add(30*time.Minute).UnixNano() / int64(time.Millisecond),
+				To:      start.Add(90*time.Minute).UnixNano() / int64(time.Millisecond),
+			}
+			So(GetAlertStateHistory(query), ShouldBeNil)
+
+			So(query.Result.TotalCount, ShouldEqual, 1)
+			So(query.Result.History[0].NewState, ShouldEqual, models.AlertStateAlerting)
+		})
+
+		Convey("Cannot get history of another org", func() {
+			query := &models.GetAlertStateHistoryQuery{OrgId: 2, AlertId: 1}
+			So(GetAlertStateHistory(query), ShouldBeNil)
+			So(query.Result.TotalCount, ShouldEqual, 0)
+		})
+
+		Convey("Can delete old history", func() {
+			cmd := &models.DeleteOldAlertStateHistoryCommand{OlderThan: start.Add(90 * time.Minute)}
+			So(DeleteOldAlertStateHistory(cmd), ShouldBeNil)
+			So(cmd.DeletedRows, ShouldEqual, 2)
+
+			query := &models.GetAlertStateHistoryQuery{OrgId: 1, AlertId: 1}
+			So(GetAlertStateHistory(query), ShouldBeNil)
+			So(query.Result.TotalCount, ShouldEqual, 1)
+		})
+	})
+}
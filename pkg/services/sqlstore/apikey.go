@@ -14,13 +14,16 @@ func init() {
 	bus.AddHandler("sql", GetApiKeyByName)
 	bus.AddHandlerCtx("sql", DeleteApiKeyCtx)
 	bus.AddHandler("sql", AddApiKey)
+	bus.AddHandler("sql", UpdateApiKeysLastUsed)
+	bus.AddHandler("sql", DeleteUnusedApiKeys)
 }
 
 func GetApiKeys(query *models.GetApiKeysQuery) error {
-	sess := x.Limit(100, 0).Where("org_id=? and ( expires IS NULL or expires >= ?)",
+	// service account tokens are listed by the service account api
+	sess := x.Limit(100, 0).Where("org_id=? and service_account_id IS NULL and ( expires IS NULL or expires >= ?)",
 		query.OrgId, timeNow().Unix()).Asc("name")
 	if query.IncludeExpired {
-		sess = x.Limit(100, 0).Where("org_id=?", query.OrgId).Asc("name")
+		sess = x.Limit(100, 0).Where("org_id=? and service_account_id IS NULL", query.OrgId).Asc("name")
 	}
 
 	query.Result = make([]*models.ApiKey, 0)
@@ -29,7 +32,7 @@ func GetApiKeys(query *models.GetApiKeysQuery) error {
 
 func DeleteApiKeyCtx(ctx context.Context, cmd *models.DeleteApiKeyCommand) error {
 	return withDbSession(ctx, func(sess *DBSession) error {
-		var rawSql = "DELETE FROM api_key WHERE id=? and org_id=?"
+		var rawSql = "DELETE FROM api_key WHERE id=? and org_id=? and service_account_id IS NULL"
 		_, err := sess.Exec(rawSql, cmd.Id, cmd.OrgId)
 		return err
 	})
@@ -44,21 +47,20 @@ func AddApiKey(cmd *models.AddApiKeyCommand) error {
 		}
 
 		updated := timeNow()
-		var expires *int64 = nil
-		if cmd.SecondsToLive > 0 {
-			v := updated.Add(time.Second * time.Duration(cmd.SecondsToLive)).Unix()
-			expires = &v
-		} else if cmd.SecondsToLive < 0 {
-			return models.ErrInvalidApiKeyExpiration
+		expires, err := apiKeyExpiration(updated, cmd.SecondsToLive)
+		if err != nil {
+			return err
 		}
 		t := models.ApiKey{
-			OrgId:   cmd.OrgId,
-			Name:    cmd.Name,
-			Role:    cmd.Role,
-			Key:     cmd.Key,
-			Created: updated,
-			Updated: updated,
-			Expires: expires,
+			OrgId:                cmd.OrgId,
+			Name:                 cmd.Name,
+			Role:                 cmd.Role,
+			Key:                  cmd.Key,
+			Created:              updated,
+			Updated:              updated,
+			Expires:              expires,
+			AllowedCidrs:         cmd.AllowedCidrs,
+			AllowedRoutePrefixes: cmd.AllowedRoutePrefixes,
 		}
 
 		if _, err := sess.Insert(&t); err != nil {
@@ -69,6 +71,20 @@ func AddApiKey(cmd *models.AddApiKeyCommand) error {
 	})
 }
 
+// apiKeyExpiration returns the expiration of a key created at the given time, keys
+// without seconds to live never expire.
+func apiKeyExpiration(created time.Time, secondsToLive int64) (*int64, error) {
+	if secondsToLive < 0 {
+		return nil, models.ErrInvalidApiKeyExpiration
+	}
+	if secondsToLive == 0 {
+		return nil, nil
+	}
+
+	expires := created.Add(time.Second * time.Duration(secondsToLive)).Unix()
+	return &expires, nil
+}
+
 func GetApiKeyById(query *models.GetApiKeyByIdQuery) error {
 	var apikey models.ApiKey
 	has, err := x.Id(query.ApiKeyId).Get(&apikey)
@@ -96,3 +112,29 @@ func GetApiKeyByName(query *models.GetApiKeyByNameQuery) error {
 	query.Result = &apikey
 	return nil
 }
+
+func UpdateApiKeysLastUsed(cmd *models.UpdateApiKeysLastUsedCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		for _, usage := range cmd.Usages {
+			rawSql := "UPDATE api_key SET last_used_at = ?, last_used_ip = ? WHERE id = ?"
+			if _, err := sess.Exec(rawSql, usage.LastUsedAt, usage.LastUsedIp, usage.Id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func DeleteUnusedApiKeys(cmd *models.DeleteUnusedApiKeysCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		// service account tokens are managed with their service account
+		rawSql := "DELETE FROM api_key WHERE service_account_id IS NULL AND (last_used_at < ? OR (last_used_at IS NULL AND created < ?))"
+		res, err := sess.Exec(rawSql, cmd.UnusedSince, cmd.UnusedSince)
+		if err != nil {
+			return err
+		}
+
+		cmd.DeletedRows, err = res.RowsAffected()
+		return err
+	})
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestApiKeyDataAccess(t *testing.T) {
@@ -136,3 +137,61 @@ func TestApiKeyErrors(t *testing.T) {
 		})
 	})
 }
+
+func TestApiKeyUsage(t *testing.T) {
+	t.Run("Testing API key usage and restrictions", func(t *testing.T) {
+		InitTestDB(t)
+
+		addKey := func(name string) *models.ApiKey {
+			cmd := models.AddApiKeyCommand{OrgId: 1, Name: name, Key: name, Role: models.ROLE_VIEWER}
+			require.NoError(t, AddApiKey(&cmd))
+			return cmd.Result
+		}
+
+		t.Run("Should store the restrictions of a key", func(t *testing.T) {
+			cmd := models.AddApiKeyCommand{OrgId: 1, Name: "restricted", Key: "restricted", Role: models.ROLE_EDITOR,
+				AllowedCidrs: []string{"10.0.0.0/8"}, AllowedRoutePrefixes: []string{"/api/annotations"}}
+			require.NoError(t, AddApiKey(&cmd))
+
+			query := models.GetApiKeyByNameQuery{KeyName: "restricted", OrgId: 1}
+			require.NoError(t, GetApiKeyByName(&query))
+			assert.Equal(t, []string{"10.0.0.0/8"}, query.Result.AllowedCidrs)
+			assert.Equal(t, []string{"/api/annotations"}, query.Result.AllowedRoutePrefixes)
+		})
+
+		t.Run("Should delete keys unused since the given time", func(t *testing.T) {
+			now := time.Now()
+			usedRecently := addKey("used-recently")
+			usedLongAgo := addKey("used-long-ago")
+			neverUsed := addKey("never-used")
+			neverUsedOld := addKey("never-used-old")
+
+			orgCmd := models.CreateOrgCommand{Name: "Service account org"}
+			require.NoError(t, CreateOrg(&orgCmd))
+			serviceAccountCmd := models.CreateServiceAccountCommand{OrgId: orgCmd.Result.Id, Name: "ci bot", Role: models.ROLE_VIEWER}
+			require.NoError(t, CreateServiceAccount(&serviceAccountCmd))
+			tokenCmd := models.AddServiceAccountTokenCommand{OrgId: orgCmd.Result.Id, ServiceAccountId: serviceAccountCmd.Result.Id, Name: "token-never-used-old", Key: "token"}
+			require.NoError(t, AddServiceAccountToken(&tokenCmd))
+
+			_, err := x.Exec("UPDATE api_key SET created = ? WHERE id IN (?, ?)", now.AddDate(0, 0, -60), neverUsedOld.Id, tokenCmd.Result.Id)
+			require.NoError(t, err)
+
+			err = UpdateApiKeysLastUsed(&models.UpdateApiKeysLastUsedCommand{Usages: []*models.ApiKeyUsage{
+				{Id: usedRecently.Id, LastUsedAt: now.AddDate(0, 0, -1), LastUsedIp: "10.0.0.1"},
+				{Id: usedLongAgo.Id, LastUsedAt: now.AddDate(0, 0, -60), LastUsedIp: "10.0.0.2"},
+			}})
+			require.NoError(t, err)
+
+			cmd := models.DeleteUnusedApiKeysCommand{UnusedSince: now.AddDate(0, 0, -30)}
+			require.NoError(t, DeleteUnusedApiKeys(&cmd))
+			assert.Equal(t, int64(2), cmd.DeletedRows)
+
+			for _, key := range []*models.ApiKey{usedRecently, neverUsed, tokenCmd.Result} {
+				assert.NoError(t, GetApiKeyById(&models.GetApiKeyByIdQuery{ApiKeyId: key.Id}))
+			}
+			for _, key := range []*models.ApiKey{usedLongAgo, neverUsedOld} {
+				assert.Equal(t, models.ErrInvalidApiKey, GetApiKeyById(&models.GetApiKeyByIdQuery{ApiKeyId: key.Id}))
+			}
+		})
+	})
+}
@@ -168,4 +168,108 @@ func addAlertMigrations(mg *Migrator) {
 	mg.AddMigration("Remove unique index org_id_name", NewDropIndexMigration(alert_notification, &Index{
 		Cols: []string{"org_id", "name"}, Type: UniqueIndex,
 	}))
+
+	alertInstance := Table{
+		Name: "alert_instance",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "alert_id", Type: DB_BigInt, Nullable: false},
+			{Name: "labels_hash", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "metric", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "labels", Type: DB_Text, Nullable: false},
+			{Name: "state", Type: DB_NVarchar, Length: 50, Nullable: false},
+			{Name: "eval_data", Type: DB_Text, Nullable: true},
+			{Name: "new_state_date", Type: DB_DateTime, Nullable: false},
+			{Name: "state_changes", Type: DB_BigInt, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"alert_id", "labels_hash"}, Type: UniqueIndex},
+			{Cols: []string{"org_id", "alert_id"}, Type: IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_instance table v1", NewAddTableMigration(alertInstance))
+	mg.AddMigration("add unique index alert_instance alert_id & labels_hash", NewAddIndexMigration(alertInstance, alertInstance.Indices[0]))
+	mg.AddMigration("add index alert_instance org_id & alert_id", NewAddIndexMigration(alertInstance, alertInstance.Indices[1]))
+
+	alertSilence := Table{
+		Name: "alert_silence",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "comment", Type: DB_Text, Nullable: false},
+			{Name: "alert_name", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "folder_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "tags", Type: DB_Text, Nullable: true},
+			{Name: "starts_at", Type: DB_DateTime, Nullable: false},
+			{Name: "ends_at", Type: DB_DateTime, Nullable: false},
+			{Name: "created_by", Type: DB_BigInt, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "ends_at"}, Type: IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_silence table v1", NewAddTableMigration(alertSilence))
+	mg.AddMigration("add index alert_silence org_id & ends_at", NewAddIndexMigration(alertSilence, alertSilence.Indices[0]))
+
+	alertRule := Table{
+		Name: "alert_rule",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "alert_id", Type: DB_BigInt, Nullable: false},
+			{Name: "name", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "settings", Type: DB_Text, Nullable: false},
+			{Name: "provisioned", Type: DB_Bool, Nullable: false},
+			{Name: "version", Type: DB_Int, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+			{Name: "created_by", Type: DB_BigInt, Nullable: false},
+			{Name: "updated_by", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "uid"}, Type: UniqueIndex},
+			{Cols: []string{"org_id", "name"}, Type: UniqueIndex},
+			{Cols: []string{"alert_id"}, Type: IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_rule table v1", NewAddTableMigration(alertRule))
+	mg.AddMigration("add unique index alert_rule org_id & uid", NewAddIndexMigration(alertRule, alertRule.Indices[0]))
+	mg.AddMigration("add unique index alert_rule org_id & name", NewAddIndexMigration(alertRule, alertRule.Indices[1]))
+	mg.AddMigration("add index alert_rule alert_id", NewAddIndexMigration(alertRule, alertRule.Indices[2]))
+
+	alertStateHistory := Table{
+		Name: "alert_state_history",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "alert_id", Type: DB_BigInt, Nullable: false},
+			{Name: "metric", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "labels", Type: DB_Text, Nullable: true},
+			{Name: "prev_state", Type: DB_NVarchar, Length: 50, Nullable: false},
+			{Name: "new_state", Type: DB_NVarchar, Length: 50, Nullable: false},
+			{Name: "eval_matches", Type: DB_Text, Nullable: true},
+			{Name: "condition_evals", Type: DB_Text, Nullable: true},
+			{Name: "error", Type: DB_Text, Nullable: true},
+			{Name: "notifications", Type: DB_Text, Nullable: true},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "alert_id", "created"}, Type: IndexType},
+			{Cols: []string{"created"}, Type: IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_state_history table v1", NewAddTableMigration(alertStateHistory))
+	mg.AddMigration("add index alert_state_history org_id & alert_id & created", NewAddIndexMigration(alertStateHistory, alertStateHistory.Indices[0]))
+	mg.AddMigration("add index alert_state_history created", NewAddIndexMigration(alertStateHistory, alertStateHistory.Indices[1]))
 }
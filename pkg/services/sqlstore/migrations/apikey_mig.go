@@ -82,4 +82,28 @@ func addApiKeyMigrations(mg *Migrator) {
 	mg.AddMigration("Add expires to api_key table", NewAddColumnMigration(apiKeyV2, &Column{
 		Name: "expires", Type: DB_BigInt, Nullable: true,
 	}))
+
+	mg.AddMigration("Add service_account_id to api_key table", NewAddColumnMigration(apiKeyV2, &Column{
+		Name: "service_account_id", Type: DB_BigInt, Nullable: true,
+	}))
+
+	mg.AddMigration("Add index api_key.service_account_id", NewAddIndexMigration(apiKeyV2, &Index{
+		Cols: []string{"service_account_id"},
+	}))
+
+	mg.AddMigration("Add last_used_at to api_key table", NewAddColumnMigration(apiKeyV2, &Column{
+		Name: "last_used_at", Type: DB_DateTime, Nullable: true,
+	}))
+
+	mg.AddMigration("Add last_used_ip to api_key table", NewAddColumnMigration(apiKeyV2, &Column{
+		Name: "last_used_ip", Type: DB_NVarchar, Length: 255, Nullable: true,
+	}))
+
+	mg.AddMigration("Add allowed_cidrs to api_key table", NewAddColumnMigration(apiKeyV2, &Column{
+		Name: "allowed_cidrs", Type: DB_Text, Nullable: true,
+	}))
+
+	mg.AddMigration("Add allowed_route_prefixes to api_key table", NewAddColumnMigration(apiKeyV2, &Column{
+		Name: "allowed_route_prefixes", Type: DB_Text, Nullable: true,
+	}))
 }
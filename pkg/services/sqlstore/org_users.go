@@ -106,6 +106,10 @@ func GetOrgUsers(query *models.GetOrgUsersQuery) error {
 	whereConditions = append(whereConditions, "org_user.org_id = ?")
 	whereParams = append(whereParams, query.OrgId)
 
+	// service accounts are listed by the service account api
+	whereConditions = append(whereConditions, x.Dialect().Quote("user")+".is_service_account = ?")
+	whereParams = append(whereParams, false)
+
 	if query.Query != "" {
 		queryWithWildcards := "%" + query.Query + "%"
 		whereConditions = append(whereConditions, "(email "+dialect.LikeStr()+" ? OR name "+dialect.LikeStr()+" ? OR login "+dialect.LikeStr()+" ?)")
@@ -0,0 +1,248 @@
+package sqlstore
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func init() {
+	bus.AddHandler("sql", CreateServiceAccount)
+	bus.AddHandler("sql", UpdateServiceAccount)
+	bus.AddHandler("sql", DeleteServiceAccount)
+	bus.AddHandler("sql", GetServiceAccounts)
+	bus.AddHandler("sql", GetServiceAccountById)
+
+	bus.AddHandler("sql", AddServiceAccountToken)
+	bus.AddHandler("sql", DeleteServiceAccountToken)
+	bus.AddHandler("sql", GetServiceAccountTokens)
+}
+
+func getServiceAccountSelectSqlBase() string {
+	return `SELECT
+		u.id as id,
+		org_user.org_id as org_id,
+		u.name as name,
+		u.login as login,
+		org_user.role as role,
+		(SELECT COUNT(*) FROM api_key WHERE api_key.service_account_id = u.id) as tokens
+		FROM ` + dialect.Quote("user") + ` as u
+		INNER JOIN org_user ON org_user.user_id = u.id
+		WHERE org_user.org_id = ? AND u.is_service_account = ? `
+}
+
+// serviceAccountLogin returns the login of the user backing a service account, logins
+// are unique across orgs so the org id is part of it.
+func serviceAccountLogin(orgId int64, name string) string {
+	return fmt.Sprintf("%s%d-%s", models.ServiceAccountLoginPrefix, orgId, models.SlugifyTitle(name))
+}
+
+func CreateServiceAccount(cmd *models.CreateServiceAccountCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		if err := verifyExistingOrg(sess, cmd.OrgId); err != nil {
+			return err
+		}
+
+		login := serviceAccountLogin(cmd.OrgId, cmd.Name)
+		if exists, err := sess.Exist(&models.User{Login: login}); err != nil {
+			return err
+		} else if exists {
+			return models.ErrServiceAccountNameTaken
+		}
+
+		now := time.Now()
+		user := models.User{
+			Email:            login,
+			Name:             cmd.Name,
+			Login:            login,
+			OrgId:            cmd.OrgId,
+			IsServiceAccount: true,
+			Created:          now,
+			Updated:          now,
+			LastSeenAt:       now.AddDate(-10, 0, 0),
+		}
+
+		var err error
+		if user.Salt, err = util.GetRandomString(10); err != nil {
+			return err
+		}
+		if user.Rands, err = util.GetRandomString(10); err != nil {
+			return err
+		}
+
+		if _, err := sess.Insert(&user); err != nil {
+			return err
+		}
+
+		orgUser := models.OrgUser{
+			OrgId:   cmd.OrgId,
+			UserId:  user.Id,
+			Role:    cmd.Role,
+			Created: now,
+			Updated: now,
+		}
+
+		if _, err := sess.Insert(&orgUser); err != nil {
+			return err
+		}
+
+		cmd.Result = &models.ServiceAccountDTO{
+			Id:    user.Id,
+			OrgId: cmd.OrgId,
+			Name:  user.Name,
+			Login: user.Login,
+			Role:  cmd.Role,
+			Teams: []string{},
+		}
+		return nil
+	})
+}
+
+func UpdateServiceAccount(cmd *models.UpdateServiceAccountCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		if _, err := getServiceAccount(sess, cmd.OrgId, cmd.Id); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if cmd.Name != "" {
+			if _, err := sess.ID(cmd.Id).Cols("name", "updated").Update(&models.User{Name: cmd.Name, Updated: now}); err != nil {
+				return err
+			}
+		}
+
+		if cmd.Role != "" {
+			rawSql := "UPDATE org_user SET role = ?, updated = ? WHERE org_id = ? AND user_id = ?"
+			if _, err := sess.Exec(rawSql, cmd.Role, now, cmd.OrgId, cmd.Id); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func DeleteServiceAccount(cmd *models.DeleteServiceAccountCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		if _, err := getServiceAccount(sess, cmd.OrgId, cmd.Id); err != nil {
+			return err
+		}
+
+		if err := deleteUserInTransaction(sess, &models.DeleteUserCommand{UserId: cmd.Id}); err != nil {
+			return err
+		}
+
+		_, err := sess.Exec("DELETE FROM api_key WHERE service_account_id = ?", cmd.Id)
+		return err
+	})
+}
+
+func GetServiceAccounts(query *models.GetServiceAccountsQuery) error {
+	query.Result = make([]*models.ServiceAccountDTO, 0)
+
+	rawSql := getServiceAccountSelectSqlBase() + "ORDER BY u.name ASC"
+	return x.SQL(rawSql, query.OrgId, true).Find(&query.Result)
+}
+
+func GetServiceAccountById(query *models.GetServiceAccountByIdQuery) error {
+	serviceAccount, err := getServiceAccount(newSession(), query.OrgId, query.Id)
+	if err != nil {
+		return err
+	}
+
+	teamsQuery := models.GetTeamsByUserQuery{OrgId: query.OrgId, UserId: query.Id}
+	if err := GetTeamsByUser(&teamsQuery); err != nil {
+		return err
+	}
+
+	serviceAccount.Teams = make([]string, len(teamsQuery.Result))
+	for i, team := range teamsQuery.Result {
+		serviceAccount.Teams[i] = team.Name
+	}
+
+	query.Result = serviceAccount
+	return nil
+}
+
+func getServiceAccount(sess *DBSession, orgId int64, id int64) (*models.ServiceAccountDTO, error) {
+	var serviceAccount models.ServiceAccountDTO
+	has, err := sess.SQL(getServiceAccountSelectSqlBase()+"AND u.id = ?", orgId, true, id).Get(&serviceAccount)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, models.ErrServiceAccountNotFound
+	}
+
+	return &serviceAccount, nil
+}
+
+func AddServiceAccountToken(cmd *models.AddServiceAccountTokenCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		serviceAccount, err := getServiceAccount(sess, cmd.OrgId, cmd.ServiceAccountId)
+		if err != nil {
+			return err
+		}
+
+		if exists, err := sess.Exist(&models.ApiKey{OrgId: cmd.OrgId, Name: cmd.Name}); err != nil {
+			return err
+		} else if exists {
+			return models.ErrDuplicateApiKey
+		}
+
+		updated := timeNow()
+		expires, err := apiKeyExpiration(updated, cmd.SecondsToLive)
+		if err != nil {
+			return err
+		}
+
+		token := models.ApiKey{
+			OrgId:            cmd.OrgId,
+			Name:             cmd.Name,
+			Role:             serviceAccount.Role,
+			Key:              cmd.Key,
+			Created:          updated,
+			Updated:          updated,
+			Expires:          expires,
+			ServiceAccountId: &serviceAccount.Id,
+
+			AllowedCidrs:         cmd.AllowedCidrs,
+			AllowedRoutePrefixes: cmd.AllowedRoutePrefixes,
+		}
+
+		if _, err := sess.Insert(&token); err != nil {
+			return err
+		}
+
+		cmd.Result = &token
+		return nil
+	})
+}
+
+func DeleteServiceAccountToken(cmd *models.DeleteServiceAccountTokenCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		rawSql := "DELETE FROM api_key WHERE id = ? AND org_id = ? AND service_account_id = ?"
+		result, err := sess.Exec(rawSql, cmd.Id, cmd.OrgId, cmd.ServiceAccountId)
+		if err != nil {
+			return err
+		}
+
+		if affected, err := result.RowsAffected(); err != nil {
+			return err
+		} else if affected == 0 {
+			return models.ErrInvalidApiKey
+		}
+
+		return nil
+	})
+}
+
+func GetServiceAccountTokens(query *models.GetServiceAccountTokensQuery) error {
+	query.Result = make([]*models.ApiKey, 0)
+
+	return x.Where("org_id = ? AND service_account_id = ?", query.OrgId, query.ServiceAccountId).
+		Asc("name").
+		Find(&query.Result)
+}
@@ -0,0 +1,175 @@
+package sqlstore
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceAccountDataAccess(t *testing.T) {
+	t.Run("Testing service account data access", func(t *testing.T) {
+		InitTestDB(t)
+
+		orgCmd := models.CreateOrgCommand{Name: "Service account org"}
+		require.NoError(t, CreateOrg(&orgCmd))
+		orgId := orgCmd.Result.Id
+
+		createCmd := models.CreateServiceAccountCommand{OrgId: orgId, Name: "CI Bot", Role: models.ROLE_EDITOR}
+		err := CreateServiceAccount(&createCmd)
+		require.NoError(t, err)
+		serviceAccount := createCmd.Result
+
+		t.Run("Should create a user flagged as service account", func(t *testing.T) {
+			assert.Equal(t, fmt.Sprintf("sa-%d-ci-bot", orgId), serviceAccount.Login)
+
+			query := models.GetSignedInUserQuery{UserId: serviceAccount.Id, OrgId: orgId}
+			err := GetSignedInUser(&query)
+			require.NoError(t, err)
+			assert.True(t, query.Result.IsServiceAccount)
+			assert.Equal(t, models.ROLE_EDITOR, query.Result.OrgRole)
+		})
+
+		t.Run("Should not create service accounts with the same name", func(t *testing.T) {
+			err := CreateServiceAccount(&models.CreateServiceAccountCommand{OrgId: orgId, Name: "ci bot", Role: models.ROLE_VIEWER})
+			assert.Equal(t, models.ErrServiceAccountNameTaken, err)
+		})
+
+		t.Run("Should not list service accounts as users", func(t *testing.T) {
+			query := models.SearchUsersQuery{Page: 1, Limit: 10}
+			err := SearchUsers(&query)
+			require.NoError(t, err)
+			for _, user := range query.Result.Users {
+				assert.NotEqual(t, serviceAccount.Id, user.Id)
+			}
+		})
+
+		t.Run("Should not list service accounts as org users", func(t *testing.T) {
+			query := models.GetOrgUsersQuery{OrgId: orgId}
+			err := GetOrgUsers(&query)
+			require.NoError(t, err)
+			for _, user := range query.Result {
+				assert.NotEqual(t, serviceAccount.Id, user.UserId)
+			}
+		})
+
+		t.Run("Should not make service accounts Grafana admins", func(t *testing.T) {
+			err := UpdateUserPermissions(&models.UpdateUserPermissionsCommand{UserId: serviceAccount.Id, IsGrafanaAdmin: true})
+			assert.Equal(t, models.ErrUserIsServiceAccount, err)
+
+			query := models.GetUserByIdQuery{Id: serviceAccount.Id}
+			require.NoError(t, GetUserById(&query))
+			assert.False(t, query.Result.IsAdmin)
+		})
+
+		t.Run("Should update name and role", func(t *testing.T) {
+			err := UpdateServiceAccount(&models.UpdateServiceAccountCommand{Id: serviceAccount.Id, OrgId: orgId, Name: "Deploy Bot", Role: models.ROLE_ADMIN})
+			require.NoError(t, err)
+
+			query := models.GetServiceAccountByIdQuery{Id: serviceAccount.Id, OrgId: orgId}
+			err = GetServiceAccountById(&query)
+			require.NoError(t, err)
+			assert.Equal(t, "Deploy Bot", query.Result.Name)
+			assert.Equal(t, models.ROLE_ADMIN, query.Result.Role)
+		})
+
+		t.Run("Should not find service accounts of other orgs", func(t *testing.T) {
+			err := GetServiceAccountById(&models.GetServiceAccountByIdQuery{Id: serviceAccount.Id, OrgId: orgId + 1})
+			assert.Equal(t, models.ErrServiceAccountNotFound, err)
+		})
+
+		t.Run("Should return teams of the service account", func(t *testing.T) {
+			teamCmd := models.CreateTeamCommand{OrgId: orgId, Name: "deployers"}
+			require.NoError(t, CreateTeam(&teamCmd))
+			require.NoError(t, AddTeamMember(&models.AddTeamMemberCommand{OrgId: orgId, TeamId: teamCmd.Result.Id, UserId: serviceAccount.Id}))
+
+			query := models.GetServiceAccountByIdQuery{Id: serviceAccount.Id, OrgId: orgId}
+			err := GetServiceAccountById(&query)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"deployers"}, query.Result.Teams)
+		})
+
+		t.Run("Given service account tokens", func(t *testing.T) {
+			tokenCmd := models.AddServiceAccountTokenCommand{OrgId: orgId, ServiceAccountId: serviceAccount.Id, Name: "deploy-token", Key: "sa-key-1", SecondsToLive: 3600}
+			require.NoError(t, AddServiceAccountToken(&tokenCmd))
+			require.NoError(t, AddServiceAccountToken(&models.AddServiceAccountTokenCommand{OrgId: orgId, ServiceAccountId: serviceAccount.Id, Name: "backup-token", Key: "sa-key-2"}))
+			require.NoError(t, AddApiKey(&models.AddApiKeyCommand{OrgId: orgId, Name: "org-key", Key: "org-key", Role: models.ROLE_VIEWER}))
+
+			t.Run("Should link tokens to the service account", func(t *testing.T) {
+				query := models.GetApiKeyByNameQuery{KeyName: "deploy-token", OrgId: orgId}
+				require.NoError(t, GetApiKeyByName(&query))
+				require.NotNil(t, query.Result.ServiceAccountId)
+				assert.Equal(t, serviceAccount.Id, *query.Result.ServiceAccountId)
+				assert.NotNil(t, query.Result.Expires)
+			})
+
+			t.Run("Should not add tokens with the name of another key", func(t *testing.T) {
+				err := AddServiceAccountToken(&models.AddServiceAccountTokenCommand{OrgId: orgId, ServiceAccountId: serviceAccount.Id, Name: "org-key", Key: "sa-key-3"})
+				assert.Equal(t, models.ErrDuplicateApiKey, err)
+			})
+
+			t.Run("Should list tokens apart from org api keys", func(t *testing.T) {
+				tokensQuery := models.GetServiceAccountTokensQuery{OrgId: orgId, ServiceAccountId: serviceAccount.Id}
+				require.NoError(t, GetServiceAccountTokens(&tokensQuery))
+				require.Len(t, tokensQuery.Result, 2)
+				assert.Equal(t, "backup-token", tokensQuery.Result[0].Name)
+
+				keysQuery := models.GetApiKeysQuery{OrgId: orgId, IncludeExpired: true}
+				require.NoError(t, GetApiKeys(&keysQuery))
+				for _, key := range keysQuery.Result {
+					assert.Nil(t, key.ServiceAccountId)
+				}
+			})
+
+			t.Run("Should store the restrictions of a token", func(t *testing.T) {
+				restrictedCmd := models.AddServiceAccountTokenCommand{OrgId: orgId, ServiceAccountId: serviceAccount.Id, Name: "restricted-token", Key: "sa-key-4",
+					AllowedCidrs: []string{"10.0.0.0/8"}, AllowedRoutePrefixes: []string{"/api/dashboards"}}
+				require.NoError(t, AddServiceAccountToken(&restrictedCmd))
+
+				query := models.GetApiKeyByNameQuery{KeyName: "restricted-token", OrgId: orgId}
+				require.NoError(t, GetApiKeyByName(&query))
+				assert.Equal(t, []string{"10.0.0.0/8"}, query.Result.AllowedCidrs)
+				assert.Equal(t, []string{"/api/dashboards"}, query.Result.AllowedRoutePrefixes)
+			})
+
+			t.Run("Should update last used", func(t *testing.T) {
+				now := timeNow()
+				require.NoError(t, UpdateApiKeysLastUsed(&models.UpdateApiKeysLastUsedCommand{
+					Usages: []*models.ApiKeyUsage{{Id: tokenCmd.Result.Id, LastUsedAt: now, LastUsedIp: "10.0.0.1"}},
+				}))
+
+				query := models.GetApiKeyByIdQuery{ApiKeyId: tokenCmd.Result.Id}
+				require.NoError(t, GetApiKeyById(&query))
+				require.NotNil(t, query.Result.LastUsedAt)
+				assert.Equal(t, now.Unix(), query.Result.LastUsedAt.Unix())
+				assert.Equal(t, "10.0.0.1", query.Result.LastUsedIp)
+			})
+
+			t.Run("Should delete tokens of the service account only", func(t *testing.T) {
+				err := DeleteServiceAccountToken(&models.DeleteServiceAccountTokenCommand{Id: tokenCmd.Result.Id, OrgId: orgId, ServiceAccountId: serviceAccount.Id + 1})
+				assert.Equal(t, models.ErrInvalidApiKey, err)
+
+				err = DeleteServiceAccountToken(&models.DeleteServiceAccountTokenCommand{Id: tokenCmd.Result.Id, OrgId: orgId, ServiceAccountId: serviceAccount.Id})
+				require.NoError(t, err)
+			})
+
+			t.Run("Should delete the service account with its tokens and team memberships", func(t *testing.T) {
+				err := DeleteServiceAccount(&models.DeleteServiceAccountCommand{Id: serviceAccount.Id, OrgId: orgId})
+				require.NoError(t, err)
+
+				err = GetApiKeyByName(&models.GetApiKeyByNameQuery{KeyName: "backup-token", OrgId: orgId})
+				assert.Equal(t, models.ErrInvalidApiKey, err)
+
+				teamsQuery := models.GetTeamsByUserQuery{OrgId: orgId, UserId: serviceAccount.Id}
+				require.NoError(t, GetTeamsByUser(&teamsQuery))
+				assert.Empty(t, teamsQuery.Result)
+
+				listQuery := models.GetServiceAccountsQuery{OrgId: orgId}
+				require.NoError(t, GetServiceAccounts(&listQuery))
+				assert.Empty(t, listQuery.Result)
+			})
+		})
+	})
+}
@@ -34,6 +34,16 @@ func (sb *SqlBuilder) writeDashboardPermissionFilter(user *models.SignedInUser,
 		return
 	}
 
+	sb.sql.WriteString(` AND
+	(
+		`)
+	sb.writeDashboardPermissionCondition(user, permission)
+	sb.sql.WriteString(`
+	)`)
+}
+
+// writeDashboardPermissionCondition writes the permission filter condition without the leading AND
+func (sb *SqlBuilder) writeDashboardPermissionCondition(user *models.SignedInUser, permission models.PermissionType) {
 	okRoles := []interface{}{user.OrgRole}
 
 	if user.OrgRole == models.ROLE_EDITOR {
@@ -42,9 +52,7 @@ func (sb *SqlBuilder) writeDashboardPermissionFilter(user *models.SignedInUser,
 
 	falseStr := dialect.BooleanStr(false)
 
-	sb.sql.WriteString(` AND
-	(
-		dashboard.id IN (
+	sb.sql.WriteString(`dashboard.id IN (
 			SELECT distinct DashboardId from (
 				SELECT d.id AS DashboardId
 					FROM dashboard AS d
@@ -81,8 +89,7 @@ func (sb *SqlBuilder) writeDashboardPermissionFilter(user *models.SignedInUser,
 							da.role IN (?` + strings.Repeat(",?", len(okRoles)-1) + `)
 						)
 			) AS a
-		)
-	)`)
+		)`)
 
 	sb.params = append(sb.params, user.OrgId, permission, user.UserId, user.UserId)
 	sb.params = append(sb.params, okRoles...)
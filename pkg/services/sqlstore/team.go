@@ -324,6 +324,16 @@ func RemoveTeamMember(cmd *models.RemoveTeamMemberCommand) error {
 			}
 		}
 
+		if cmd.ProtectExternal {
+			member, err := getTeamMember(sess, cmd.OrgId, cmd.TeamId, cmd.UserId)
+			if err != nil {
+				return err
+			}
+			if member.External {
+				return models.ErrTeamMemberIsExternal
+			}
+		}
+
 		var rawSql = "DELETE FROM team_member WHERE org_id=? and team_id=? and user_id=?"
 		res, err := sess.Exec(rawSql, cmd.OrgId, cmd.TeamId, cmd.UserId)
 		if err != nil {
@@ -206,6 +206,23 @@ func TestTeamCommandsAndQueries(t *testing.T) {
 				So(len(q2.Result), ShouldEqual, 0)
 			})
 
+			Convey("When ProtectExternal is set to true", func() {
+				err = AddTeamMember(&models.AddTeamMemberCommand{OrgId: testOrgId, TeamId: group1.Result.Id, UserId: userIds[0], External: true})
+				So(err, ShouldBeNil)
+				err = AddTeamMember(&models.AddTeamMemberCommand{OrgId: testOrgId, TeamId: group1.Result.Id, UserId: userIds[1]})
+				So(err, ShouldBeNil)
+
+				Convey("A user should not be able to remove an external member", func() {
+					err = RemoveTeamMember(&models.RemoveTeamMemberCommand{OrgId: testOrgId, TeamId: group1.Result.Id, UserId: userIds[0], ProtectExternal: true})
+					So(err, ShouldEqual, models.ErrTeamMemberIsExternal)
+				})
+
+				Convey("A user should be able to remove a member that isn't external", func() {
+					err = RemoveTeamMember(&models.RemoveTeamMemberCommand{OrgId: testOrgId, TeamId: group1.Result.Id, UserId: userIds[1], ProtectExternal: true})
+					So(err, ShouldBeNil)
+				})
+			})
+
 			Convey("When ProtectLastAdmin is set to true", func() {
 				err = AddTeamMember(&models.AddTeamMemberCommand{OrgId: testOrgId, TeamId: group1.Result.Id, UserId: userIds[0], Permission: models.PERMISSION_ADMIN})
 				So(err, ShouldBeNil)
@@ -383,6 +383,7 @@ func GetSignedInUser(query *models.GetSignedInUserQuery) error {
 	var rawSql = `SELECT
 		u.id             as user_id,
 		u.is_admin       as is_grafana_admin,
+		u.is_service_account as is_service_account,
 		u.email          as email,
 		u.login          as login,
 		u.name           as name,
@@ -440,8 +441,9 @@ func SearchUsers(query *models.SearchUsersQuery) error {
 
 	queryWithWildcards := "%" + query.Query + "%"
 
-	whereConditions := make([]string, 0)
-	whereParams := make([]interface{}, 0)
+	// service accounts are listed by the service account api
+	whereConditions := []string{"u.is_service_account = ?"}
+	whereParams := []interface{}{false}
 	sess := x.Table("user").Alias("u")
 
 	// Join with only most recent auth module
@@ -596,6 +598,11 @@ func UpdateUserPermissions(cmd *models.UpdateUserPermissionsCommand) error {
 			return err
 		}
 
+		// org admins can create tokens for service accounts
+		if user.IsServiceAccount && cmd.IsGrafanaAdmin {
+			return models.ErrUserIsServiceAccount
+		}
+
 		user.IsAdmin = cmd.IsGrafanaAdmin
 		sess.UseBool("is_admin")
 
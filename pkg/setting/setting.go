@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -146,6 +147,9 @@ var (
 	LoginCookieName      string
 	LoginMaxLifetimeDays int
 
+	// Proxies trusted to set the client address of API key requests
+	ApiKeyTrustedProxies []*net.IPNet
+
 	AnonymousEnabled bool
 	AnonymousOrgName string
 	AnonymousOrgRole string
@@ -204,6 +208,12 @@ var (
 	AlertingMaxAttempts         int
 	AlertingMinInterval         int64
 
+	// AlertingStateHistoryRetention is how long alert state history is kept, zero keeps it forever.
+	AlertingStateHistoryRetention time.Duration
+
+	// AlertingExpiredSilenceRetention is how long silences are kept after they ended, zero keeps them forever.
+	AlertingExpiredSilenceRetention time.Duration
+
 	// Explore UI
 	ExploreEnabled bool
 
@@ -283,7 +293,8 @@ type Cfg struct {
 
 	EditorsCanAdmin bool
 
-	ApiKeyMaxSecondsToLive int64
+	ApiKeyMaxSecondsToLive      int64
+	ApiKeyDeleteUnusedAfterDays int
 
 	// Use to enable new features which may still be in alpha/beta stage.
 	FeatureToggles map[string]bool
@@ -849,6 +860,11 @@ func (cfg *Cfg) Load(args *CommandLineArgs) error {
 	LoginMaxLifetimeDays = auth.Key("login_maximum_lifetime_days").MustInt(30)
 	cfg.LoginMaxLifetimeDays = LoginMaxLifetimeDays
 	cfg.ApiKeyMaxSecondsToLive = auth.Key("api_key_max_seconds_to_live").MustInt64(-1)
+	cfg.ApiKeyDeleteUnusedAfterDays = auth.Key("api_key_delete_unused_after_days").MustInt(0)
+	ApiKeyTrustedProxies, err = parseTrustedProxies(auth.Key("api_key_trusted_proxies").String())
+	if err != nil {
+		return err
+	}
 
 	cfg.TokenRotationIntervalMinutes = auth.Key("token_rotation_interval_minutes").MustInt(10)
 	if cfg.TokenRotationIntervalMinutes < 2 {
@@ -985,6 +1001,10 @@ func (cfg *Cfg) Load(args *CommandLineArgs) error {
 	AlertingNotificationTimeout = time.Second * time.Duration(notificationTimeoutSeconds)
 	AlertingMaxAttempts = alerting.Key("max_attempts").MustInt(3)
 	AlertingMinInterval = alerting.Key("min_interval_seconds").MustInt64(1)
+	stateHistoryRetentionDays := alerting.Key("state_history_retention_days").MustInt64(90)
+	AlertingStateHistoryRetention = time.Hour * 24 * time.Duration(stateHistoryRetentionDays)
+	expiredSilenceRetentionDays := alerting.Key("expired_silence_retention_days").MustInt64(7)
+	AlertingExpiredSilenceRetention = time.Hour * 24 * time.Duration(expiredSilenceRetentionDays)
 
 	explore := iniFile.Section("explore")
 	ExploreEnabled = explore.Key("enabled").MustBool(true)
@@ -1069,6 +1089,34 @@ func (cfg *Cfg) Load(args *CommandLineArgs) error {
 	return nil
 }
 
+// parseTrustedProxies parses a comma separated list of IP addresses and CIDR ranges
+func parseTrustedProxies(value string) ([]*net.IPNet, error) {
+	proxies := []*net.IPNet{}
+	for _, proxy := range util.SplitString(value) {
+		if proxy == "" {
+			continue
+		}
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid api_key_trusted_proxies address %q", proxy)
+			}
+			if ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid api_key_trusted_proxies range %q: %w", proxy, err)
+		}
+		proxies = append(proxies, network)
+	}
+	return proxies, nil
+}
+
 func valueAsString(section *ini.Section, keyName string, defaultValue string) (value string, err error) {
 	defer func() {
 		if err_ := recover(); err_ != nil {
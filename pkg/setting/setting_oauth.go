@@ -8,6 +8,9 @@ type OAuthInfo struct {
 	EmailAttributeName     string
 	EmailAttributePath     string
 	RoleAttributePath      string
+	GroupsAttributePath    string
+	OrgMapping             []string
+	TeamMapping            []string
 	AllowedDomains         []string
 	HostedDomain           string
 	ApiUrl                 string
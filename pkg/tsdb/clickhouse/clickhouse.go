@@ -0,0 +1,208 @@
+package clickhouse
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb"
+	"github.com/grafana/grafana/pkg/tsdb/sqleng"
+	"xorm.io/core"
+)
+
+func init() {
+	tsdb.RegisterTsdbQueryEndpoint(models.DS_CLICKHOUSE, newClickHouseQueryEndpoint)
+}
+
+// ClickHouse error codes of errors caused by the query, other errors are logged
+// and not shown to the user.
+const (
+	errNumberOfArgumentsDoesntMatch = 42
+	errIllegalTypeOfArgument        = 43
+	errUnknownFunction              = 46
+	errUnknownIdentifier            = 47
+	errTypeMismatch                 = 53
+	errUnknownTable                 = 60
+	errSyntaxError                  = 62
+	errUnknownDatabase              = 81
+)
+
+func newClickHouseQueryEndpoint(datasource *models.DataSource) (tsdb.TsdbQueryEndpoint, error) {
+	logger := log.New("tsdb.clickhouse")
+
+	transport, err := datasource.GetHttpTransport()
+	if err != nil {
+		return nil, err
+	}
+
+	// queries are canceled with the request, no timeout is set on the client
+	clientName := fmt.Sprintf("ds%d", datasource.Id)
+	registerHTTPClient(clientName, &http.Client{Transport: transport})
+
+	cnnstr, err := generateConnectionString(datasource, clientName)
+	if err != nil {
+		return nil, err
+	}
+
+	if setting.Env == setting.DEV {
+		logger.Debug("getEngine", "url", datasource.Url, "database", datasource.Database, "user", datasource.User)
+	}
+
+	config := sqleng.SqlQueryEndpointConfiguration{
+		DriverName:        driverName,
+		ConnectionString:  cnnstr,
+		Datasource:        datasource,
+		TimeColumnNames:   []string{"time", "time_sec"},
+		MetricColumnTypes: []string{"String", "Nullable(String)", "LowCardinality(String)", "LowCardinality(Nullable(String))"},
+	}
+
+	rowTransformer := clickHouseQueryResultTransformer{
+		log: logger,
+	}
+
+	return sqleng.NewSqlQueryEndpoint(&config, &rowTransformer, newClickHouseMacroEngine(), logger)
+}
+
+func generateConnectionString(datasource *models.DataSource, clientName string) (string, error) {
+	rawURL := datasource.Url
+	if !strings.HasPrefix(rawURL, "http://") && !strings.HasPrefix(rawURL, "https://") {
+		rawURL = "http://" + rawURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid data source URL: %v", err)
+	}
+
+	params := url.Values{}
+	params.Set("client", clientName)
+	if datasource.Database != "" {
+		params.Set("database", datasource.Database)
+	}
+	if datasource.User != "" {
+		params.Set("user", datasource.User)
+		params.Set("password", datasource.DecryptedPassword())
+	}
+	u.RawQuery = params.Encode()
+
+	return u.String(), nil
+}
+
+type clickHouseQueryResultTransformer struct {
+	log log.Logger
+}
+
+func (t *clickHouseQueryResultTransformer) TransformQueryResult(columnTypes []*sql.ColumnType, rows *core.Rows) (tsdb.RowValues, error) {
+	values := make([]interface{}, len(columnTypes))
+	valuePtrs := make([]interface{}, len(columnTypes))
+
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+
+	if err := rows.Scan(valuePtrs...); err != nil {
+		return nil, err
+	}
+
+	for i := range values {
+		value, err := convertValue(columnTypes[i].DatabaseTypeName(), values[i])
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+
+	return values, nil
+}
+
+func (t *clickHouseQueryResultTransformer) TransformQueryError(err error) error {
+	if chErr, ok := err.(*clickHouseError); ok {
+		switch chErr.code {
+		case errNumberOfArgumentsDoesntMatch, errIllegalTypeOfArgument, errUnknownFunction, errUnknownIdentifier,
+			errTypeMismatch, errUnknownTable, errSyntaxError, errUnknownDatabase:
+			return err
+		}
+
+		t.log.Error("query error", "err", err)
+		return errQueryFailed
+	}
+
+	return err
+}
+
+var errQueryFailed = errors.New("Query failed. Please inspect Grafana server log for details")
+
+// convertValue converts a value read from the HTTP interface to the Go type of its
+// ClickHouse column type. Integers are converted to int64, or uint64 for UInt64,
+// wide integers, floats and decimals to float64, dates to time.Time and all other
+// types are kept as strings.
+func convertValue(columnType string, value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	var err error
+	switch baseType(columnType) {
+	case "Int8", "Int16", "Int32", "Int64", "UInt8", "UInt16", "UInt32":
+		value, err = strconv.ParseInt(s, 10, 64)
+	case "UInt64":
+		value, err = strconv.ParseUint(s, 10, 64)
+	case "Int128", "Int256", "UInt128", "UInt256", "Float32", "Float64",
+		"Decimal", "Decimal32", "Decimal64", "Decimal128", "Decimal256":
+		value, err = strconv.ParseFloat(s, 64)
+	case "Bool":
+		value, err = strconv.ParseBool(s)
+	case "Date", "Date32":
+		value, err = time.ParseInLocation("2006-01-02", s, time.UTC)
+	case "DateTime", "DateTime64":
+		value, err = parseDateTime(s)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert value %q of column type %s: %v", s, columnType, err)
+	}
+
+	return value, nil
+}
+
+// baseType returns the type without the Nullable and LowCardinality wrappers and
+// the parameters, for example DateTime for Nullable(DateTime('Europe/Berlin')).
+func baseType(columnType string) string {
+	for {
+		unwrapped := false
+		for _, wrapper := range []string{"Nullable(", "LowCardinality("} {
+			if strings.HasPrefix(columnType, wrapper) && strings.HasSuffix(columnType, ")") {
+				columnType = columnType[len(wrapper) : len(columnType)-1]
+				unwrapped = true
+			}
+		}
+		if !unwrapped {
+			break
+		}
+	}
+
+	if i := strings.Index(columnType, "("); i != -1 {
+		columnType = columnType[:i]
+	}
+
+	return columnType
+}
+
+// parseDateTime parses the ISO format of date_time_output_format and falls back to
+// the default format, which is in the server time zone and read as UTC.
+func parseDateTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+
+	return time.ParseInLocation("2006-01-02 15:04:05.999999999", s, time.UTC)
+}
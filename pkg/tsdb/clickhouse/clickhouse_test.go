@@ -0,0 +1,259 @@
+package clickhouse
+
+import (
+	"context"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/securejsondata"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/tsdb"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestClickHouse(t *testing.T) {
+	var (
+		sentQuery   string
+		sentParams  url.Values
+		sentHeaders http.Header
+		status      int
+		body        string
+	)
+
+	// mocks the HTTP interface of ClickHouse, the rows are returned in the
+	// TabSeparatedWithNamesAndTypes format requested by the driver
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		sentQuery = string(b)
+		sentParams = r.URL.Query()
+		sentHeaders = r.Header
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	Convey("ClickHouse", t, func() {
+		ds := &models.DataSource{
+			Id:             1,
+			Type:           models.DS_CLICKHOUSE,
+			Url:            server.URL,
+			Database:       "grafana",
+			User:           "grafana",
+			JsonData:       simplejson.New(),
+			SecureJsonData: securejsondata.GetEncryptedJsonData(map[string]string{"password": "password"}),
+		}
+
+		endpoint, err := newClickHouseQueryEndpoint(ds)
+		So(err, ShouldBeNil)
+
+		from := time.Date(2018, 4, 12, 18, 0, 0, 0, time.UTC)
+		to := from.Add(5 * time.Minute)
+		fromStr := strconv.FormatInt(from.UnixNano()/int64(time.Millisecond), 10)
+		toStr := strconv.FormatInt(to.UnixNano()/int64(time.Millisecond), 10)
+
+		newQuery := func(format, rawSQL string) *tsdb.TsdbQuery {
+			return &tsdb.TsdbQuery{
+				TimeRange: tsdb.NewTimeRange(fromStr, toStr),
+				Queries: []*tsdb.Query{
+					{
+						RefId:      "A",
+						DataSource: ds,
+						Model: simplejson.NewFromAny(map[string]interface{}{
+							"rawSql": rawSQL,
+							"format": format,
+						}),
+					},
+				},
+			}
+		}
+
+		respond := func(rows ...string) {
+			status = http.StatusOK
+			body = strings.Join(rows, "\n") + "\n"
+		}
+
+		Convey("Should send the interpolated query with the connection settings", func() {
+			respond("time\tvalue", "DateTime\tFloat64")
+
+			res, err := endpoint.Query(context.Background(), ds, newQuery("time_series", "SELECT $__timeGroupAlias(ts, '1m'), avg(v) AS value FROM metrics WHERE $__timeFilter(ts) GROUP BY time"))
+			So(err, ShouldBeNil)
+			So(res.Results["A"].Error, ShouldBeNil)
+
+			So(sentQuery, ShouldEqual, "SELECT toStartOfInterval(ts, INTERVAL 60 second) AS \"time\", avg(v) AS value FROM metrics WHERE ts BETWEEN toDateTime(1523556000) AND toDateTime(1523556300) GROUP BY time")
+			So(sentParams.Get("database"), ShouldEqual, "grafana")
+			So(sentParams.Get("default_format"), ShouldEqual, "TabSeparatedWithNamesAndTypes")
+			So(sentParams.Get("date_time_output_format"), ShouldEqual, "iso")
+			So(sentParams.Get("password"), ShouldEqual, "")
+			So(sentHeaders.Get("X-ClickHouse-User"), ShouldEqual, "grafana")
+			So(sentHeaders.Get("X-ClickHouse-Key"), ShouldEqual, "password")
+		})
+
+		Convey("Should convert column types in table results", func() {
+			respond(
+				"i32\tu64\tnullable_f64\tdecimal\tdate\tdatetime\tdatetime64\ttext\tenum\tflag",
+				"Int32\tUInt64\tNullable(Float64)\tDecimal(10, 2)\tDate\tDateTime('Europe/Berlin')\tDateTime64(3)\tLowCardinality(String)\tEnum8('a' = 1, 'b' = 2)\tBool",
+				"-5\t18446744073709551615\t1.5\t10.25\t2018-04-12\t2018-04-12T18:00:00Z\t2018-04-12T18:00:00.123Z\ttab\\there\ta\ttrue",
+				"0\t0\t\\N\t0.00\t2018-04-13\t2018-04-12T18:01:00Z\t2018-04-12T18:01:00.456Z\t\\N\tb\tfalse",
+			)
+
+			res, err := endpoint.Query(context.Background(), ds, newQuery("table", "SELECT * FROM types"))
+			So(err, ShouldBeNil)
+
+			queryRes := res.Results["A"]
+			So(queryRes.Error, ShouldBeNil)
+			So(queryRes.Tables, ShouldHaveLength, 1)
+
+			table := queryRes.Tables[0]
+			So(table.Columns, ShouldHaveLength, 10)
+			So(table.Columns[0].Text, ShouldEqual, "i32")
+			So(table.Rows, ShouldHaveLength, 2)
+
+			row := table.Rows[0]
+			So(row[0], ShouldEqual, int64(-5))
+			So(row[1], ShouldEqual, uint64(18446744073709551615))
+			So(row[2], ShouldEqual, 1.5)
+			So(row[3], ShouldEqual, 10.25)
+			So(row[4], ShouldEqual, time.Date(2018, 4, 12, 0, 0, 0, 0, time.UTC))
+			So(row[5], ShouldEqual, from)
+			So(row[6], ShouldEqual, from.Add(123*time.Millisecond))
+			So(row[7], ShouldEqual, "tab\there")
+			So(row[8], ShouldEqual, "a")
+			So(row[9], ShouldEqual, true)
+
+			row = table.Rows[1]
+			So(row[2], ShouldBeNil)
+			So(row[3], ShouldEqual, 0.0)
+			So(row[7], ShouldBeNil)
+		})
+
+		Convey("Should return time series with the metric column", func() {
+			respond(
+				"time\thost\tvalue",
+				"DateTime\tLowCardinality(String)\tNullable(Float64)",
+				"2018-04-12T18:00:00Z\tserver-a\t1.5",
+				"2018-04-12T18:00:00Z\tserver-b\t2.5",
+				"2018-04-12T18:01:00Z\tserver-a\t\\N",
+				"2018-04-12T18:01:00Z\tserver-b\t3",
+			)
+
+			res, err := endpoint.Query(context.Background(), ds, newQuery("time_series", "SELECT $__timeGroupAlias(ts, '1m'), host, avg(v) AS value FROM metrics GROUP BY time, host ORDER BY time"))
+			So(err, ShouldBeNil)
+
+			queryRes := res.Results["A"]
+			So(queryRes.Error, ShouldBeNil)
+			So(queryRes.Series, ShouldHaveLength, 2)
+
+			So(queryRes.Series[0].Name, ShouldEqual, "server-a")
+			So(queryRes.Series[0].Points, ShouldHaveLength, 2)
+			So(queryRes.Series[0].Points[0][0].Float64, ShouldEqual, 1.5)
+			So(queryRes.Series[0].Points[0][1].Float64, ShouldEqual, float64(from.Unix()*1000))
+			So(queryRes.Series[0].Points[1][0].Valid, ShouldBeFalse)
+
+			So(queryRes.Series[1].Name, ShouldEqual, "server-b")
+			So(queryRes.Series[1].Points[1][0].Float64, ShouldEqual, 3)
+		})
+
+		Convey("Should fill missing points with the fill mode of the time group macro", func() {
+			respond(
+				"time\tvalue",
+				"DateTime\tUInt64",
+				"2018-04-12T18:01:00Z\t10",
+				"2018-04-12T18:03:00Z\t30",
+			)
+
+			res, err := endpoint.Query(context.Background(), ds, newQuery("time_series", "SELECT $__timeGroupAlias(ts, '1m', previous), count() AS value FROM metrics GROUP BY time ORDER BY time"))
+			So(err, ShouldBeNil)
+
+			queryRes := res.Results["A"]
+			So(queryRes.Error, ShouldBeNil)
+			So(queryRes.Series, ShouldHaveLength, 1)
+
+			points := queryRes.Series[0].Points
+			So(points, ShouldHaveLength, 5)
+			So(points[0][0].Valid, ShouldBeFalse)
+			So(points[1][0].Float64, ShouldEqual, 10)
+			So(points[2][0].Float64, ShouldEqual, 10)
+			So(points[2][1].Float64, ShouldEqual, float64(from.Add(2*time.Minute).Unix()*1000))
+			So(points[3][0].Float64, ShouldEqual, 30)
+			So(points[4][0].Float64, ShouldEqual, 30)
+		})
+
+		Convey("Should return errors caused by the query", func() {
+			status = http.StatusBadRequest
+			body = "Code: 62, e.displayText() = DB::Exception: Syntax error: failed at position 1\n"
+
+			res, err := endpoint.Query(context.Background(), ds, newQuery("table", "SELEC 1"))
+			So(err, ShouldBeNil)
+			So(res.Results["A"].Error.Error(), ShouldStartWith, "Code: 62, e.displayText() = DB::Exception: Syntax error")
+		})
+
+		Convey("Should hide other errors", func() {
+			status = http.StatusInternalServerError
+			body = "Code: 241, e.displayText() = DB::Exception: Memory limit exceeded\n"
+
+			res, err := endpoint.Query(context.Background(), ds, newQuery("table", "SELECT 1"))
+			So(err, ShouldBeNil)
+			So(res.Results["A"].Error, ShouldEqual, errQueryFailed)
+		})
+
+		Convey("Should return exceptions written while the result is streamed", func() {
+			respond(
+				"value",
+				"UInt64",
+				"1",
+				"Code: 241, e.displayText() = DB::Exception: Memory limit exceeded",
+			)
+
+			res, err := endpoint.Query(context.Background(), ds, newQuery("table", "SELECT number AS value FROM numbers(10)"))
+			So(err, ShouldBeNil)
+			So(res.Results["A"].Error, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestConvertValue(t *testing.T) {
+	Convey("Converting ClickHouse values", t, func() {
+		Convey("Should unwrap nullable and low cardinality types", func() {
+			So(baseType("Nullable(Int8)"), ShouldEqual, "Int8")
+			So(baseType("LowCardinality(Nullable(String))"), ShouldEqual, "String")
+			So(baseType("Nullable(DateTime('Europe/Berlin'))"), ShouldEqual, "DateTime")
+			So(baseType("Decimal(18, 4)"), ShouldEqual, "Decimal")
+			So(baseType("Array(Nullable(Int8))"), ShouldEqual, "Array")
+		})
+
+		Convey("Should parse special float values", func() {
+			value, err := convertValue("Float64", "nan")
+			So(err, ShouldBeNil)
+			So(value, ShouldHaveSameTypeAs, float64(0))
+
+			value, err = convertValue("Float32", "-inf")
+			So(err, ShouldBeNil)
+			So(math.IsInf(value.(float64), -1), ShouldBeTrue)
+		})
+
+		Convey("Should parse date times without time zone as UTC", func() {
+			value, err := convertValue("DateTime", "2018-04-12 18:00:00")
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, time.Date(2018, 4, 12, 18, 0, 0, 0, time.UTC))
+		})
+
+		Convey("Should keep other types as strings", func() {
+			value, err := convertValue("Array(String)", "['a','b']")
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "['a','b']")
+		})
+
+		Convey("Should return an error for invalid values", func() {
+			_, err := convertValue("Int32", "abc")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
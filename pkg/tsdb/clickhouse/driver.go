@@ -0,0 +1,294 @@
+package clickhouse
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"xorm.io/core"
+)
+
+// driverName is the name the ClickHouse HTTP interface driver is registered with
+// in database/sql and xorm.
+const driverName = "clickhouse"
+
+var (
+	httpClients     = make(map[string]*http.Client)
+	httpClientsLock sync.RWMutex
+
+	errNotSupported = errors.New("clickhouse: only queries are supported")
+)
+
+func init() {
+	sql.Register(driverName, &httpDriver{})
+	core.RegisterDriver(driverName, &xormDriver{})
+}
+
+// registerHTTPClient registers a HTTP client that can be used in a connection
+// string with the client parameter, like mysql.RegisterTLSConfig.
+func registerHTTPClient(name string, client *http.Client) {
+	httpClientsLock.Lock()
+	defer httpClientsLock.Unlock()
+
+	httpClients[name] = client
+}
+
+// xormDriver makes the driver usable by xorm. ClickHouse has no xorm dialect, since
+// the engine is only used for raw queries the MySQL dialect, which quotes identifiers
+// the same way as ClickHouse, is used.
+type xormDriver struct{}
+
+func (d *xormDriver) Parse(driverName, dataSourceName string) (*core.Uri, error) {
+	u, err := url.Parse(dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &core.Uri{DbType: core.MYSQL, DbName: u.Query().Get("database")}, nil
+}
+
+// httpDriver is a database/sql driver for the HTTP interface of ClickHouse. The
+// connection string is the URL of the interface with the user, password, database
+// and client parameters. Results are read in the TabSeparatedWithNamesAndTypes format
+// and all values are returned as strings, the conversion to Go types is done by the
+// query result transformer based on the ClickHouse column types.
+type httpDriver struct{}
+
+func (d *httpDriver) Open(dsn string) (driver.Conn, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	params := u.Query()
+	c := &httpConn{
+		client:   http.DefaultClient,
+		user:     params.Get("user"),
+		password: params.Get("password"),
+	}
+
+	if name := params.Get("client"); name != "" {
+		httpClientsLock.RLock()
+		client, ok := httpClients[name]
+		httpClientsLock.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("clickhouse: unknown http client %s", name)
+		}
+		c.client = client
+	}
+
+	query := url.Values{}
+	if database := params.Get("database"); database != "" {
+		query.Set("database", database)
+	}
+	query.Set("default_format", "TabSeparatedWithNamesAndTypes")
+	query.Set("date_time_output_format", "iso")
+	u.RawQuery = query.Encode()
+	c.url = u.String()
+
+	return c, nil
+}
+
+type httpConn struct {
+	client   *http.Client
+	url      string
+	user     string
+	password string
+}
+
+func (c *httpConn) Prepare(query string) (driver.Stmt, error) {
+	return &httpStmt{conn: c, query: query}, nil
+}
+
+func (c *httpConn) Close() error {
+	return nil
+}
+
+func (c *httpConn) Begin() (driver.Tx, error) {
+	return nil, errNotSupported
+}
+
+func (c *httpConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if len(args) > 0 {
+		return nil, errors.New("clickhouse: query arguments are not supported")
+	}
+
+	return c.query(ctx, query)
+}
+
+func (c *httpConn) query(ctx context.Context, query string) (driver.Rows, error) {
+	req, err := http.NewRequest(http.MethodPost, c.url, strings.NewReader(query))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if c.user != "" {
+		req.Header.Set("X-ClickHouse-User", c.user)
+		req.Header.Set("X-ClickHouse-Key", c.password)
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		body, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, newClickHouseError(strings.TrimSpace(string(body)))
+	}
+
+	return newHTTPRows(res.Body)
+}
+
+type httpStmt struct {
+	conn  *httpConn
+	query string
+}
+
+func (s *httpStmt) Close() error {
+	return nil
+}
+
+func (s *httpStmt) NumInput() int {
+	return 0
+}
+
+func (s *httpStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errNotSupported
+}
+
+func (s *httpStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.conn.query(context.Background(), s.query)
+}
+
+// clickHouseError is an error returned by the HTTP interface of ClickHouse,
+// the message contains the code and the exception, like
+// Code: 62, e.displayText() = DB::Exception: Syntax error: ...
+type clickHouseError struct {
+	code    int
+	message string
+}
+
+var errorCodeRegExp = regexp.MustCompile(`^Code: (\d+)`)
+
+func newClickHouseError(message string) *clickHouseError {
+	e := &clickHouseError{message: message}
+	if matches := errorCodeRegExp.FindStringSubmatch(message); len(matches) > 1 {
+		e.code, _ = strconv.Atoi(matches[1])
+	}
+	return e
+}
+
+func (e *clickHouseError) Error() string {
+	return e.message
+}
+
+type httpRows struct {
+	body    io.ReadCloser
+	reader  *bufio.Reader
+	columns []string
+	types   []string
+}
+
+func newHTTPRows(body io.ReadCloser) (*httpRows, error) {
+	r := &httpRows{body: body, reader: bufio.NewReader(body)}
+
+	columns, err := r.readLine()
+	if err == nil {
+		r.columns = columns
+		r.types, err = r.readLine()
+	}
+	if err != nil {
+		body.Close()
+		if err == io.EOF {
+			return nil, errors.New("clickhouse: missing column names and types in response")
+		}
+		return nil, err
+	}
+
+	if len(r.columns) != len(r.types) {
+		body.Close()
+		return nil, errors.New("clickhouse: invalid column names and types in response")
+	}
+
+	for i := range r.columns {
+		r.columns[i] = unescapeTSV(r.columns[i])
+		r.types[i] = unescapeTSV(r.types[i])
+	}
+
+	return r, nil
+}
+
+func (r *httpRows) Columns() []string {
+	return r.columns
+}
+
+func (r *httpRows) ColumnTypeDatabaseTypeName(index int) string {
+	return r.types[index]
+}
+
+func (r *httpRows) Close() error {
+	return r.body.Close()
+}
+
+func (r *httpRows) Next(dest []driver.Value) error {
+	fields, err := r.readLine()
+	if err != nil {
+		return err
+	}
+
+	// exceptions that happen while the result is streamed are written as text to the body
+	if len(fields) != len(r.columns) {
+		return fmt.Errorf("clickhouse: unexpected row in response: %s", strings.Join(fields, "\t"))
+	}
+
+	for i, field := range fields {
+		if field == `\N` {
+			dest[i] = nil
+		} else {
+			dest[i] = unescapeTSV(field)
+		}
+	}
+
+	return nil
+}
+
+// readLine reads a line of the response and splits it into its fields.
+func (r *httpRows) readLine() ([]string, error) {
+	line, err := r.reader.ReadString('\n')
+	if err != nil {
+		if err == io.EOF && line != "" {
+			return strings.Split(line, "\t"), nil
+		}
+		return nil, err
+	}
+
+	return strings.Split(strings.TrimSuffix(line, "\n"), "\t"), nil
+}
+
+var tsvReplacer = strings.NewReplacer(`\\`, `\`, `\t`, "\t", `\n`, "\n", `\r`, "\r", `\0`, "\x00", `\b`, "\b", `\f`, "\f", `\'`, "'")
+
+// unescapeTSV unescapes a field of the TabSeparated format.
+func unescapeTSV(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	return tsvReplacer.Replace(s)
+}
@@ -0,0 +1,148 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/tsdb"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMacroEngine(t *testing.T) {
+	Convey("MacroEngine", t, func() {
+		engine := newClickHouseMacroEngine()
+		query := &tsdb.Query{Model: simplejson.New()}
+
+		Convey("Given a time range between 2018-04-12 00:00 and 2018-04-12 00:05", func() {
+			from := time.Date(2018, 4, 12, 18, 0, 0, 0, time.UTC)
+			to := from.Add(5 * time.Minute)
+			timeRange := tsdb.NewFakeTimeRange("5m", "now", to)
+
+			Convey("interpolate __time function", func() {
+				sql, err := engine.Interpolate(query, timeRange, "select $__time(time_column)")
+				So(err, ShouldBeNil)
+
+				So(sql, ShouldEqual, "select toUnixTimestamp(time_column) AS time_sec")
+			})
+
+			Convey("interpolate __timeGroup function", func() {
+				sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column,'5m')")
+				So(err, ShouldBeNil)
+				sql2, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupAlias(time_column,'5m')")
+				So(err, ShouldBeNil)
+
+				So(sql, ShouldEqual, "GROUP BY toStartOfInterval(time_column, INTERVAL 300 second)")
+				So(sql2, ShouldEqual, sql+" AS \"time\"")
+			})
+
+			Convey("interpolate __timeGroup function with spaces around arguments", func() {
+				sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column , '5m')")
+				So(err, ShouldBeNil)
+
+				So(sql, ShouldEqual, "GROUP BY toStartOfInterval(time_column, INTERVAL 300 second)")
+			})
+
+			Convey("interpolate __timeGroup function with interval below one second", func() {
+				sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column, '100ms')")
+				So(err, ShouldBeNil)
+
+				So(sql, ShouldEqual, "GROUP BY toStartOfInterval(time_column, INTERVAL 1 second)")
+			})
+
+			Convey("interpolate __timeGroup function with fill mode", func() {
+				sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column, '5m', previous)")
+				So(err, ShouldBeNil)
+
+				So(sql, ShouldEqual, "GROUP BY toStartOfInterval(time_column, INTERVAL 300 second)")
+				So(query.Model.Get("fill").MustBool(), ShouldBeTrue)
+				So(query.Model.Get("fillMode").MustString(), ShouldEqual, "previous")
+				So(query.Model.Get("fillInterval").MustFloat64(), ShouldEqual, 300)
+			})
+
+			Convey("interpolate __timeGroup function with invalid interval", func() {
+				_, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column, 'abc')")
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("interpolate __timeFilter function", func() {
+				sql, err := engine.Interpolate(query, timeRange, "WHERE $__timeFilter(time_column)")
+				So(err, ShouldBeNil)
+
+				So(sql, ShouldEqual, fmt.Sprintf("WHERE time_column BETWEEN toDateTime(%d) AND toDateTime(%d)", from.Unix(), to.Unix()))
+			})
+
+			Convey("interpolate __timeFrom function", func() {
+				sql, err := engine.Interpolate(query, timeRange, "select $__timeFrom()")
+				So(err, ShouldBeNil)
+
+				So(sql, ShouldEqual, fmt.Sprintf("select toDateTime(%d)", from.Unix()))
+			})
+
+			Convey("interpolate __timeTo function", func() {
+				sql, err := engine.Interpolate(query, timeRange, "select $__timeTo()")
+				So(err, ShouldBeNil)
+
+				So(sql, ShouldEqual, fmt.Sprintf("select toDateTime(%d)", to.Unix()))
+			})
+
+			Convey("interpolate __unixEpochFilter function", func() {
+				sql, err := engine.Interpolate(query, timeRange, "select $__unixEpochFilter(time)")
+				So(err, ShouldBeNil)
+
+				So(sql, ShouldEqual, fmt.Sprintf("select time >= %d AND time <= %d", from.Unix(), to.Unix()))
+			})
+
+			Convey("interpolate __unixEpochFrom function", func() {
+				sql, err := engine.Interpolate(query, timeRange, "select $__unixEpochFrom()")
+				So(err, ShouldBeNil)
+
+				So(sql, ShouldEqual, fmt.Sprintf("select %d", from.Unix()))
+			})
+
+			Convey("interpolate __unixEpochNanoFilter function", func() {
+				sql, err := engine.Interpolate(query, timeRange, "select $__unixEpochNanoFilter(time)")
+				So(err, ShouldBeNil)
+
+				So(sql, ShouldEqual, fmt.Sprintf("select time >= %d AND time <= %d", from.UnixNano(), to.UnixNano()))
+			})
+
+			Convey("interpolate __unixEpochGroup function", func() {
+				sql, err := engine.Interpolate(query, timeRange, "SELECT $__unixEpochGroup(time_column,'5m')")
+				So(err, ShouldBeNil)
+				sql2, err := engine.Interpolate(query, timeRange, "SELECT $__unixEpochGroupAlias(time_column,'5m')")
+				So(err, ShouldBeNil)
+
+				So(sql, ShouldEqual, "SELECT intDiv(time_column, 300) * 300")
+				So(sql2, ShouldEqual, sql+" AS \"time\"")
+			})
+
+			Convey("interpolate unknown macro", func() {
+				_, err := engine.Interpolate(query, timeRange, "select $__unknown(time)")
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("Given a time range between 1960-02-01 07:00 and 1965-02-03 08:00", func() {
+			from := time.Date(1960, 2, 1, 7, 0, 0, 0, time.UTC)
+			to := time.Date(1965, 2, 3, 8, 0, 0, 0, time.UTC)
+			timeRange := tsdb.NewTimeRange(strconv.FormatInt(from.UnixNano()/int64(time.Millisecond), 10), strconv.FormatInt(to.UnixNano()/int64(time.Millisecond), 10))
+
+			Convey("interpolate __timeFilter function", func() {
+				sql, err := engine.Interpolate(query, timeRange, "WHERE $__timeFilter(time_column)")
+				So(err, ShouldBeNil)
+
+				So(sql, ShouldEqual, fmt.Sprintf("WHERE time_column BETWEEN toDateTime(%d) AND toDateTime(%d)", from.Unix(), to.Unix()))
+			})
+
+			Convey("interpolate __unixEpochFilter function", func() {
+				sql, err := engine.Interpolate(query, timeRange, "select $__unixEpochFilter(time)")
+				So(err, ShouldBeNil)
+
+				So(sql, ShouldEqual, fmt.Sprintf("select time >= %d AND time <= %d", from.Unix(), to.Unix()))
+			})
+		})
+	})
+}
@@ -65,7 +65,23 @@ func (r *SearchRequest) MarshalJSON() ([]byte, error) {
 
 // SearchResponseHits represents search response hits
 type SearchResponseHits struct {
-	Hits []map[string]interface{}
+	Hits  []map[string]interface{}
+	Total interface{} `json:"total"`
+}
+
+// GetTotal returns the total number of hits matching the search. Elasticsearch 7.0+
+// returns the total as an object, ex {"value": 10, "relation": "eq"}.
+func (h *SearchResponseHits) GetTotal() int64 {
+	switch total := h.Total.(type) {
+	case float64:
+		return int64(total)
+	case map[string]interface{}:
+		if value, ok := total["value"].(float64); ok {
+			return int64(value)
+		}
+	}
+
+	return 0
 }
 
 // SearchResponse represents a search response
@@ -150,6 +166,13 @@ type RangeFilter struct {
 // DateFormatEpochMS represents a date format of epoch milliseconds (epoch_millis)
 const DateFormatEpochMS = "epoch_millis"
 
+const (
+	// SortOrderAsc sorts documents in ascending order
+	SortOrderAsc = "asc"
+	// SortOrderDesc sorts documents in descending order
+	SortOrderDesc = "desc"
+)
+
 // MarshalJSON returns the JSON encoding of the query string filter.
 func (f *RangeFilter) MarshalJSON() ([]byte, error) {
 	root := map[string]map[string]map[string]interface{}{
@@ -266,6 +289,65 @@ type TermsAggregation struct {
 	Missing     *string                `json:"missing,omitempty"`
 }
 
+// DateRangeAggregation represents a date range aggregation
+type DateRangeAggregation struct {
+	Field  string      `json:"field"`
+	Format string      `json:"format,omitempty"`
+	Ranges []DateRange `json:"ranges"`
+}
+
+// DateRange represents a range of a date range aggregation, from and to
+// can be dates or date math expressions
+type DateRange struct {
+	Key  string `json:"key,omitempty"`
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// CompositeAggregation represents a composite aggregation
+type CompositeAggregation struct {
+	Size    int                `json:"size,omitempty"`
+	Sources []*CompositeSource `json:"sources"`
+}
+
+// CompositeSource represents a values source of a composite aggregation
+type CompositeSource struct {
+	Name     string
+	Type     string
+	Field    string
+	Interval string
+	Order    string
+}
+
+// MarshalJSON returns the JSON encoding of the composite source
+func (s *CompositeSource) MarshalJSON() ([]byte, error) {
+	source := map[string]interface{}{
+		"field": s.Field,
+	}
+
+	if s.Interval != "" {
+		source["interval"] = s.Interval
+	}
+
+	if s.Order != "" {
+		source["order"] = s.Order
+	}
+
+	root := map[string]interface{}{
+		s.Name: map[string]interface{}{
+			s.Type: source,
+		},
+	}
+
+	return json.Marshal(root)
+}
+
+// BucketSortAggregation represents a bucket sort pipeline aggregation
+type BucketSortAggregation struct {
+	Sort []map[string]interface{} `json:"sort"`
+	Size int                      `json:"size,omitempty"`
+}
+
 // ExtendedBounds represents extended bounds
 type ExtendedBounds struct {
 	Min string `json:"min"`
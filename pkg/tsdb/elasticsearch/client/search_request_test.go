@@ -387,6 +387,48 @@ func TestSearchRequest(t *testing.T) {
 					})
 				})
 			})
+			Convey("and adding composite agg with date range child agg and bucket sort", func() {
+				aggBuilder := b.Agg()
+				aggBuilder.Composite("2", func(a *CompositeAggregation, ib AggBuilder) {
+					a.Size = 100
+					a.Sources = append(a.Sources, &CompositeSource{Name: "host", Type: "terms", Field: "@host"})
+					ib.DateRange("3", "@timestamp", func(a *DateRangeAggregation, ib AggBuilder) {
+						a.Ranges = append(a.Ranges, DateRange{Key: "today", From: "now/d"})
+					})
+					ib.BucketSort("bucket_sort", func(a *BucketSortAggregation) {
+						a.Sort = append(a.Sort, map[string]interface{}{"4": map[string]string{"order": "desc"}})
+						a.Size = 10
+					})
+				})
+
+				Convey("When building search request", func() {
+					sr, err := b.Build()
+					So(err, ShouldBeNil)
+
+					Convey("When marshal to JSON should generate correct json", func() {
+						body, err := json.Marshal(sr)
+						So(err, ShouldBeNil)
+						json, err := simplejson.NewJson(body)
+						So(err, ShouldBeNil)
+
+						compositeAgg := json.GetPath("aggs", "2")
+						So(compositeAgg.GetPath("composite", "size").MustInt(), ShouldEqual, 100)
+						sources := compositeAgg.GetPath("composite", "sources").MustArray()
+						So(sources, ShouldHaveLength, 1)
+						So(simplejson.NewFromAny(sources[0]).GetPath("host", "terms", "field").MustString(), ShouldEqual, "@host")
+
+						dateRangeAgg := compositeAgg.GetPath("aggs", "3", "date_range")
+						So(dateRangeAgg.Get("field").MustString(), ShouldEqual, "@timestamp")
+						ranges := dateRangeAgg.Get("ranges").MustArray()
+						So(ranges, ShouldHaveLength, 1)
+						So(ranges[0], ShouldResemble, map[string]interface{}{"key": "today", "from": "now/d"})
+
+						bucketSortAgg := compositeAgg.GetPath("aggs", "bucket_sort", "bucket_sort")
+						So(bucketSortAgg.Get("size").MustInt(), ShouldEqual, 10)
+						So(bucketSortAgg.Get("sort").GetIndex(0).GetPath("4", "order").MustString(), ShouldEqual, "desc")
+					})
+				})
+			})
 		})
 
 		Convey("Given new search request builder for es version 2", func() {
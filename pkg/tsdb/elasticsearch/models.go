@@ -47,7 +47,11 @@ var metricAggType = map[string]string{
 	"moving_avg":     "Moving Average",
 	"derivative":     "Derivative",
 	"bucket_script":  "Bucket Script",
+	"cumulative_sum": "Cumulative Sum",
+	"serial_diff":    "Serial Difference",
 	"raw_document":   "Raw Document",
+	"raw_data":       "Raw Data",
+	"logs":           "Logs",
 }
 
 var extendedStats = map[string]string{
@@ -62,15 +66,37 @@ var extendedStats = map[string]string{
 }
 
 var pipelineAggType = map[string]string{
-	"moving_avg":    "moving_avg",
-	"derivative":    "derivative",
-	"bucket_script": "bucket_script",
+	"moving_avg":     "moving_avg",
+	"derivative":     "derivative",
+	"bucket_script":  "bucket_script",
+	"cumulative_sum": "cumulative_sum",
+	"serial_diff":    "serial_diff",
 }
 
 var pipelineAggWithMultipleBucketPathsType = map[string]string{
 	"bucket_script": "bucket_script",
 }
 
+var documentMetricType = map[string]string{
+	"raw_document": "raw_document",
+	"raw_data":     "raw_data",
+	"logs":         "logs",
+}
+
+// isDocumentMetric returns true for metrics returning the documents of the query
+func isDocumentMetric(metricType string) bool {
+	if _, ok := documentMetricType[metricType]; ok {
+		return true
+	}
+	return false
+}
+
+// isDocumentQuery returns true if the query returns documents, which
+// is the case when its first metric is a document metric.
+func isDocumentQuery(q *Query) bool {
+	return len(q.Metrics) > 0 && isDocumentMetric(q.Metrics[0].Type)
+}
+
 func isPipelineAgg(metricType string) bool {
 	if _, ok := pipelineAggType[metricType]; ok {
 		return true
@@ -85,9 +111,11 @@ func isPipelineAggWithMultipleBucketPaths(metricType string) bool {
 	return false
 }
 
+// describeMetric returns the display name of a metric, metrics not
+// requiring a field are described by the name of their type only.
 func describeMetric(metricType, field string) string {
 	text := metricAggType[metricType]
-	if metricType == countType {
+	if metricType == countType || isDocumentMetric(metricType) {
 		return text
 	}
 	return text + " " + field
@@ -1,12 +1,15 @@
 package elasticsearch
 
 import (
+	"encoding/json"
 	"errors"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/grafana/grafana/pkg/components/null"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/tsdb"
@@ -18,12 +21,17 @@ const (
 	countType         = "count"
 	percentilesType   = "percentiles"
 	extendedStatsType = "extended_stats"
+	rawDocumentType   = "raw_document"
+	rawDataType       = "raw_data"
+	logsType          = "logs"
 	// Bucket types
 	dateHistType    = "date_histogram"
 	histogramType   = "histogram"
 	filtersType     = "filters"
 	termsType       = "terms"
 	geohashGridType = "geohash_grid"
+	dateRangeType   = "date_range"
+	compositeType   = "composite"
 )
 
 type responseParser struct {
@@ -69,6 +77,18 @@ func (rp *responseParser) getTimeSeries() (*tsdb.Response, error) {
 			Columns: make([]tsdb.TableColumn, 0),
 			Rows:    make([]tsdb.RowValues, 0),
 		}
+		if isDocumentQuery(target) && res.Hits != nil {
+			frame, err := rp.processHits(res.Hits, target)
+			if err != nil {
+				return nil, err
+			}
+			encoded, err := frame.MarshalArrow()
+			if err != nil {
+				return nil, err
+			}
+			queryRes.Dataframes = append(queryRes.Dataframes, encoded)
+		}
+
 		err := rp.processBuckets(res.Aggregations, target, &queryRes.Series, &table, props, 0)
 		if err != nil {
 			return nil, err
@@ -120,14 +140,20 @@ func (rp *responseParser) processBuckets(aggs map[string]interface{}, target *Qu
 					newProps[k] = v
 				}
 
-				if key, err := bucket.Get("key").String(); err == nil {
-					newProps[aggDef.Field] = key
-				} else if key, err := bucket.Get("key").Int64(); err == nil {
-					newProps[aggDef.Field] = strconv.FormatInt(key, 10)
-				}
+				if aggDef.Type == compositeType {
+					for _, name := range getCompositeSourceNames(aggDef) {
+						newProps[name] = bucketKeyToString(bucket.GetPath("key", name))
+					}
+				} else {
+					if key, err := bucket.Get("key").String(); err == nil {
+						newProps[aggDef.Field] = key
+					} else if key, err := bucket.Get("key").Int64(); err == nil {
+						newProps[aggDef.Field] = strconv.FormatInt(key, 10)
+					}
 
-				if key, err := bucket.Get("key_as_string").String(); err == nil {
-					newProps[aggDef.Field] = key
+					if key, err := bucket.Get("key_as_string").String(); err == nil {
+						newProps[aggDef.Field] = key
+					}
 				}
 				err = rp.processBuckets(bucket.MustMap(), target, series, table, newProps, depth+1)
 				if err != nil {
@@ -171,7 +197,7 @@ func (rp *responseParser) processMetrics(esAgg *simplejson.Json, target *Query,
 		}
 
 		switch metric.Type {
-		case countType:
+		case countType, logsType:
 			newSeries := tsdb.TimeSeries{
 				Tags: make(map[string]string),
 			}
@@ -298,11 +324,18 @@ func (rp *responseParser) processAggregationDocs(esAgg *simplejson.Json, aggDef
 	}
 	sort.Strings(propKeys)
 
+	keyNames := []string{aggDef.Field}
+	if aggDef.Type == compositeType {
+		keyNames = getCompositeSourceNames(aggDef)
+	}
+
 	if len(table.Columns) == 0 {
 		for _, propKey := range propKeys {
 			table.Columns = append(table.Columns, tsdb.TableColumn{Text: propKey})
 		}
-		table.Columns = append(table.Columns, tsdb.TableColumn{Text: aggDef.Field})
+		for _, keyName := range keyNames {
+			table.Columns = append(table.Columns, tsdb.TableColumn{Text: keyName})
+		}
 	}
 
 	addMetricValue := func(values *tsdb.RowValues, metricName string, value null.Float) {
@@ -327,16 +360,29 @@ func (rp *responseParser) processAggregationDocs(esAgg *simplejson.Json, aggDef
 			values = append(values, props[propKey])
 		}
 
-		if key, err := bucket.Get("key").String(); err == nil {
-			values = append(values, key)
+		if aggDef.Type == compositeType {
+			for _, keyName := range keyNames {
+				values = append(values, getBucketKeyValue(bucket.GetPath("key", keyName)))
+			}
 		} else {
-			values = append(values, castToNullFloat(bucket.Get("key")))
+			values = append(values, getBucketKeyValue(bucket.Get("key")))
 		}
 
 		for _, metric := range target.Metrics {
 			switch metric.Type {
 			case countType:
 				addMetricValue(&values, rp.getMetricName(metric.Type), castToNullFloat(bucket.Get("doc_count")))
+			case percentilesType:
+				percentiles := bucket.GetPath(metric.ID, "values").MustMap()
+				percentileKeys := make([]string, 0)
+				for k := range percentiles {
+					percentileKeys = append(percentileKeys, k)
+				}
+				sort.Strings(percentileKeys)
+				for _, percentileName := range percentileKeys {
+					value := castToNullFloat(bucket.GetPath(metric.ID, "values", percentileName))
+					addMetricValue(&values, "p"+percentileName+" "+metric.Field, value)
+				}
 			case extendedStatsType:
 				metaKeys := make([]string, 0)
 				meta := metric.Meta.MustMap()
@@ -359,8 +405,7 @@ func (rp *responseParser) processAggregationDocs(esAgg *simplejson.Json, aggDef
 						value = castToNullFloat(bucket.GetPath(metric.ID, statName))
 					}
 
-					addMetricValue(&values, rp.getMetricName(metric.Type), value)
-					break
+					addMetricValue(&values, rp.getMetricName(statName), value)
 				}
 			default:
 				metricName := rp.getMetricName(metric.Type)
@@ -478,6 +523,10 @@ func (rp *responseParser) getSeriesName(series *tsdb.TimeSeries, target *Query,
 			for _, metric := range target.Metrics {
 				if metric.ID == metricID {
 					metricName = metric.Settings.Get("script").MustString()
+					if metricName == "" {
+						metricName = "Unset"
+						break
+					}
 					for name, pipelineAgg := range metric.PipelineVariables {
 						for _, m := range target.Metrics {
 							if m.ID == pipelineAgg {
@@ -491,7 +540,7 @@ func (rp *responseParser) getSeriesName(series *tsdb.TimeSeries, target *Query,
 			found := false
 			for _, metric := range target.Metrics {
 				if metric.ID == field {
-					metricName += " " + describeMetric(metric.Type, field)
+					metricName += " " + describeMetric(metric.Type, metric.Field)
 					found = true
 				}
 			}
@@ -510,8 +559,8 @@ func (rp *responseParser) getSeriesName(series *tsdb.TimeSeries, target *Query,
 	}
 
 	name := ""
-	for _, v := range series.Tags {
-		name += v + " "
+	for _, k := range getPropKeys(series.Tags, target) {
+		name += series.Tags[k] + " "
 	}
 
 	if metricTypeCount == 1 {
@@ -534,6 +583,72 @@ func (rp *responseParser) getMetricName(metric string) string {
 	return metric
 }
 
+// getPropKeys returns the keys of the props of a series in the order of the bucket
+// aggregations which created them, like the frontend names series.
+func getPropKeys(props map[string]string, target *Query) []string {
+	keys := make([]string, 0, len(props))
+	seen := make(map[string]bool)
+	addKey := func(k string) {
+		if _, ok := props[k]; ok && !seen[k] {
+			keys = append(keys, k)
+			seen[k] = true
+		}
+	}
+
+	for _, bucketAgg := range target.BucketAggs {
+		switch bucketAgg.Type {
+		case filtersType:
+			addKey("filter")
+		case compositeType:
+			for _, name := range getCompositeSourceNames(bucketAgg) {
+				addKey(name)
+			}
+		default:
+			addKey(bucketAgg.Field)
+		}
+	}
+
+	remaining := make([]string, 0)
+	for k := range props {
+		if !seen[k] {
+			remaining = append(remaining, k)
+		}
+	}
+	sort.Strings(remaining)
+
+	return append(keys, remaining...)
+}
+
+// getCompositeSourceNames returns the names of the values sources of a composite
+// aggregation, which are the keys of its bucket keys.
+func getCompositeSourceNames(bucketAgg *BucketAgg) []string {
+	names := make([]string, 0)
+	for _, source := range getCompositeSources(bucketAgg) {
+		names = append(names, source.Get("name").MustString())
+	}
+	return names
+}
+
+func getBucketKeyValue(key *simplejson.Json) interface{} {
+	if s, err := key.String(); err == nil {
+		return s
+	}
+	return castToNullFloat(key)
+}
+
+func bucketKeyToString(key *simplejson.Json) string {
+	if s, err := key.String(); err == nil {
+		return s
+	}
+	if i, err := key.Int64(); err == nil {
+		return strconv.FormatInt(i, 10)
+	}
+	if f, err := key.Float64(); err == nil {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return ""
+}
+
 func castToNullFloat(j *simplejson.Json) null.Float {
 	f, err := j.Float64()
 	if err == nil {
@@ -578,3 +693,160 @@ func getErrorFromElasticResponse(response *es.SearchResponse) *tsdb.QueryResult
 
 	return result
 }
+
+// processHits converts the documents of raw_document, raw_data and logs queries into a
+// data frame with a field for every property of the documents. The properties of nested
+// objects of the _source are flattened into fields named `parent.child`, raw_document
+// queries return the whole _source as a JSON field instead.
+func (rp *responseParser) processHits(hits *es.SearchResponseHits, target *Query) (*data.Frame, error) {
+	metricType := target.Metrics[0].Type
+	docs := make([]map[string]interface{}, 0, len(hits.Hits))
+	propNames := make([]string, 0)
+	seen := map[string]bool{}
+
+	for _, hit := range hits.Hits {
+		doc := map[string]interface{}{
+			"_id":    hit["_id"],
+			"_type":  hit["_type"],
+			"_index": hit["_index"],
+		}
+
+		source, _ := hit["_source"].(map[string]interface{})
+		if metricType == rawDocumentType {
+			encoded, err := json.Marshal(source)
+			if err != nil {
+				return nil, err
+			}
+			doc["_source"] = string(encoded)
+		} else {
+			flattenDocument(doc, "", source)
+		}
+
+		// doc values of the time field are returned as single value arrays
+		if fields, ok := hit["fields"].(map[string]interface{}); ok {
+			for k, v := range fields {
+				if values, ok := v.([]interface{}); ok && len(values) == 1 {
+					v = values[0]
+				}
+				doc[k] = v
+			}
+		}
+
+		for k := range doc {
+			if !seen[k] {
+				seen[k] = true
+				propNames = append(propNames, k)
+			}
+		}
+		docs = append(docs, doc)
+	}
+	sort.Strings(propNames)
+
+	frame := data.NewFrame(metricType)
+	if seen[target.TimeField] {
+		frame.Fields = append(frame.Fields, newDocumentTimeField(target.TimeField, docs))
+	}
+
+	for _, name := range propNames {
+		if name == target.TimeField {
+			continue
+		}
+		frame.Fields = append(frame.Fields, newDocumentField(name, docs))
+	}
+
+	frame.Meta = &data.FrameMeta{
+		Custom: map[string]interface{}{
+			"total": hits.GetTotal(),
+		},
+	}
+	if metricType == logsType {
+		frame.Meta.Custom["preferredVisualisationType"] = "logs"
+	}
+
+	return frame, nil
+}
+
+// flattenDocument adds the properties of nested objects to the document with their path as name.
+func flattenDocument(doc map[string]interface{}, prefix string, source map[string]interface{}) {
+	for k, v := range source {
+		if nested, ok := v.(map[string]interface{}); ok {
+			flattenDocument(doc, prefix+k+".", nested)
+			continue
+		}
+		doc[prefix+k] = v
+	}
+}
+
+// newDocumentTimeField parses the time field of documents, which can be
+// a date string or a number of milliseconds since the epoch.
+func newDocumentTimeField(name string, docs []map[string]interface{}) *data.Field {
+	values := make([]*time.Time, len(docs))
+	for i, doc := range docs {
+		switch v := doc[name].(type) {
+		case string:
+			if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+				values[i] = &t
+			} else if ms, err := strconv.ParseInt(v, 10, 64); err == nil {
+				t := time.Unix(0, ms*int64(time.Millisecond)).UTC()
+				values[i] = &t
+			}
+		case float64:
+			t := time.Unix(0, int64(v)*int64(time.Millisecond)).UTC()
+			values[i] = &t
+		}
+	}
+
+	return data.NewField(name, nil, values)
+}
+
+// newDocumentField returns a number or boolean field if all values of the
+// property have that type, other values are converted to strings.
+func newDocumentField(name string, docs []map[string]interface{}) *data.Field {
+	isNumber, isBool := true, true
+	for _, doc := range docs {
+		switch doc[name].(type) {
+		case nil:
+		case float64:
+			isBool = false
+		case bool:
+			isNumber = false
+		default:
+			isNumber, isBool = false, false
+		}
+	}
+
+	switch {
+	case isNumber:
+		values := make([]*float64, len(docs))
+		for i, doc := range docs {
+			if v, ok := doc[name].(float64); ok {
+				values[i] = &v
+			}
+		}
+		return data.NewField(name, nil, values)
+	case isBool:
+		values := make([]*bool, len(docs))
+		for i, doc := range docs {
+			if v, ok := doc[name].(bool); ok {
+				values[i] = &v
+			}
+		}
+		return data.NewField(name, nil, values)
+	}
+
+	values := make([]*string, len(docs))
+	for i, doc := range docs {
+		switch v := doc[name].(type) {
+		case nil:
+		case string:
+			values[i] = &v
+		default:
+			encoded, err := json.Marshal(v)
+			if err == nil {
+				text := string(encoded)
+				values[i] = &text
+			}
+		}
+	}
+	return data.NewField(name, nil, values)
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/grafana/grafana/pkg/components/null"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
@@ -865,58 +866,431 @@ func TestResponseParser(t *testing.T) {
 			So(seriesThree.Points[1][1].Float64, ShouldEqual, 2000)
 		})
 
-		// Convey("Raw documents query", func() {
-		// 	targets := map[string]string{
-		// 		"A": `{
-		// 			"timeField": "@timestamp",
-		// 			"metrics": [{ "type": "raw_document", "id": "1" }]
-		// 		}`,
-		// 	}
-		// 	response := `{
-		//     "responses": [
-		//       {
-		//         "hits": {
-		//           "total": 100,
-		//           "hits": [
-		//             {
-		//               "_id": "1",
-		//               "_type": "type",
-		//               "_index": "index",
-		//               "_source": { "sourceProp": "asd" },
-		//               "fields": { "fieldProp": "field" }
-		//             },
-		//             {
-		//               "_source": { "sourceProp": "asd2" },
-		//               "fields": { "fieldProp": "field2" }
-		//             }
-		//           ]
-		//         }
-		//       }
-		//     ]
-		// 	}`
-		// 	rp, err := newResponseParserForTest(targets, response)
-		// 	So(err, ShouldBeNil)
-		// 	result, err := rp.getTimeSeries()
-		// 	So(err, ShouldBeNil)
-		// 	So(result.Results, ShouldHaveLength, 1)
-
-		// 	queryRes := result.Results["A"]
-		// 	So(queryRes, ShouldNotBeNil)
-		// 	So(queryRes.Tables, ShouldHaveLength, 1)
-
-		// 	rows := queryRes.Tables[0].Rows
-		// 	So(rows, ShouldHaveLength, 1)
-		// 	cols := queryRes.Tables[0].Columns
-		// 	So(cols, ShouldHaveLength, 3)
-
-		// 	So(cols[0].Text, ShouldEqual, "host")
-		// 	So(cols[1].Text, ShouldEqual, "Average test")
-		// 	So(cols[2].Text, ShouldEqual, "Average test2")
-
-		// 	So(rows[0][0].(string), ShouldEqual, "server-1")
-		// 	So(rows[0][1].(null.Float).Float64, ShouldEqual, 1000)
-		// 	So(rows[0][2].(null.Float).Float64, ShouldEqual, 3000)
-		// })
+		Convey("With cumulative sum", func() {
+			targets := map[string]string{
+				"A": `{
+					"timeField": "@timestamp",
+					"metrics": [
+						{ "id": "1", "type": "sum", "field": "@value" },
+						{ "id": "3", "type": "cumulative_sum", "field": "1", "pipelineAgg": "1" }
+					],
+					"bucketAggs": [{ "type": "date_histogram", "field": "@timestamp", "id": "2" }]
+				}`,
+			}
+			response := `{
+				"responses": [
+					{
+						"aggregations": {
+							"2": {
+								"buckets": [
+									{ "1": { "value": 2 }, "3": { "value": 2 }, "doc_count": 60, "key": 1000 },
+									{ "1": { "value": 3 }, "3": { "value": 5 }, "doc_count": 60, "key": 2000 }
+								]
+							}
+						}
+					}
+				]
+			}`
+			rp, err := newResponseParserForTest(targets, response)
+			So(err, ShouldBeNil)
+			result, err := rp.getTimeSeries()
+			So(err, ShouldBeNil)
+
+			queryRes := result.Results["A"]
+			So(queryRes.Series, ShouldHaveLength, 2)
+			So(queryRes.Series[0].Name, ShouldEqual, "Sum @value")
+
+			seriesTwo := queryRes.Series[1]
+			So(seriesTwo.Name, ShouldEqual, "Cumulative Sum Sum @value")
+			So(seriesTwo.Points, ShouldHaveLength, 2)
+			So(seriesTwo.Points[0][0].Float64, ShouldEqual, 2)
+			So(seriesTwo.Points[1][0].Float64, ShouldEqual, 5)
+		})
+
+		Convey("With bucket_script without script", func() {
+			targets := map[string]string{
+				"A": `{
+					"timeField": "@timestamp",
+					"metrics": [
+						{ "id": "1", "type": "sum", "field": "@value" },
+						{
+							"id": "4",
+							"field": "select field",
+							"pipelineVariables": [{ "name": "var1", "pipelineAgg": "1" }],
+							"type": "bucket_script"
+						}
+					],
+					"bucketAggs": [{ "type": "date_histogram", "field": "@timestamp", "id": "2" }]
+				}`,
+			}
+			response := `{
+				"responses": [
+					{
+						"aggregations": {
+							"2": {
+								"buckets": [{ "1": { "value": 2 }, "4": { "value": 2 }, "doc_count": 60, "key": 1000 }]
+							}
+						}
+					}
+				]
+			}`
+			rp, err := newResponseParserForTest(targets, response)
+			So(err, ShouldBeNil)
+			result, err := rp.getTimeSeries()
+			So(err, ShouldBeNil)
+
+			queryRes := result.Results["A"]
+			So(queryRes.Series, ShouldHaveLength, 2)
+			So(queryRes.Series[1].Name, ShouldEqual, "Unset")
+		})
+
+		Convey("With composite and date histogram aggs", func() {
+			targets := map[string]string{
+				"A": `{
+					"timeField": "@timestamp",
+					"metrics": [{ "type": "count", "id": "1" }],
+					"bucketAggs": [
+						{
+							"type": "composite",
+							"id": "2",
+							"settings": {
+								"sources": [{ "name": "host", "field": "@host" }, { "name": "dc", "field": "@dc" }]
+							}
+						},
+						{ "type": "date_histogram", "field": "@timestamp", "id": "3" }
+					]
+				}`,
+			}
+			response := `{
+				"responses": [
+					{
+						"aggregations": {
+							"2": {
+								"after_key": { "host": "server-2", "dc": "dc-1" },
+								"buckets": [
+									{
+										"3": { "buckets": [{ "doc_count": 1, "key": 1000 }] },
+										"doc_count": 1,
+										"key": { "host": "server-1", "dc": "dc-2" }
+									},
+									{
+										"3": { "buckets": [{ "doc_count": 4, "key": 1000 }] },
+										"doc_count": 4,
+										"key": { "host": "server-2", "dc": "dc-1" }
+									}
+								]
+							}
+						}
+					}
+				]
+			}`
+			rp, err := newResponseParserForTest(targets, response)
+			So(err, ShouldBeNil)
+			result, err := rp.getTimeSeries()
+			So(err, ShouldBeNil)
+
+			queryRes := result.Results["A"]
+			So(queryRes.Series, ShouldHaveLength, 2)
+			So(queryRes.Series[0].Name, ShouldEqual, "server-1 dc-2")
+			So(queryRes.Series[0].Points[0][0].Float64, ShouldEqual, 1)
+			So(queryRes.Series[1].Name, ShouldEqual, "server-2 dc-1")
+			So(queryRes.Series[1].Points[0][0].Float64, ShouldEqual, 4)
+		})
+
+		Convey("With composite agg and no group by time", func() {
+			targets := map[string]string{
+				"A": `{
+					"timeField": "@timestamp",
+					"metrics": [{ "type": "count", "id": "1" }],
+					"bucketAggs": [
+						{
+							"type": "composite",
+							"id": "2",
+							"settings": {
+								"sources": [{ "name": "host", "field": "@host" }, { "name": "code", "field": "@code" }]
+							}
+						}
+					]
+				}`,
+			}
+			response := `{
+				"responses": [
+					{
+						"aggregations": {
+							"2": {
+								"buckets": [
+									{ "doc_count": 10, "key": { "host": "server-1", "code": 200 } },
+									{ "doc_count": 2, "key": { "host": "server-1", "code": 500 } }
+								]
+							}
+						}
+					}
+				]
+			}`
+			rp, err := newResponseParserForTest(targets, response)
+			So(err, ShouldBeNil)
+			result, err := rp.getTimeSeries()
+			So(err, ShouldBeNil)
+
+			queryRes := result.Results["A"]
+			So(queryRes.Tables, ShouldHaveLength, 1)
+
+			cols := queryRes.Tables[0].Columns
+			So(cols, ShouldHaveLength, 3)
+			So(cols[0].Text, ShouldEqual, "host")
+			So(cols[1].Text, ShouldEqual, "code")
+			So(cols[2].Text, ShouldEqual, "Count")
+
+			rows := queryRes.Tables[0].Rows
+			So(rows, ShouldHaveLength, 2)
+			So(rows[0][0].(string), ShouldEqual, "server-1")
+			So(rows[0][1].(null.Float).Float64, ShouldEqual, 200)
+			So(rows[0][2].(null.Float).Float64, ShouldEqual, 10)
+			So(rows[1][1].(null.Float).Float64, ShouldEqual, 500)
+			So(rows[1][2].(null.Float).Float64, ShouldEqual, 2)
+		})
+
+		Convey("With date range agg, percentiles and extended stats and no group by time", func() {
+			targets := map[string]string{
+				"A": `{
+					"timeField": "@timestamp",
+					"metrics": [
+						{ "type": "percentiles", "field": "value", "settings": { "percents": ["75", "90"] }, "id": "1" },
+						{ "type": "extended_stats", "field": "value", "meta": { "max": true, "std_deviation": true }, "id": "3" }
+					],
+					"bucketAggs": [
+						{
+							"type": "date_range",
+							"field": "@timestamp",
+							"id": "2",
+							"settings": { "ranges": [{ "to": "now-1d/d", "key": "older" }, { "from": "now-1d/d", "key": "recent" }] }
+						}
+					]
+				}`,
+			}
+			response := `{
+				"responses": [
+					{
+						"aggregations": {
+							"2": {
+								"buckets": [
+									{
+										"1": { "values": { "75": 3.3, "90": 5.5 } },
+										"3": { "max": 10.2, "std_deviation": 1.5, "std_deviation_bounds": { "upper": 4, "lower": -2 } },
+										"doc_count": 10,
+										"key": "older",
+										"to": 1000
+									},
+									{
+										"1": { "values": { "75": 2.3, "90": 4.5 } },
+										"3": { "max": 8.1, "std_deviation": 0.5, "std_deviation_bounds": { "upper": 3, "lower": -1 } },
+										"doc_count": 15,
+										"key": "recent",
+										"from": 1000
+									}
+								]
+							}
+						}
+					}
+				]
+			}`
+			rp, err := newResponseParserForTest(targets, response)
+			So(err, ShouldBeNil)
+			result, err := rp.getTimeSeries()
+			So(err, ShouldBeNil)
+
+			queryRes := result.Results["A"]
+			So(queryRes.Tables, ShouldHaveLength, 1)
+
+			cols := queryRes.Tables[0].Columns
+			So(cols, ShouldHaveLength, 5)
+			So(cols[0].Text, ShouldEqual, "@timestamp")
+			So(cols[1].Text, ShouldEqual, "p75 value")
+			So(cols[2].Text, ShouldEqual, "p90 value")
+			So(cols[3].Text, ShouldEqual, "Max")
+			So(cols[4].Text, ShouldEqual, "Std Dev")
+
+			rows := queryRes.Tables[0].Rows
+			So(rows, ShouldHaveLength, 2)
+			So(rows[0][0].(string), ShouldEqual, "older")
+			So(rows[0][1].(null.Float).Float64, ShouldEqual, 3.3)
+			So(rows[0][2].(null.Float).Float64, ShouldEqual, 5.5)
+			So(rows[0][3].(null.Float).Float64, ShouldEqual, 10.2)
+			So(rows[0][4].(null.Float).Float64, ShouldEqual, 1.5)
+			So(rows[1][0].(string), ShouldEqual, "recent")
+			So(rows[1][1].(null.Float).Float64, ShouldEqual, 2.3)
+		})
+
+		Convey("Raw documents query", func() {
+			targets := map[string]string{
+				"A": `{
+					"timeField": "@timestamp",
+					"metrics": [{ "type": "raw_document", "id": "1" }]
+				}`,
+			}
+			response := `{
+        "responses": [
+          {
+            "hits": {
+              "total": 100,
+              "hits": [
+                {
+                  "_id": "1",
+                  "_type": "type",
+                  "_index": "index",
+                  "_source": { "sourceProp": "asd", "nested": { "prop": 1 } },
+                  "fields": { "@timestamp": ["2018-05-15T17:54:00.000Z"] }
+                },
+                {
+                  "_id": "2",
+                  "_type": "type",
+                  "_index": "index",
+                  "_source": { "sourceProp": "asd2" },
+                  "fields": { "@timestamp": ["2018-05-15T17:53:00.000Z"] }
+                }
+              ]
+            }
+          }
+        ]
+			}`
+			rp, err := newResponseParserForTest(targets, response)
+			So(err, ShouldBeNil)
+			result, err := rp.getTimeSeries()
+			So(err, ShouldBeNil)
+			So(result.Results, ShouldHaveLength, 1)
+
+			queryRes := result.Results["A"]
+			So(queryRes, ShouldNotBeNil)
+			So(queryRes.Series, ShouldBeEmpty)
+			So(queryRes.Dataframes, ShouldHaveLength, 1)
+
+			frames, err := data.UnmarshalArrowFrames(queryRes.Dataframes)
+			So(err, ShouldBeNil)
+			frame := frames[0]
+			So(frame.Name, ShouldEqual, "raw_document")
+			So(frame.Meta.Custom["total"], ShouldEqual, 100)
+			So(frameFieldNames(frame), ShouldResemble, []string{"@timestamp", "_id", "_index", "_source", "_type"})
+			So(frame.Fields[0].Len(), ShouldEqual, 2)
+			So(*frame.Fields[0].At(0).(*time.Time), ShouldEqual, time.Date(2018, 5, 15, 17, 54, 0, 0, time.UTC))
+			So(*frame.Fields[1].At(1).(*string), ShouldEqual, "2")
+			So(*frame.Fields[3].At(0).(*string), ShouldEqual, `{"nested":{"prop":1},"sourceProp":"asd"}`)
+		})
+
+		Convey("Raw data query", func() {
+			targets := map[string]string{
+				"A": `{
+					"timeField": "@timestamp",
+					"metrics": [{ "type": "raw_data", "id": "1" }]
+				}`,
+			}
+			response := `{
+        "responses": [
+          {
+            "hits": {
+              "total": { "value": 2, "relation": "eq" },
+              "hits": [
+                {
+                  "_id": "1",
+                  "_type": "_doc",
+                  "_index": "index",
+                  "_source": {
+                    "@timestamp": "2018-05-15T17:54:00.000Z",
+                    "host": { "name": "server-1" },
+                    "value": 10,
+                    "up": true,
+                    "tags": ["a", "b"]
+                  }
+                },
+                {
+                  "_id": "2",
+                  "_type": "_doc",
+                  "_index": "index",
+                  "_source": {
+                    "@timestamp": 1526406780000,
+                    "host": { "name": "server-2" },
+                    "up": false
+                  }
+                }
+              ]
+            }
+          }
+        ]
+			}`
+			rp, err := newResponseParserForTest(targets, response)
+			So(err, ShouldBeNil)
+			result, err := rp.getTimeSeries()
+			So(err, ShouldBeNil)
+
+			frames, err := data.UnmarshalArrowFrames(result.Results["A"].Dataframes)
+			So(err, ShouldBeNil)
+			frame := frames[0]
+			So(frame.Meta.Custom["total"], ShouldEqual, 2)
+			So(frameFieldNames(frame), ShouldResemble, []string{"@timestamp", "_id", "_index", "_type", "host.name", "tags", "up", "value"})
+
+			So(*frame.Fields[0].At(0).(*time.Time), ShouldEqual, time.Date(2018, 5, 15, 17, 54, 0, 0, time.UTC))
+			So(*frame.Fields[0].At(1).(*time.Time), ShouldEqual, time.Date(2018, 5, 15, 17, 53, 0, 0, time.UTC))
+			So(*frame.Fields[4].At(1).(*string), ShouldEqual, "server-2")
+			So(*frame.Fields[5].At(0).(*string), ShouldEqual, `["a","b"]`)
+			So(frame.Fields[5].At(1).(*string), ShouldBeNil)
+			So(*frame.Fields[6].At(1).(*bool), ShouldBeFalse)
+			So(*frame.Fields[7].At(0).(*float64), ShouldEqual, 10)
+			So(frame.Fields[7].At(1).(*float64), ShouldBeNil)
+		})
+
+		Convey("Logs query with date histogram", func() {
+			targets := map[string]string{
+				"A": `{
+					"timeField": "@timestamp",
+					"metrics": [{ "type": "logs", "id": "1" }],
+					"bucketAggs": [{ "type": "date_histogram", "field": "@timestamp", "id": "2" }]
+				}`,
+			}
+			response := `{
+        "responses": [
+          {
+            "aggregations": {
+              "2": {
+                "buckets": [
+                  { "doc_count": 1, "key": 1526406720000 },
+                  { "doc_count": 1, "key": 1526406780000 }
+                ]
+              }
+            },
+            "hits": {
+              "total": { "value": 2, "relation": "eq" },
+              "hits": [
+                {
+                  "_id": "1",
+                  "_source": { "@timestamp": "2018-05-15T17:53:10.000Z", "message": "hello", "level": "info" }
+                },
+                {
+                  "_id": "2",
+                  "_source": { "@timestamp": "2018-05-15T17:52:30.000Z", "message": "world", "level": "error" }
+                }
+              ]
+            }
+          }
+        ]
+			}`
+			rp, err := newResponseParserForTest(targets, response)
+			So(err, ShouldBeNil)
+			result, err := rp.getTimeSeries()
+			So(err, ShouldBeNil)
+
+			queryRes := result.Results["A"]
+			So(queryRes.Series, ShouldHaveLength, 1)
+			So(queryRes.Series[0].Name, ShouldEqual, "Count")
+			So(queryRes.Series[0].Points[0][0].Float64, ShouldEqual, 1)
+			So(queryRes.Series[0].Points[1][1].Float64, ShouldEqual, 1526406780000)
+
+			frames, err := data.UnmarshalArrowFrames(queryRes.Dataframes)
+			So(err, ShouldBeNil)
+			frame := frames[0]
+			So(frame.Name, ShouldEqual, "logs")
+			So(frame.Meta.Custom["preferredVisualisationType"], ShouldEqual, "logs")
+			So(frameFieldNames(frame), ShouldResemble, []string{"@timestamp", "_id", "_index", "_type", "level", "message"})
+			So(*frame.Fields[5].At(0).(*string), ShouldEqual, "hello")
+		})
 	})
 }
 
@@ -956,3 +1330,11 @@ func newResponseParserForTest(tsdbQueries map[string]string, responseBody string
 
 	return newResponseParser(response.Responses, queries, nil), nil
 }
+
+func frameFieldNames(frame *data.Frame) []string {
+	names := make([]string, 0, len(frame.Fields))
+	for _, field := range frame.Fields {
+		names = append(names, field.Name)
+	}
+	return names
+}
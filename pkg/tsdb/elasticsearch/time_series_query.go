@@ -9,6 +9,10 @@ import (
 	"github.com/grafana/grafana/pkg/tsdb/elasticsearch/client"
 )
 
+// defaultDocumentSize is the number of documents returned by
+// raw_document, raw_data and logs queries without size.
+const defaultDocumentSize = 500
+
 type timeSeriesQuery struct {
 	client             es.Client
 	tsdbQuery          *tsdb.TsdbQuery
@@ -54,19 +58,19 @@ func (e *timeSeriesQuery) execute() (*tsdb.Response, error) {
 			filters.AddQueryStringFilter(q.RawQuery, true)
 		}
 
-		if len(q.BucketAggs) == 0 {
-			if len(q.Metrics) == 0 || q.Metrics[0].Type != "raw_document" {
-				result.Results[q.RefID] = &tsdb.QueryResult{
-					RefId:       q.RefID,
-					Error:       fmt.Errorf("invalid query, missing metrics and aggregations"),
-					ErrorString: "invalid query, missing metrics and aggregations",
-				}
+		if isDocumentQuery(q) {
+			addDocumentQuery(b, q.Metrics[0], e.client.GetTimeField())
+
+			// logs queries can count the documents with bucket aggregations
+			if q.Metrics[0].Type != logsType || len(q.BucketAggs) == 0 {
 				continue
 			}
-			metric := q.Metrics[0]
-			b.Size(metric.Settings.Get("size").MustInt(500))
-			b.SortDesc("@timestamp", "boolean")
-			b.AddDocValueField("@timestamp")
+		} else if len(q.BucketAggs) == 0 {
+			result.Results[q.RefID] = &tsdb.QueryResult{
+				RefId:       q.RefID,
+				Error:       fmt.Errorf("invalid query, missing metrics and aggregations"),
+				ErrorString: "invalid query, missing metrics and aggregations",
+			}
 			continue
 		}
 
@@ -85,71 +89,19 @@ func (e *timeSeriesQuery) execute() (*tsdb.Response, error) {
 				aggBuilder = addTermsAgg(aggBuilder, bucketAgg, q.Metrics)
 			case geohashGridType:
 				aggBuilder = addGeoHashGridAgg(aggBuilder, bucketAgg)
+			case dateRangeType:
+				aggBuilder = addDateRangeAgg(aggBuilder, bucketAgg)
+			case compositeType:
+				aggBuilder = addCompositeAgg(aggBuilder, bucketAgg)
 			}
 		}
 
 		for _, m := range q.Metrics {
-			if m.Type == countType {
+			if m.Type == countType || isDocumentMetric(m.Type) {
 				continue
 			}
 
-			if isPipelineAgg(m.Type) {
-				if isPipelineAggWithMultipleBucketPaths(m.Type) {
-					if len(m.PipelineVariables) > 0 {
-						bucketPaths := map[string]interface{}{}
-						for name, pipelineAgg := range m.PipelineVariables {
-							if _, err := strconv.Atoi(pipelineAgg); err == nil {
-								var appliedAgg *MetricAgg
-								for _, pipelineMetric := range q.Metrics {
-									if pipelineMetric.ID == pipelineAgg {
-										appliedAgg = pipelineMetric
-										break
-									}
-								}
-								if appliedAgg != nil {
-									if appliedAgg.Type == countType {
-										bucketPaths[name] = "_count"
-									} else {
-										bucketPaths[name] = pipelineAgg
-									}
-								}
-							}
-						}
-
-						aggBuilder.Pipeline(m.ID, m.Type, bucketPaths, func(a *es.PipelineAggregation) {
-							a.Settings = m.Settings.MustMap()
-						})
-					} else {
-						continue
-					}
-				} else {
-					if _, err := strconv.Atoi(m.PipelineAggregate); err == nil {
-						var appliedAgg *MetricAgg
-						for _, pipelineMetric := range q.Metrics {
-							if pipelineMetric.ID == m.PipelineAggregate {
-								appliedAgg = pipelineMetric
-								break
-							}
-						}
-						if appliedAgg != nil {
-							bucketPath := m.PipelineAggregate
-							if appliedAgg.Type == countType {
-								bucketPath = "_count"
-							}
-
-							aggBuilder.Pipeline(m.ID, m.Type, bucketPath, func(a *es.PipelineAggregation) {
-								a.Settings = m.Settings.MustMap()
-							})
-						}
-					} else {
-						continue
-					}
-				}
-			} else {
-				aggBuilder.Metric(m.ID, m.Type, m.Field, func(a *es.MetricAggregation) {
-					a.Settings = m.Settings.MustMap()
-				})
-			}
+			addMetricAgg(aggBuilder, m, q.Metrics)
 		}
 	}
 
@@ -167,6 +119,86 @@ func (e *timeSeriesQuery) execute() (*tsdb.Response, error) {
 	return rp.getTimeSeries()
 }
 
+// addDocumentQuery sets the size and sort of queries returning documents. The size of
+// raw_document and raw_data metrics is read from the size setting, the size of logs
+// from the limit setting, documents are sorted by time in descending order by default.
+func addDocumentQuery(b *es.SearchRequestBuilder, metric *MetricAgg, timeField string) {
+	size := metric.Settings.Get("size").MustInt(defaultDocumentSize)
+	if metric.Type == logsType {
+		size = metric.Settings.Get("limit").MustInt(defaultDocumentSize)
+	}
+	if size <= 0 {
+		size = defaultDocumentSize
+	}
+
+	order := metric.Settings.Get("order").MustString(es.SortOrderDesc)
+	if order != es.SortOrderAsc {
+		order = es.SortOrderDesc
+	}
+
+	b.Size(size)
+	b.Sort(order, timeField, "boolean")
+	b.AddDocValueField(timeField)
+}
+
+// addMetricAgg adds a metric or pipeline aggregation to the aggregation builder. Pipeline
+// aggregations are skipped when the metrics they are applied to cannot be found.
+func addMetricAgg(aggBuilder es.AggBuilder, m *MetricAgg, metrics []*MetricAgg) {
+	if !isPipelineAgg(m.Type) {
+		aggBuilder.Metric(m.ID, m.Type, m.Field, func(a *es.MetricAggregation) {
+			a.Settings = m.Settings.MustMap()
+		})
+		return
+	}
+
+	bucketPath, ok := getPipelineBucketPath(m, metrics)
+	if !ok {
+		return
+	}
+
+	aggBuilder.Pipeline(m.ID, m.Type, bucketPath, func(a *es.PipelineAggregation) {
+		a.Settings = m.Settings.MustMap()
+	})
+}
+
+// getPipelineBucketPath returns the buckets path of a pipeline metric, a map of
+// variable names to metrics for bucket_script and a single metric otherwise.
+func getPipelineBucketPath(m *MetricAgg, metrics []*MetricAgg) (interface{}, bool) {
+	findBucketPath := func(id string) (string, bool) {
+		if _, err := strconv.Atoi(id); err != nil {
+			return "", false
+		}
+
+		for _, pipelineMetric := range metrics {
+			if pipelineMetric.ID == id {
+				if pipelineMetric.Type == countType {
+					return "_count", true
+				}
+				return id, true
+			}
+		}
+
+		return "", false
+	}
+
+	if !isPipelineAggWithMultipleBucketPaths(m.Type) {
+		return findBucketPath(m.PipelineAggregate)
+	}
+
+	if len(m.PipelineVariables) == 0 {
+		return nil, false
+	}
+
+	bucketPaths := map[string]interface{}{}
+	for name, pipelineAgg := range m.PipelineVariables {
+		if bucketPath, ok := findBucketPath(pipelineAgg); ok {
+			bucketPaths[name] = bucketPath
+		}
+	}
+
+	return bucketPaths, true
+}
+
 func addDateHistogramAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg, timeFrom, timeTo string) es.AggBuilder {
 	aggBuilder.DateHistogram(bucketAgg.ID, bucketAgg.Field, func(a *es.DateHistogramAgg, b es.AggBuilder) {
 		a.Interval = bucketAgg.Settings.Get("interval").MustString("auto")
@@ -231,16 +263,27 @@ func addTermsAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg, metrics []*Metr
 		}
 
 		if orderBy, err := bucketAgg.Settings.Get("orderBy").String(); err == nil {
-			a.Order[orderBy] = bucketAgg.Settings.Get("order").MustString("desc")
+			order := bucketAgg.Settings.Get("order").MustString("desc")
 
+			var orderByMetric *MetricAgg
 			if _, err := strconv.Atoi(orderBy); err == nil {
 				for _, m := range metrics {
 					if m.ID == orderBy {
-						b.Metric(m.ID, m.Type, m.Field, nil)
+						orderByMetric = m
 						break
 					}
 				}
 			}
+
+			switch {
+			case orderByMetric == nil:
+				a.Order[orderBy] = order
+			case isPipelineAgg(orderByMetric.Type):
+				addTermsPipelineOrder(a, b, orderByMetric, metrics, order)
+			default:
+				a.Order[orderBy] = order
+				b.Metric(orderByMetric.ID, orderByMetric.Type, orderByMetric.Field, nil)
+			}
 		}
 
 		aggBuilder = b
@@ -249,6 +292,55 @@ func addTermsAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg, metrics []*Metr
 	return aggBuilder
 }
 
+// termsPipelineOrderCandidates is the minimum number of terms, picked by document count,
+// that a pipeline metric is computed for before the top terms by the metric are kept.
+const termsPipelineOrderCandidates = 500
+
+// addTermsPipelineOrder orders the buckets of a terms aggregation by a pipeline metric. Terms
+// cannot be ordered by pipeline aggregations, so the metric and the metrics it is applied to
+// are added to the terms buckets, and a bucket_sort aggregation sorts them by the metric and
+// trims them to the size of the terms. Elasticsearch still picks the terms buckets by
+// document count, so the size of the terms is raised to termsPipelineOrderCandidates first
+// and terms outside of these candidates are never returned. Only pipeline aggregations with
+// multiple bucket paths can be computed per term, others require a histogram parent and
+// leave the terms ordered by document count.
+func addTermsPipelineOrder(a *es.TermsAggregation, b es.AggBuilder, m *MetricAgg, metrics []*MetricAgg, order string) {
+	if !isPipelineAggWithMultipleBucketPaths(m.Type) {
+		return
+	}
+
+	bucketPath, ok := getPipelineBucketPath(m, metrics)
+	if !ok {
+		return
+	}
+
+	for _, pipelineAgg := range m.PipelineVariables {
+		for _, appliedAgg := range metrics {
+			if appliedAgg.ID == pipelineAgg && appliedAgg.Type != countType && !isPipelineAgg(appliedAgg.Type) {
+				b.Metric(appliedAgg.ID, appliedAgg.Type, appliedAgg.Field, func(a *es.MetricAggregation) {
+					a.Settings = appliedAgg.Settings.MustMap()
+				})
+			}
+		}
+	}
+
+	b.Pipeline(m.ID, m.Type, bucketPath, func(a *es.PipelineAggregation) {
+		a.Settings = m.Settings.MustMap()
+	})
+
+	size := a.Size
+	if a.Size < termsPipelineOrderCandidates {
+		a.Size = termsPipelineOrderCandidates
+	}
+
+	b.BucketSort("bucket_sort", func(a *es.BucketSortAggregation) {
+		a.Sort = append(a.Sort, map[string]interface{}{
+			m.ID: map[string]string{"order": order},
+		})
+		a.Size = size
+	})
+}
+
 func addFiltersAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg) es.AggBuilder {
 	filters := make(map[string]interface{})
 	for _, filter := range bucketAgg.Settings.Get("filters").MustArray() {
@@ -280,6 +372,83 @@ func addGeoHashGridAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg) es.AggBui
 	return aggBuilder
 }
 
+func addDateRangeAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg) es.AggBuilder {
+	ranges := make([]es.DateRange, 0)
+	for _, r := range bucketAgg.Settings.Get("ranges").MustArray() {
+		rangeJSON := simplejson.NewFromAny(r)
+		dateRange := es.DateRange{
+			Key:  rangeJSON.Get("key").MustString(),
+			From: rangeJSON.Get("from").MustString(),
+			To:   rangeJSON.Get("to").MustString(),
+		}
+		if dateRange.From == "" && dateRange.To == "" {
+			continue
+		}
+		ranges = append(ranges, dateRange)
+	}
+
+	if len(ranges) > 0 {
+		aggBuilder.DateRange(bucketAgg.ID, bucketAgg.Field, func(a *es.DateRangeAggregation, b es.AggBuilder) {
+			a.Ranges = ranges
+			a.Format = bucketAgg.Settings.Get("format").MustString()
+			aggBuilder = b
+		})
+	}
+
+	return aggBuilder
+}
+
+func addCompositeAgg(aggBuilder es.AggBuilder, bucketAgg *BucketAgg) es.AggBuilder {
+	aggBuilder.Composite(bucketAgg.ID, func(a *es.CompositeAggregation, b es.AggBuilder) {
+		a.Size = bucketAgg.Settings.Get("size").MustInt(500)
+
+		for _, source := range getCompositeSources(bucketAgg) {
+			compositeSource := &es.CompositeSource{
+				Name:     source.Get("name").MustString(),
+				Type:     source.Get("type").MustString(termsType),
+				Field:    source.Get("field").MustString(),
+				Interval: source.Get("interval").MustString(),
+				Order:    source.Get("order").MustString(),
+			}
+
+			if compositeSource.Type == dateHistType && (compositeSource.Interval == "" || compositeSource.Interval == "auto") {
+				compositeSource.Interval = "$__interval"
+			}
+
+			a.Sources = append(a.Sources, compositeSource)
+		}
+
+		aggBuilder = b
+	})
+
+	return aggBuilder
+}
+
+// getCompositeSources returns the values sources of a composite aggregation, read from the
+// sources setting or a terms source of the aggregation field when it has no sources.
+func getCompositeSources(bucketAgg *BucketAgg) []*simplejson.Json {
+	sources := make([]*simplejson.Json, 0)
+	for _, s := range bucketAgg.Settings.Get("sources").MustArray() {
+		source := simplejson.NewFromAny(s)
+		if source.Get("field").MustString() == "" {
+			continue
+		}
+		if source.Get("name").MustString() == "" {
+			source.Set("name", source.Get("field").MustString())
+		}
+		sources = append(sources, source)
+	}
+
+	if len(sources) == 0 && bucketAgg.Field != "" {
+		sources = append(sources, simplejson.NewFromAny(map[string]interface{}{
+			"name":  bucketAgg.Field,
+			"field": bucketAgg.Field,
+		}))
+	}
+
+	return sources
+}
+
 type timeSeriesQueryParser struct{}
 
 func newTimeSeriesQueryParser() *timeSeriesQueryParser {
@@ -302,6 +302,53 @@ func TestExecuteTimeSeriesQuery(t *testing.T) {
 			So(sr.Size, ShouldEqual, 1337)
 		})
 
+		Convey("With raw document metric should sort by time field", func() {
+			c := newFakeClient(5)
+			c.timeField = "timestamp"
+			_, err := executeTsdbQuery(c, `{
+				"timeField": "timestamp",
+				"bucketAggs": [],
+				"metrics": [{ "id": "1", "type": "raw_document", "settings": { "order": "asc" } }]
+			}`, from, to, 15*time.Second)
+			So(err, ShouldBeNil)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			So(sr.Sort["timestamp"], ShouldResemble, map[string]string{"order": "asc", "unmapped_type": "boolean"})
+			So(sr.CustomProps["docvalue_fields"], ShouldResemble, []string{"timestamp"})
+		})
+
+		Convey("With raw data metric", func() {
+			c := newFakeClient(5)
+			_, err := executeTsdbQuery(c, `{
+				"timeField": "@timestamp",
+				"bucketAggs": [],
+				"metrics": [{ "id": "1", "type": "raw_data", "settings": { "size": 10 } }]
+			}`, from, to, 15*time.Second)
+			So(err, ShouldBeNil)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			So(sr.Size, ShouldEqual, 10)
+			So(sr.Sort["@timestamp"], ShouldResemble, map[string]string{"order": "desc", "unmapped_type": "boolean"})
+			So(sr.Aggs, ShouldBeEmpty)
+		})
+
+		Convey("With logs metric and date histogram agg", func() {
+			c := newFakeClient(5)
+			_, err := executeTsdbQuery(c, `{
+				"timeField": "@timestamp",
+				"bucketAggs": [{ "id": "2", "type": "date_histogram", "field": "@timestamp", "settings": { "interval": "auto" } }],
+				"metrics": [{ "id": "1", "type": "logs", "settings": { "limit": 100 } }]
+			}`, from, to, 15*time.Second)
+			So(err, ShouldBeNil)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			So(sr.Size, ShouldEqual, 100)
+			So(sr.Sort["@timestamp"], ShouldNotBeNil)
+			So(sr.Aggs, ShouldHaveLength, 1)
+			So(sr.Aggs[0].Key, ShouldEqual, "2")
+			So(sr.Aggs[0].Aggregation.Aggs, ShouldBeEmpty)
+		})
+
 		Convey("With date histogram agg", func() {
 			c := newFakeClient(5)
 			_, err := executeTsdbQuery(c, `{
@@ -614,6 +661,166 @@ func TestExecuteTimeSeriesQuery(t *testing.T) {
 				"var1": "_count",
 			})
 		})
+
+		Convey("With cumulative sum and serial difference", func() {
+			c := newFakeClient(5)
+			_, err := executeTsdbQuery(c, `{
+				"timeField": "@timestamp",
+				"bucketAggs": [
+					{ "type": "date_histogram", "field": "@timestamp", "id": "4" }
+				],
+				"metrics": [
+					{ "id": "3", "type": "sum", "field": "@value" },
+					{ "id": "2", "type": "cumulative_sum", "pipelineAgg": "3" },
+					{ "id": "5", "type": "serial_diff", "pipelineAgg": "3", "settings": { "lag": "7" } }
+				]
+			}`, from, to, 15*time.Second)
+			So(err, ShouldBeNil)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			firstLevel := sr.Aggs[0]
+			So(firstLevel.Aggregation.Aggs, ShouldHaveLength, 3)
+
+			cumulativeSumAgg := firstLevel.Aggregation.Aggs[1]
+			So(cumulativeSumAgg.Key, ShouldEqual, "2")
+			So(cumulativeSumAgg.Aggregation.Type, ShouldEqual, "cumulative_sum")
+			So(cumulativeSumAgg.Aggregation.Aggregation.(*es.PipelineAggregation).BucketPath, ShouldEqual, "3")
+
+			serialDiffAgg := firstLevel.Aggregation.Aggs[2]
+			So(serialDiffAgg.Key, ShouldEqual, "5")
+			So(serialDiffAgg.Aggregation.Type, ShouldEqual, "serial_diff")
+			plAgg := serialDiffAgg.Aggregation.Aggregation.(*es.PipelineAggregation)
+			So(plAgg.BucketPath, ShouldEqual, "3")
+			So(plAgg.Settings["lag"], ShouldEqual, "7")
+		})
+
+		Convey("With term agg and order by bucket_script", func() {
+			c := newFakeClient(5)
+			_, err := executeTsdbQuery(c, `{
+				"timeField": "@timestamp",
+				"bucketAggs": [
+					{
+						"type": "terms",
+						"field": "@host",
+						"id": "2",
+						"settings": { "size": "5", "order": "asc", "orderBy": "6" }
+					},
+					{ "type": "date_histogram", "field": "@timestamp", "id": "4" }
+				],
+				"metrics": [
+					{ "id": "1", "type": "count" },
+					{ "id": "3", "type": "sum", "field": "@value" },
+					{
+						"id": "6",
+						"type": "bucket_script",
+						"pipelineVariables": [
+							{ "name": "var1", "pipelineAgg": "3" },
+							{ "name": "var2", "pipelineAgg": "1" }
+						],
+						"settings": { "script": "params.var1 / params.var2" }
+					}
+				]
+			}`, from, to, 15*time.Second)
+			So(err, ShouldBeNil)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			termsAgg := sr.Aggs[0].Aggregation
+			So(termsAgg.Aggregation.(*es.TermsAggregation).Order, ShouldBeEmpty)
+			// the script is computed for more terms than returned
+			So(termsAgg.Aggregation.(*es.TermsAggregation).Size, ShouldEqual, 500)
+			So(termsAgg.Aggs, ShouldHaveLength, 4)
+
+			sumAgg := termsAgg.Aggs[0]
+			So(sumAgg.Key, ShouldEqual, "3")
+			So(sumAgg.Aggregation.Type, ShouldEqual, "sum")
+
+			bucketScriptAgg := termsAgg.Aggs[1]
+			So(bucketScriptAgg.Key, ShouldEqual, "6")
+			So(bucketScriptAgg.Aggregation.Aggregation.(*es.PipelineAggregation).BucketPath, ShouldResemble, map[string]interface{}{
+				"var1": "3",
+				"var2": "_count",
+			})
+
+			bucketSortAgg := termsAgg.Aggs[2]
+			So(bucketSortAgg.Aggregation.Type, ShouldEqual, "bucket_sort")
+			bucketSort := bucketSortAgg.Aggregation.Aggregation.(*es.BucketSortAggregation)
+			So(bucketSort.Size, ShouldEqual, 5)
+			So(bucketSort.Sort, ShouldResemble, []map[string]interface{}{
+				{"6": map[string]string{"order": "asc"}},
+			})
+
+			So(termsAgg.Aggs[3].Aggregation.Type, ShouldEqual, "date_histogram")
+		})
+
+		Convey("With date range agg", func() {
+			c := newFakeClient(5)
+			_, err := executeTsdbQuery(c, `{
+				"timeField": "@timestamp",
+				"bucketAggs": [
+					{
+						"id": "2",
+						"type": "date_range",
+						"field": "@timestamp",
+						"settings": {
+							"format": "yyyy-MM-dd",
+							"ranges": [
+								{ "to": "now-1d/d", "key": "older" },
+								{ "from": "now-1d/d" },
+								{ "key": "empty" }
+							]
+						}
+					}
+				],
+				"metrics": [{ "type": "count", "id": "1" }]
+			}`, from, to, 15*time.Second)
+			So(err, ShouldBeNil)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			firstLevel := sr.Aggs[0]
+			So(firstLevel.Key, ShouldEqual, "2")
+			So(firstLevel.Aggregation.Type, ShouldEqual, "date_range")
+			dateRangeAgg := firstLevel.Aggregation.Aggregation.(*es.DateRangeAggregation)
+			So(dateRangeAgg.Field, ShouldEqual, "@timestamp")
+			So(dateRangeAgg.Format, ShouldEqual, "yyyy-MM-dd")
+			So(dateRangeAgg.Ranges, ShouldResemble, []es.DateRange{
+				{Key: "older", To: "now-1d/d"},
+				{From: "now-1d/d"},
+			})
+		})
+
+		Convey("With composite agg", func() {
+			c := newFakeClient(6)
+			_, err := executeTsdbQuery(c, `{
+				"timeField": "@timestamp",
+				"bucketAggs": [
+					{
+						"id": "2",
+						"type": "composite",
+						"settings": {
+							"size": 100,
+							"sources": [
+								{ "name": "host", "field": "@host" },
+								{ "name": "time", "type": "date_histogram", "field": "@timestamp", "interval": "auto" }
+							]
+						}
+					}
+				],
+				"metrics": [{ "type": "avg", "field": "@value", "id": "1" }]
+			}`, from, to, 15*time.Second)
+			So(err, ShouldBeNil)
+			sr := c.multisearchRequests[0].Requests[0]
+
+			firstLevel := sr.Aggs[0]
+			So(firstLevel.Key, ShouldEqual, "2")
+			So(firstLevel.Aggregation.Type, ShouldEqual, "composite")
+			compositeAgg := firstLevel.Aggregation.Aggregation.(*es.CompositeAggregation)
+			So(compositeAgg.Size, ShouldEqual, 100)
+			So(compositeAgg.Sources, ShouldResemble, []*es.CompositeSource{
+				{Name: "host", Type: "terms", Field: "@host"},
+				{Name: "time", Type: "date_histogram", Field: "@timestamp", Interval: "$__interval"},
+			})
+			So(firstLevel.Aggregation.Aggs[0].Aggregation.Type, ShouldEqual, "avg")
+		})
 	})
 }
 
@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"path"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"golang.org/x/net/context/ctxhttp"
@@ -35,18 +36,32 @@ func init() {
 	tsdb.RegisterTsdbQueryEndpoint("graphite", NewGraphiteExecutor)
 }
 
+// defaultMaxDataPoints is the number of data points Graphite consolidates
+// series to when the query has no max data points, as in alert rules.
+const defaultMaxDataPoints = 500
+
 func (e *GraphiteExecutor) Query(ctx context.Context, dsInfo *models.DataSource, tsdbQuery *tsdb.TsdbQuery) (*tsdb.Response, error) {
+	if len(tsdbQuery.Queries) > 0 {
+		switch tsdbQuery.Queries[0].Model.Get("type").MustString() {
+		case "tagsAutoComplete":
+			return e.executeTagsAutoComplete(ctx, dsInfo, tsdbQuery)
+		case "functions":
+			return e.executeFunctions(ctx, dsInfo, tsdbQuery)
+		}
+	}
+
 	result := &tsdb.Response{}
 
 	from := "-" + formatTimeRange(tsdbQuery.TimeRange.From)
 	until := formatTimeRange(tsdbQuery.TimeRange.To)
 	var target string
+	refID := "A"
+	maxDataPoints := int64(defaultMaxDataPoints)
 
 	formData := url.Values{
-		"from":          []string{from},
-		"until":         []string{until},
-		"format":        []string{"json"},
-		"maxDataPoints": []string{"500"},
+		"from":   []string{from},
+		"until":  []string{until},
+		"format": []string{"json"},
 	}
 
 	emptyQueries := make([]string, 0)
@@ -64,6 +79,13 @@ func (e *GraphiteExecutor) Query(ctx context.Context, dsInfo *models.DataSource,
 			continue
 		}
 		target = fixIntervalFormat(currTarget)
+
+		if query.RefId != "" {
+			refID = query.RefId
+		}
+		if query.MaxDataPoints > 0 {
+			maxDataPoints = query.MaxDataPoints
+		}
 	}
 
 	if target == "" {
@@ -72,6 +94,7 @@ func (e *GraphiteExecutor) Query(ctx context.Context, dsInfo *models.DataSource,
 	}
 
 	formData["target"] = []string{target}
+	formData["maxDataPoints"] = []string{strconv.FormatInt(maxDataPoints, 10)}
 
 	if setting.Env == setting.DEV {
 		glog.Debug("Graphite request", "params", formData)
@@ -120,6 +143,7 @@ func (e *GraphiteExecutor) Query(ctx context.Context, dsInfo *models.DataSource,
 		queryRes.Series = append(queryRes.Series, &tsdb.TimeSeries{
 			Name:   series.Target,
 			Points: series.DataPoints,
+			Tags:   series.getTags(),
 		})
 
 		if setting.Env == setting.DEV {
@@ -127,7 +151,8 @@ func (e *GraphiteExecutor) Query(ctx context.Context, dsInfo *models.DataSource,
 		}
 	}
 
-	result.Results["A"] = queryRes
+	queryRes.RefId = refID
+	result.Results[refID] = queryRes
 	return result, nil
 }
 
@@ -171,6 +196,51 @@ func (e *GraphiteExecutor) createRequest(dsInfo *models.DataSource, data url.Val
 	return req, err
 }
 
+// createMetadataRequest creates a GET request of the Graphite API, such as the tags and functions APIs.
+func (e *GraphiteExecutor) createMetadataRequest(dsInfo *models.DataSource, apiPath string, params url.Values) (*http.Request, error) {
+	u, _ := url.Parse(dsInfo.Url)
+	u.Path = path.Join(u.Path, apiPath)
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		glog.Info("Failed to create request", "error", err)
+		return nil, fmt.Errorf("Failed to create request. error: %v", err)
+	}
+
+	if dsInfo.BasicAuth {
+		req.SetBasicAuth(dsInfo.BasicAuthUser, dsInfo.DecryptedBasicAuthPassword())
+	}
+
+	return req, nil
+}
+
+// doMetadataRequest sends a request of the Graphite API and returns the body of the response.
+func (e *GraphiteExecutor) doMetadataRequest(ctx context.Context, dsInfo *models.DataSource, req *http.Request) ([]byte, error) {
+	httpClient, err := dsInfo.GetHttpClient()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := ctxhttp.Do(ctx, httpClient, req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	defer res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode/100 != 2 {
+		glog.Info("Request failed", "url", req.URL.Path, "status", res.Status, "body", string(body))
+		return nil, fmt.Errorf("Request failed status: %v", res.Status)
+	}
+
+	return body, nil
+}
+
 func formatTimeRange(input string) string {
 	if input == "now" {
 		return input
@@ -1,8 +1,16 @@
 package graphite
 
 import (
-	. "github.com/smartystreets/goconvey/convey"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/tsdb"
+	. "github.com/smartystreets/goconvey/convey"
 )
 
 func TestGraphiteFunctions(t *testing.T) {
@@ -59,3 +67,75 @@ func TestGraphiteFunctions(t *testing.T) {
 
 	})
 }
+
+func TestGraphiteQuery(t *testing.T) {
+	Convey("Graphite render query", t, func() {
+		var form url.Values
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := r.ParseForm(); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			form = r.PostForm
+			_, _ = w.Write([]byte(`[
+				{
+					"target": "cpu;host=server-1;dc=us",
+					"tags": { "name": "cpu", "host": "server-1", "dc": "us", "shard": 2 },
+					"datapoints": [[1.5, 1000], [null, 1060]]
+				},
+				{ "target": "mem", "datapoints": [[2, 1000]] }
+			]`))
+		}))
+		defer server.Close()
+
+		dsInfo := &models.DataSource{Id: 1, Url: server.URL, JsonData: simplejson.New()}
+		executor := &GraphiteExecutor{}
+
+		Convey("should parse series tags and use the max data points of the query", func() {
+			res, err := executor.Query(context.Background(), dsInfo, &tsdb.TsdbQuery{
+				TimeRange: tsdb.NewTimeRange("1h", "now"),
+				Queries: []*tsdb.Query{
+					{
+						RefId:         "B",
+						MaxDataPoints: 1200,
+						Model:         simplejson.NewFromAny(map[string]interface{}{"target": "seriesByTag('name=cpu')"}),
+					},
+				},
+			})
+			So(err, ShouldBeNil)
+
+			So(form.Get("target"), ShouldEqual, "seriesByTag('name=cpu')")
+			So(form.Get("maxDataPoints"), ShouldEqual, "1200")
+			So(form.Get("from"), ShouldEqual, "-1h")
+
+			queryRes := res.Results["B"]
+			So(queryRes, ShouldNotBeNil)
+			So(queryRes.Series, ShouldHaveLength, 2)
+			So(queryRes.Series[0].Name, ShouldEqual, "cpu;host=server-1;dc=us")
+			So(queryRes.Series[0].Tags, ShouldResemble, map[string]string{
+				"name":  "cpu",
+				"host":  "server-1",
+				"dc":    "us",
+				"shard": "2",
+			})
+			So(queryRes.Series[0].Points, ShouldHaveLength, 2)
+			So(queryRes.Series[0].Points[1][0].Valid, ShouldBeFalse)
+			So(queryRes.Series[1].Tags, ShouldBeEmpty)
+		})
+
+		Convey("should default max data points for alert queries", func() {
+			res, err := executor.Query(context.Background(), dsInfo, &tsdb.TsdbQuery{
+				TimeRange: tsdb.NewTimeRange("1h", "now"),
+				Queries: []*tsdb.Query{
+					{
+						RefId: "A",
+						Model: simplejson.NewFromAny(map[string]interface{}{"target": "mem"}),
+					},
+				},
+			})
+			So(err, ShouldBeNil)
+			So(form.Get("maxDataPoints"), ShouldEqual, "500")
+			So(res.Results["A"].Series, ShouldHaveLength, 2)
+		})
+	})
+}
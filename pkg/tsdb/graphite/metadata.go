@@ -0,0 +1,175 @@
+package graphite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/tsdb"
+)
+
+// functionsCacheTTL is how long the functions of a data source are cached,
+// they only change when Graphite is upgraded or its functions plugins change.
+const functionsCacheTTL = time.Hour
+
+type functionsCacheItem struct {
+	version   int
+	functions *simplejson.Json
+	expires   time.Time
+}
+
+var (
+	functionsCache     = make(map[int64]*functionsCacheItem)
+	functionsCacheLock sync.Mutex
+
+	// Graphite encodes the default of some function parameters as Infinity, which is not valid JSON
+	infinityRegex = regexp.MustCompile(`"default": ?(-?)Infinity`)
+)
+
+// executeTagsAutoComplete returns the tags, or the values of a tag, of the series matching the tag
+// expressions of the query, as a table with a text and a value column like metric find queries.
+func (e *GraphiteExecutor) executeTagsAutoComplete(ctx context.Context, dsInfo *models.DataSource, tsdbQuery *tsdb.TsdbQuery) (*tsdb.Response, error) {
+	query := tsdbQuery.Queries[0]
+	model := query.Model
+
+	params := url.Values{}
+	for _, expr := range model.Get("expressions").MustArray() {
+		if s, ok := expr.(string); ok && s != "" {
+			params.Add("expr", fixIntervalFormat(s))
+		}
+	}
+
+	apiPath := "tags/autoComplete/tags"
+	if tag := model.Get("tag").MustString(); tag != "" {
+		apiPath = "tags/autoComplete/values"
+		params.Set("tag", tag)
+		if valuePrefix := model.Get("valuePrefix").MustString(); valuePrefix != "" {
+			params.Set("valuePrefix", valuePrefix)
+		}
+	} else if tagPrefix := model.Get("tagPrefix").MustString(); tagPrefix != "" {
+		params.Set("tagPrefix", tagPrefix)
+	}
+
+	if limit := model.Get("limit").MustInt(); limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+
+	if tsdbQuery.TimeRange != nil {
+		params.Set("from", "-"+formatTimeRange(tsdbQuery.TimeRange.From))
+		params.Set("until", formatTimeRange(tsdbQuery.TimeRange.To))
+	}
+
+	req, err := e.createMetadataRequest(dsInfo, apiPath, params)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := e.doMetadataRequest(ctx, dsInfo, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []string
+	if err := json.Unmarshal(body, &values); err != nil {
+		glog.Info("Failed to unmarshal graphite tags response", "error", err, "body", string(body))
+		return nil, err
+	}
+
+	table := &tsdb.Table{
+		Columns: []tsdb.TableColumn{{Text: "text"}, {Text: "value"}},
+		Rows:    make([]tsdb.RowValues, 0, len(values)),
+	}
+	for _, v := range values {
+		table.Rows = append(table.Rows, tsdb.RowValues{v, v})
+	}
+
+	queryRes := tsdb.NewQueryResult()
+	queryRes.RefId = query.RefId
+	queryRes.Tables = append(queryRes.Tables, table)
+	queryRes.Meta = simplejson.New()
+	queryRes.Meta.Set("rowCount", len(values))
+
+	return &tsdb.Response{
+		Results: map[string]*tsdb.QueryResult{query.RefId: queryRes},
+	}, nil
+}
+
+// executeFunctions returns the definitions of the functions of Graphite 1.1+ in the
+// functions meta property of the result. Definitions are cached per data source.
+func (e *GraphiteExecutor) executeFunctions(ctx context.Context, dsInfo *models.DataSource, tsdbQuery *tsdb.TsdbQuery) (*tsdb.Response, error) {
+	query := tsdbQuery.Queries[0]
+
+	functions, err := e.getFunctions(ctx, dsInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	queryRes := tsdb.NewQueryResult()
+	queryRes.RefId = query.RefId
+	queryRes.Meta = simplejson.New()
+	queryRes.Meta.Set("functions", functions.Interface())
+
+	return &tsdb.Response{
+		Results: map[string]*tsdb.QueryResult{query.RefId: queryRes},
+	}, nil
+}
+
+func (e *GraphiteExecutor) getFunctions(ctx context.Context, dsInfo *models.DataSource) (*simplejson.Json, error) {
+	functionsCacheLock.Lock()
+	item, ok := functionsCache[dsInfo.Id]
+	functionsCacheLock.Unlock()
+
+	if ok && item.version == dsInfo.Version && time.Now().Before(item.expires) {
+		return item.functions, nil
+	}
+
+	req, err := e.createMetadataRequest(dsInfo, "functions", url.Values{})
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := e.doMetadataRequest(ctx, dsInfo, req)
+	if err != nil {
+		return nil, err
+	}
+
+	functions, err := parseFunctions(body)
+	if err != nil {
+		glog.Info("Failed to unmarshal graphite functions response", "error", err)
+		return nil, err
+	}
+
+	functionsCacheLock.Lock()
+	functionsCache[dsInfo.Id] = &functionsCacheItem{
+		version:   dsInfo.Version,
+		functions: functions,
+		expires:   time.Now().Add(functionsCacheTTL),
+	}
+	functionsCacheLock.Unlock()
+
+	return functions, nil
+}
+
+// parseFunctions parses the response of the functions API, infinite parameter
+// defaults are returned as the strings "Infinity" and "-Infinity".
+func parseFunctions(body []byte) (*simplejson.Json, error) {
+	body = infinityRegex.ReplaceAll(body, []byte(`"default": "${1}Infinity"`))
+
+	functions, err := simplejson.NewJson(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := functions.Map(); err != nil {
+		return nil, fmt.Errorf("unexpected graphite functions response")
+	}
+
+	return functions, nil
+}
@@ -0,0 +1,111 @@
+package graphite
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/tsdb"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGraphiteMetadata(t *testing.T) {
+	Convey("Graphite metadata queries", t, func() {
+		var requestPath string
+		var params url.Values
+		functionsRequests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestPath = r.URL.Path
+			params = r.URL.Query()
+			switch r.URL.Path {
+			case "/tags/autoComplete/tags":
+				_, _ = w.Write([]byte(`["dc", "host"]`))
+			case "/tags/autoComplete/values":
+				_, _ = w.Write([]byte(`["server-1", "server-2"]`))
+			case "/functions":
+				functionsRequests++
+				_, _ = w.Write([]byte(`{
+					"sumSeries": { "name": "sumSeries", "group": "Combine", "params": [] },
+					"maximumAbove": { "name": "maximumAbove", "params": [{ "name": "n", "default": Infinity }] }
+				}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		dsInfo := &models.DataSource{Id: 10, Version: 1, Url: server.URL, JsonData: simplejson.New()}
+		executor := &GraphiteExecutor{}
+		newQuery := func(model map[string]interface{}) *tsdb.TsdbQuery {
+			return &tsdb.TsdbQuery{
+				TimeRange: tsdb.NewTimeRange("6h", "now"),
+				Queries: []*tsdb.Query{
+					{RefId: "A", Model: simplejson.NewFromAny(model)},
+				},
+			}
+		}
+
+		Convey("should autocomplete tags", func() {
+			res, err := executor.Query(context.Background(), dsInfo, newQuery(map[string]interface{}{
+				"type":        "tagsAutoComplete",
+				"expressions": []interface{}{"name=cpu", "dc=us"},
+				"tagPrefix":   "h",
+				"limit":       10,
+			}))
+			So(err, ShouldBeNil)
+
+			So(requestPath, ShouldEqual, "/tags/autoComplete/tags")
+			So(params["expr"], ShouldResemble, []string{"name=cpu", "dc=us"})
+			So(params.Get("tagPrefix"), ShouldEqual, "h")
+			So(params.Get("limit"), ShouldEqual, "10")
+			So(params.Get("from"), ShouldEqual, "-6h")
+			So(params.Get("until"), ShouldEqual, "now")
+
+			queryRes := res.Results["A"]
+			So(queryRes.Tables, ShouldHaveLength, 1)
+			So(queryRes.Tables[0].Columns[0].Text, ShouldEqual, "text")
+			So(queryRes.Tables[0].Rows, ShouldResemble, []tsdb.RowValues{{"dc", "dc"}, {"host", "host"}})
+			So(queryRes.Meta.Get("rowCount").MustInt(), ShouldEqual, 2)
+		})
+
+		Convey("should autocomplete tag values", func() {
+			res, err := executor.Query(context.Background(), dsInfo, newQuery(map[string]interface{}{
+				"type":        "tagsAutoComplete",
+				"expressions": []interface{}{"name=cpu"},
+				"tag":         "host",
+				"valuePrefix": "server",
+			}))
+			So(err, ShouldBeNil)
+
+			So(requestPath, ShouldEqual, "/tags/autoComplete/values")
+			So(params.Get("tag"), ShouldEqual, "host")
+			So(params.Get("valuePrefix"), ShouldEqual, "server")
+			So(res.Results["A"].Tables[0].Rows, ShouldHaveLength, 2)
+		})
+
+		Convey("should return and cache the functions", func() {
+			functionsCache = make(map[int64]*functionsCacheItem)
+
+			res, err := executor.Query(context.Background(), dsInfo, newQuery(map[string]interface{}{"type": "functions"}))
+			So(err, ShouldBeNil)
+
+			functions := res.Results["A"].Meta.Get("functions")
+			So(functions.GetPath("sumSeries", "group").MustString(), ShouldEqual, "Combine")
+			param := simplejson.NewFromAny(functions.GetPath("maximumAbove", "params").MustArray()[0])
+			So(param.Get("default").MustString(), ShouldEqual, "Infinity")
+
+			_, err = executor.Query(context.Background(), dsInfo, newQuery(map[string]interface{}{"type": "functions"}))
+			So(err, ShouldBeNil)
+			So(functionsRequests, ShouldEqual, 1)
+
+			dsInfo.Version = 2
+			_, err = executor.Query(context.Background(), dsInfo, newQuery(map[string]interface{}{"type": "functions"}))
+			So(err, ShouldBeNil)
+			So(functionsRequests, ShouldEqual, 2)
+		})
+	})
+}
@@ -1,8 +1,30 @@
 package graphite
 
-import "github.com/grafana/grafana/pkg/tsdb"
+import (
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/tsdb"
+)
 
 type TargetResponseDTO struct {
-	Target     string                `json:"target"`
-	DataPoints tsdb.TimeSeriesPoints `json:"datapoints"`
+	Target     string                 `json:"target"`
+	DataPoints tsdb.TimeSeriesPoints  `json:"datapoints"`
+	Tags       map[string]interface{} `json:"tags"`
+}
+
+// getTags returns the tags of a series, Graphite returns the tags of series
+// queried with seriesByTag and the name of other series as the name tag.
+func (r *TargetResponseDTO) getTags() map[string]string {
+	tags := make(map[string]string, len(r.Tags))
+	for k, v := range r.Tags {
+		switch value := v.(type) {
+		case string:
+			tags[k] = value
+		case nil:
+			continue
+		default:
+			tags[k] = fmt.Sprintf("%v", value)
+		}
+	}
+	return tags
 }
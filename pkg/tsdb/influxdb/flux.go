@@ -0,0 +1,215 @@
+package influxdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb"
+	"golang.org/x/net/context/ctxhttp"
+)
+
+// fluxVersion is the value of jsonData `version` of data sources
+// querying InfluxDB 2.x with the Flux language.
+const fluxVersion = "Flux"
+
+var fluxVariable = regexp.MustCompile(`\bv\.(timeRangeStart|timeRangeStop|windowPeriod|defaultBucket|organization)\b`)
+
+// FluxQuery is a Flux query of a panel, with the variables
+// of the query replaced by the values of the request.
+type FluxQuery struct {
+	RefId string
+	Query string
+}
+
+func isFluxDataSource(dsInfo *models.DataSource) bool {
+	return dsInfo.JsonData != nil && dsInfo.JsonData.Get("version").MustString() == fluxVersion
+}
+
+// queryFlux executes every query of the request with the query API of InfluxDB 2.x. The
+// organization and default bucket are read from jsonData `organization` and `defaultBucket`
+// and the authentication token from secureJsonData `token`.
+func (e *InfluxDBExecutor) queryFlux(ctx context.Context, dsInfo *models.DataSource, tsdbQuery *tsdb.TsdbQuery) (*tsdb.Response, error) {
+	result := &tsdb.Response{
+		Results: make(map[string]*tsdb.QueryResult),
+	}
+
+	httpClient, err := dsInfo.GetHttpClient()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, q := range tsdbQuery.Queries {
+		query, err := parseFluxQuery(dsInfo, q, tsdbQuery)
+		if err != nil {
+			return nil, err
+		}
+
+		if setting.Env == setting.DEV {
+			glog.Debug("Flux query", "raw query", query.Query)
+		}
+
+		req, err := e.createFluxRequest(dsInfo, query.Query)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := ctxhttp.Do(ctx, httpClient, req)
+		if err != nil {
+			return nil, err
+		}
+
+		queryResult, err := parseFluxHttpResponse(resp)
+		if err != nil {
+			return nil, err
+		}
+
+		queryResult.RefId = query.RefId
+		result.Results[query.RefId] = queryResult
+	}
+
+	return result, nil
+}
+
+func parseFluxQuery(dsInfo *models.DataSource, query *tsdb.Query, tsdbQuery *tsdb.TsdbQuery) (*FluxQuery, error) {
+	text, err := query.Model.Get("query").String()
+	if err != nil || strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("Flux query %s is empty", query.RefId)
+	}
+
+	from, err := tsdbQuery.TimeRange.ParseFrom()
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := tsdbQuery.TimeRange.ParseTo()
+	if err != nil {
+		return nil, err
+	}
+
+	minInterval, err := tsdb.GetIntervalFrom(dsInfo, query.Model, time.Millisecond*1)
+	if err != nil {
+		return nil, err
+	}
+
+	calculator := tsdb.NewIntervalCalculator(&tsdb.IntervalOptions{})
+	interval := calculator.Calculate(tsdbQuery.TimeRange, minInterval)
+
+	return &FluxQuery{
+		RefId: query.RefId,
+		Query: interpolateFluxVariables(text, map[string]string{
+			"timeRangeStart": from.UTC().Format(time.RFC3339Nano),
+			"timeRangeStop":  to.UTC().Format(time.RFC3339Nano),
+			"windowPeriod":   formatFluxDuration(interval.Value),
+			"defaultBucket":  strconv.Quote(dsInfo.JsonData.Get("defaultBucket").MustString()),
+			"organization":   strconv.Quote(dsInfo.JsonData.Get("organization").MustString()),
+		}),
+	}, nil
+}
+
+// interpolateFluxVariables replaces the `v` record variables defined by
+// the InfluxDB UI, ex `range(start: v.timeRangeStart)`, with literals.
+func interpolateFluxVariables(query string, values map[string]string) string {
+	return fluxVariable.ReplaceAllStringFunc(query, func(in string) string {
+		return values[strings.TrimPrefix(in, "v.")]
+	})
+}
+
+// formatFluxDuration formats a duration as a Flux duration literal, ex 1500ms.
+func formatFluxDuration(d time.Duration) string {
+	ms := d.Nanoseconds() / int64(time.Millisecond)
+	if ms < 1 {
+		ms = 1
+	}
+
+	return strconv.FormatInt(ms, 10) + "ms"
+}
+
+func (e *InfluxDBExecutor) createFluxRequest(dsInfo *models.DataSource, query string) (*http.Request, error) {
+	u, err := url.Parse(dsInfo.Url)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, "api/v2/query")
+
+	params := url.Values{}
+	params.Set("org", dsInfo.JsonData.Get("organization").MustString())
+	u.RawQuery = params.Encode()
+
+	body, err := json.Marshal(map[string]interface{}{
+		"query": query,
+		"type":  "flux",
+		"dialect": map[string]interface{}{
+			"header":         true,
+			"delimiter":      ",",
+			"annotations":    []string{"datatype", "group", "default"},
+			"commentPrefix":  "#",
+			"dateTimeFormat": "RFC3339",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, u.String(), strings.NewReader(string(body)))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", "Grafana")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/csv")
+
+	if token, ok := dsInfo.DecryptedValue("token"); ok && token != "" {
+		req.Header.Set("Authorization", "Token "+token)
+	} else if dsInfo.BasicAuth {
+		req.SetBasicAuth(dsInfo.BasicAuthUser, dsInfo.DecryptedBasicAuthPassword())
+	}
+
+	glog.Debug("Flux request", "url", req.URL.String())
+	return req, nil
+}
+
+func parseFluxHttpResponse(resp *http.Response) (*tsdb.QueryResult, error) {
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := ioutil.ReadAll(resp.Body)
+
+		// errors of the query API are returned as json, ex {"code":"invalid","message":"..."}
+		var apiError struct {
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(body, &apiError); err == nil && apiError.Message != "" {
+			return nil, fmt.Errorf("InfluxDB returned error: %s", apiError.Message)
+		}
+
+		return nil, fmt.Errorf("Influxdb returned statuscode invalid status code: %v", resp.Status)
+	}
+
+	frames, err := parseFluxResponse(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	queryResult := tsdb.NewQueryResult()
+	for _, frame := range frames {
+		encoded, err := frame.MarshalArrow()
+		if err != nil {
+			return nil, err
+		}
+		queryResult.Dataframes = append(queryResult.Dataframes, encoded)
+	}
+
+	return queryResult, nil
+}
@@ -0,0 +1,274 @@
+package influxdb
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// fluxColumn is a column of a table in an annotated CSV response,
+// described by the #datatype, #group and #default annotations.
+type fluxColumn struct {
+	name         string
+	dataType     string
+	group        bool
+	defaultValue string
+}
+
+// fluxTable collects the rows of one table of the response.
+type fluxTable struct {
+	result  string
+	id      string
+	columns []*fluxColumn
+	rows    [][]string
+}
+
+// parseFluxResponse parses an annotated CSV response of the InfluxDB query API
+// into one data frame per table. The string columns of the group key become the
+// labels of the value fields, the _start and _stop columns of the group key are dropped.
+//
+// See https://v2.docs.influxdata.com/v2.0/reference/syntax/annotated-csv/
+func parseFluxResponse(r io.Reader) ([]*data.Frame, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	var (
+		tables      []*fluxTable
+		current     *fluxTable
+		columns     []*fluxColumn
+		annotations = map[string][]string{}
+		inHeader    = true
+	)
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse Flux response: %v", err)
+		}
+
+		// annotations start a new block of tables with different columns
+		if strings.HasPrefix(record[0], "#") {
+			if !inHeader {
+				annotations = map[string][]string{}
+				columns = nil
+				current = nil
+				inHeader = true
+			}
+			annotations[record[0]] = record
+			continue
+		}
+
+		if inHeader {
+			columns = newFluxColumns(record, annotations)
+			inHeader = false
+			continue
+		}
+
+		if len(record) != len(columns) {
+			return nil, fmt.Errorf("Failed to parse Flux response: row has %d columns, expected %d", len(record), len(columns))
+		}
+
+		if err := fluxRowError(columns, record); err != nil {
+			return nil, err
+		}
+
+		result, table := fluxRowValue(columns, record, "result"), fluxRowValue(columns, record, "table")
+		if current == nil || current.result != result || current.id != table {
+			current = &fluxTable{result: result, id: table, columns: columns}
+			tables = append(tables, current)
+		}
+		current.rows = append(current.rows, record)
+	}
+
+	frames := make([]*data.Frame, 0, len(tables))
+	for _, table := range tables {
+		frame, err := table.toFrame()
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, frame)
+	}
+
+	return frames, nil
+}
+
+func newFluxColumns(header []string, annotations map[string][]string) []*fluxColumn {
+	columns := make([]*fluxColumn, len(header))
+	for i, name := range header {
+		columns[i] = &fluxColumn{
+			name:         name,
+			dataType:     annotationValue(annotations["#datatype"], i),
+			group:        annotationValue(annotations["#group"], i) == "true",
+			defaultValue: annotationValue(annotations["#default"], i),
+		}
+	}
+
+	return columns
+}
+
+func annotationValue(annotation []string, index int) string {
+	if index < len(annotation) {
+		return annotation[index]
+	}
+	return ""
+}
+
+// fluxRowValue returns the value of a column of a row, or the default value of the column.
+func fluxRowValue(columns []*fluxColumn, record []string, name string) string {
+	for i, column := range columns {
+		if column.name == name {
+			if record[i] == "" {
+				return column.defaultValue
+			}
+			return record[i]
+		}
+	}
+
+	return ""
+}
+
+// fluxRowError returns the error of the query if the row is part of an error table.
+// Queries failing after the response started are reported with a table like
+// `,error,reference` followed by the error message.
+func fluxRowError(columns []*fluxColumn, record []string) error {
+	if len(columns) < 2 || columns[1].name != "error" {
+		return nil
+	}
+
+	message := record[1]
+	if message == "" {
+		message = "unknown error"
+	}
+
+	return fmt.Errorf("InfluxDB returned error: %s", message)
+}
+
+func (t *fluxTable) toFrame() (*data.Frame, error) {
+	labels := data.Labels{}
+	for i, column := range t.columns {
+		if !column.group || column.name == "_start" || column.name == "_stop" || column.dataType != "string" {
+			continue
+		}
+		labels[column.name] = t.value(0, i)
+	}
+
+	name := t.result
+	if measurement, ok := labels["_measurement"]; ok {
+		name = measurement
+	}
+
+	frame := data.NewFrame(name)
+	for i, column := range t.columns {
+		if i == 0 || column.name == "result" || column.name == "table" || column.group {
+			continue
+		}
+
+		field, err := t.newField(i, column)
+		if err != nil {
+			return nil, err
+		}
+
+		if column.name != "_time" {
+			field.Labels = labels.Copy()
+			if column.name == "_value" && labels["_field"] != "" {
+				field.Name = labels["_field"]
+			}
+		}
+
+		frame.Fields = append(frame.Fields, field)
+	}
+
+	return frame, nil
+}
+
+// value returns the value of a cell of the table, or the default value of the column.
+func (t *fluxTable) value(row int, col int) string {
+	if value := t.rows[row][col]; value != "" {
+		return value
+	}
+	return t.columns[col].defaultValue
+}
+
+func (t *fluxTable) newField(col int, column *fluxColumn) (*data.Field, error) {
+	switch column.dataType {
+	case "double":
+		values := make([]*float64, len(t.rows))
+		for row := range t.rows {
+			if text := t.value(row, col); text != "" {
+				value, err := strconv.ParseFloat(text, 64)
+				if err != nil {
+					return nil, fluxValueError(column, text)
+				}
+				values[row] = &value
+			}
+		}
+		return data.NewField(column.name, nil, values), nil
+	case "long":
+		values := make([]*int64, len(t.rows))
+		for row := range t.rows {
+			if text := t.value(row, col); text != "" {
+				value, err := strconv.ParseInt(text, 10, 64)
+				if err != nil {
+					return nil, fluxValueError(column, text)
+				}
+				values[row] = &value
+			}
+		}
+		return data.NewField(column.name, nil, values), nil
+	case "unsignedLong":
+		values := make([]*uint64, len(t.rows))
+		for row := range t.rows {
+			if text := t.value(row, col); text != "" {
+				value, err := strconv.ParseUint(text, 10, 64)
+				if err != nil {
+					return nil, fluxValueError(column, text)
+				}
+				values[row] = &value
+			}
+		}
+		return data.NewField(column.name, nil, values), nil
+	case "boolean":
+		values := make([]*bool, len(t.rows))
+		for row := range t.rows {
+			if text := t.value(row, col); text != "" {
+				value, err := strconv.ParseBool(text)
+				if err != nil {
+					return nil, fluxValueError(column, text)
+				}
+				values[row] = &value
+			}
+		}
+		return data.NewField(column.name, nil, values), nil
+	case "dateTime:RFC3339", "dateTime:RFC3339Nano":
+		values := make([]*time.Time, len(t.rows))
+		for row := range t.rows {
+			if text := t.value(row, col); text != "" {
+				value, err := time.Parse(time.RFC3339Nano, text)
+				if err != nil {
+					return nil, fluxValueError(column, text)
+				}
+				values[row] = &value
+			}
+		}
+		return data.NewField(column.name, nil, values), nil
+	default:
+		values := make([]*string, len(t.rows))
+		for row := range t.rows {
+			value := t.value(row, col)
+			values[row] = &value
+		}
+		return data.NewField(column.name, nil, values), nil
+	}
+}
+
+func fluxValueError(column *fluxColumn, text string) error {
+	return fmt.Errorf("Failed to parse Flux response: invalid %s value %q in column %s", column.dataType, text, column.name)
+}
@@ -0,0 +1,176 @@
+package influxdb
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/components/securejsondata"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/tsdb"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFlux(t *testing.T) {
+	Convey("InfluxDB Flux", t, func() {
+		dsInfo := &models.DataSource{
+			Id:      1,
+			Version: 1,
+			JsonData: simplejson.NewFromAny(map[string]interface{}{
+				"version":       "Flux",
+				"organization":  "grafana",
+				"defaultBucket": "telegraf",
+			}),
+			SecureJsonData: securejsondata.GetEncryptedJsonData(map[string]string{"token": "secret-token"}),
+		}
+
+		tsdbQuery := &tsdb.TsdbQuery{
+			TimeRange: tsdb.NewTimeRange("1581897600000", "1581901200000"),
+		}
+
+		Convey("should select Flux by the data source version", func() {
+			So(isFluxDataSource(dsInfo), ShouldBeTrue)
+			So(isFluxDataSource(&models.DataSource{JsonData: simplejson.New()}), ShouldBeFalse)
+		})
+
+		Convey("should replace variables with values of the request", func() {
+			query, err := parseFluxQuery(dsInfo, &tsdb.Query{
+				RefId: "A",
+				Model: simplejson.NewFromAny(map[string]interface{}{
+					"query": `from(bucket: v.defaultBucket) |> range(start: v.timeRangeStart, stop: v.timeRangeStop) |> aggregateWindow(every: v.windowPeriod, fn: mean) |> filter(fn: (r) => r.v.timeRangeStartX == 1)`,
+				}),
+			}, tsdbQuery)
+			So(err, ShouldBeNil)
+			So(query.RefId, ShouldEqual, "A")
+			So(query.Query, ShouldEqual, `from(bucket: "telegraf") |> range(start: 2020-02-17T00:00:00Z, stop: 2020-02-17T01:00:00Z) |> aggregateWindow(every: 2000ms, fn: mean) |> filter(fn: (r) => r.v.timeRangeStartX == 1)`)
+		})
+
+		Convey("should use the minimum interval for the window period", func() {
+			query, err := parseFluxQuery(dsInfo, &tsdb.Query{
+				RefId: "A",
+				Model: simplejson.NewFromAny(map[string]interface{}{
+					"query":    `aggregateWindow(every: v.windowPeriod, fn: mean)`,
+					"interval": "1m",
+				}),
+			}, tsdbQuery)
+			So(err, ShouldBeNil)
+			So(query.Query, ShouldEqual, `aggregateWindow(every: 60000ms, fn: mean)`)
+		})
+
+		Convey("should fail on empty queries", func() {
+			_, err := parseFluxQuery(dsInfo, &tsdb.Query{RefId: "A", Model: simplejson.New()}, tsdbQuery)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("should parse annotated csv into frames", func() {
+			frames := readFluxResponse("cpu.csv")
+			So(frames, ShouldHaveLength, 3)
+
+			frame := frames[0]
+			So(frame.Name, ShouldEqual, "cpu")
+			So(frame.Fields, ShouldHaveLength, 2)
+			So(frame.Fields[0].Name, ShouldEqual, "_time")
+			So(frame.Fields[0].Len(), ShouldEqual, 3)
+			So(*frame.Fields[0].At(0).(*time.Time), ShouldEqual, time.Date(2020, 2, 17, 0, 1, 0, 0, time.UTC))
+			So(frame.Fields[1].Name, ShouldEqual, "usage_idle")
+			So(frame.Fields[1].Labels, ShouldResemble, data.Labels{"_field": "usage_idle", "_measurement": "cpu", "host": "server-a"})
+			So(*frame.Fields[1].At(0).(*float64), ShouldEqual, 95.5)
+			So(frame.Fields[1].At(2).(*float64), ShouldBeNil)
+
+			So(frames[1].Fields[1].Labels["host"], ShouldEqual, "server-b")
+			So(frames[1].Fields[1].Len(), ShouldEqual, 2)
+
+			So(frames[2].Name, ShouldEqual, "system")
+			So(*frames[2].Fields[1].At(0).(*int64), ShouldEqual, 12)
+		})
+
+		Convey("should convert frames to time series for alerting", func() {
+			frames := readFluxResponse("cpu.csv")
+
+			series, err := tsdb.FrameToSeriesSlice(frames[0])
+			So(err, ShouldBeNil)
+			So(series, ShouldHaveLength, 1)
+			So(series[0].Name, ShouldEqual, "usage_idle")
+			So(series[0].Tags["host"], ShouldEqual, "server-a")
+			So(series[0].Points[1][0].Float64, ShouldEqual, 94)
+			So(series[0].Points[1][1].Float64, ShouldEqual, 1581897720000)
+		})
+
+		Convey("should return errors of error tables", func() {
+			file, err := os.Open(filepath.Join("testdata", "error.csv"))
+			So(err, ShouldBeNil)
+			defer file.Close()
+
+			_, err = parseFluxResponse(file)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "index out of range")
+		})
+
+		Convey("should execute queries with the query api", func() {
+			var request *http.Request
+			var body map[string]interface{}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				request = r
+				content, _ := ioutil.ReadAll(r.Body)
+				_ = json.Unmarshal(content, &body)
+
+				if body["query"] == "invalid" {
+					w.WriteHeader(http.StatusBadRequest)
+					_, _ = w.Write([]byte(`{"code":"invalid","message":"compilation failed: error at @1:1-1:8: undefined identifier invalid"}`))
+					return
+				}
+
+				csv, _ := ioutil.ReadFile(filepath.Join("testdata", "cpu.csv"))
+				w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+				_, _ = w.Write(csv)
+			}))
+			defer server.Close()
+
+			dsInfo.Url = server.URL
+			tsdbQuery.Queries = []*tsdb.Query{
+				{RefId: "B", Model: simplejson.NewFromAny(map[string]interface{}{"query": `from(bucket: v.defaultBucket)`})},
+			}
+
+			executor := &InfluxDBExecutor{QueryParser: &InfluxdbQueryParser{}, ResponseParser: &ResponseParser{}}
+			res, err := executor.Query(context.Background(), dsInfo, tsdbQuery)
+			So(err, ShouldBeNil)
+
+			So(request.Method, ShouldEqual, http.MethodPost)
+			So(request.URL.Path, ShouldEqual, "/api/v2/query")
+			So(request.URL.Query().Get("org"), ShouldEqual, "grafana")
+			So(request.Header.Get("Authorization"), ShouldEqual, "Token secret-token")
+			So(body["query"], ShouldEqual, `from(bucket: "telegraf")`)
+
+			So(res.Results["B"].RefId, ShouldEqual, "B")
+			frames, err := data.UnmarshalArrowFrames(res.Results["B"].Dataframes)
+			So(err, ShouldBeNil)
+			So(frames, ShouldHaveLength, 3)
+
+			Convey("should return errors of the query api", func() {
+				tsdbQuery.Queries[0].Model.Set("query", "invalid")
+				_, err := executor.Query(context.Background(), dsInfo, tsdbQuery)
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "undefined identifier invalid")
+			})
+		})
+	})
+}
+
+func readFluxResponse(name string) []*data.Frame {
+	file, err := os.Open(filepath.Join("testdata", name))
+	So(err, ShouldBeNil)
+	defer file.Close()
+
+	frames, err := parseFluxResponse(file)
+	So(err, ShouldBeNil)
+	return frames
+}
@@ -43,6 +43,10 @@ func init() {
 }
 
 func (e *InfluxDBExecutor) Query(ctx context.Context, dsInfo *models.DataSource, tsdbQuery *tsdb.TsdbQuery) (*tsdb.Response, error) {
+	if isFluxDataSource(dsInfo) {
+		return e.queryFlux(ctx, dsInfo, tsdbQuery)
+	}
+
 	result := &tsdb.Response{}
 
 	query, err := e.getQuery(dsInfo, tsdbQuery.Queries, tsdbQuery)
@@ -0,0 +1,288 @@
+package loki
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"golang.org/x/net/context/ctxhttp"
+
+	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/tsdb"
+)
+
+const (
+	// Loki rejects queries that might return more than 11000 points per series
+	maxPointsPerSeries = 11000
+
+	resultTypeMatrix = "matrix"
+)
+
+type LokiExecutor struct {
+}
+
+func NewLokiExecutor(dsInfo *models.DataSource) (tsdb.TsdbQueryEndpoint, error) {
+	return &LokiExecutor{}, nil
+}
+
+var (
+	plog               log.Logger
+	legendFormat       *regexp.Regexp
+	intervalCalculator tsdb.IntervalCalculator
+)
+
+func init() {
+	plog = log.New("tsdb.loki")
+	tsdb.RegisterTsdbQueryEndpoint("loki", NewLokiExecutor)
+	legendFormat = regexp.MustCompile(`\{\{\s*(.+?)\s*\}\}`)
+	intervalCalculator = tsdb.NewIntervalCalculator(&tsdb.IntervalOptions{MinInterval: time.Second * 1})
+}
+
+// Query executes LogQL metric queries, ex `rate({job="app"}[5m])`, against the
+// query_range API of Loki. Log queries are not supported since their streams
+// of log lines cannot be converted into time series.
+func (e *LokiExecutor) Query(ctx context.Context, dsInfo *models.DataSource, tsdbQuery *tsdb.TsdbQuery) (*tsdb.Response, error) {
+	result := &tsdb.Response{
+		Results: map[string]*tsdb.QueryResult{},
+	}
+
+	httpClient, err := dsInfo.GetHttpClient()
+	if err != nil {
+		return nil, err
+	}
+
+	queries, err := parseQuery(dsInfo, tsdbQuery.Queries, tsdbQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, query := range queries {
+		plog.Debug("Sending query", "start", query.Start, "end", query.End, "step", query.Step, "query", query.Expr)
+
+		span, ctx := opentracing.StartSpanFromContext(ctx, "alerting.loki")
+		span.SetTag("expr", query.Expr)
+		span.SetTag("start_unixnano", query.Start.UnixNano())
+		span.SetTag("stop_unixnano", query.End.UnixNano())
+		defer span.Finish()
+
+		req, err := createRequest(dsInfo, query)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := ctxhttp.Do(ctx, httpClient, req)
+		if err != nil {
+			return nil, err
+		}
+
+		queryResult, err := parseResponse(res, query)
+		if err != nil {
+			return nil, err
+		}
+		queryResult.RefId = query.RefId
+		result.Results[query.RefId] = queryResult
+	}
+
+	return result, nil
+}
+
+func parseQuery(dsInfo *models.DataSource, queries []*tsdb.Query, queryContext *tsdb.TsdbQuery) ([]*LokiQuery, error) {
+	qs := []*LokiQuery{}
+	for _, queryModel := range queries {
+		expr, err := queryModel.Model.Get("expr").String()
+		if err != nil {
+			return nil, err
+		}
+
+		format := queryModel.Model.Get("legendFormat").MustString("")
+
+		start, err := queryContext.TimeRange.ParseFrom()
+		if err != nil {
+			return nil, err
+		}
+
+		end, err := queryContext.TimeRange.ParseTo()
+		if err != nil {
+			return nil, err
+		}
+
+		dsInterval, err := tsdb.GetIntervalFrom(dsInfo, queryModel.Model, time.Second*1)
+		if err != nil {
+			return nil, err
+		}
+
+		resolution := queryModel.Model.Get("resolution").MustInt64(1)
+		interval := intervalCalculator.Calculate(queryContext.TimeRange, dsInterval)
+
+		qs = append(qs, &LokiQuery{
+			Expr:         expr,
+			Step:         calculateStep(time.Duration(int64(interval.Value)*resolution), end.Sub(start)),
+			LegendFormat: format,
+			Start:        start,
+			End:          end,
+			RefId:        queryModel.RefId,
+		})
+	}
+
+	return qs, nil
+}
+
+// calculateStep returns the interval in whole seconds, increased when
+// the time range would have more points than Loki allows.
+func calculateStep(interval time.Duration, timeRange time.Duration) time.Duration {
+	if minInterval := timeRange / maxPointsPerSeries; interval < minInterval {
+		interval = minInterval
+	}
+
+	seconds := math.Ceil(interval.Seconds())
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+func createRequest(dsInfo *models.DataSource, query *LokiQuery) (*http.Request, error) {
+	u, err := url.Parse(dsInfo.Url)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, "loki/api/v1/query_range")
+
+	params := url.Values{}
+	params.Set("query", query.Expr)
+	params.Set("start", strconv.FormatInt(query.Start.UnixNano(), 10))
+	params.Set("end", strconv.FormatInt(query.End.UnixNano(), 10))
+	params.Set("step", strconv.FormatInt(int64(query.Step/time.Second), 10))
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		plog.Info("Failed to create request", "error", err)
+		return nil, fmt.Errorf("Failed to create request. error: %v", err)
+	}
+
+	if dsInfo.BasicAuth {
+		req.SetBasicAuth(dsInfo.BasicAuthUser, dsInfo.DecryptedBasicAuthPassword())
+	}
+
+	return req, nil
+}
+
+func parseResponse(res *http.Response, query *LokiQuery) (*tsdb.QueryResult, error) {
+	body, err := ioutil.ReadAll(res.Body)
+	defer res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var data lokiResponse
+	if err := json.Unmarshal(body, &data); err != nil && res.StatusCode/100 == 2 {
+		plog.Info("Failed to unmarshal loki response", "error", err, "status", res.Status, "body", string(body))
+		return nil, err
+	}
+
+	if res.StatusCode/100 != 2 {
+		plog.Info("Request failed", "status", res.Status, "body", string(body))
+		if data.Error != "" {
+			return nil, fmt.Errorf("Request failed status: %v, error: %s", res.Status, data.Error)
+		}
+		return nil, fmt.Errorf("Request failed status: %v, error: %s", res.Status, strings.TrimSpace(string(body)))
+	}
+
+	if data.Data.ResultType != resultTypeMatrix {
+		return nil, fmt.Errorf("Unsupported result format: %s, only metric queries are supported", data.Data.ResultType)
+	}
+
+	var matrix lokiMatrix
+	if err := json.Unmarshal(data.Data.Result, &matrix); err != nil {
+		return nil, err
+	}
+
+	queryRes := tsdb.NewQueryResult()
+	for _, v := range matrix {
+		series := tsdb.TimeSeries{
+			Name:   formatLegend(v.Metric, query),
+			Tags:   make(map[string]string, len(v.Metric)),
+			Points: make([]tsdb.TimePoint, 0, len(v.Values)),
+		}
+
+		for k, v := range v.Metric {
+			series.Tags[k] = v
+		}
+
+		for _, pair := range v.Values {
+			point, err := parseSamplePair(pair)
+			if err != nil {
+				return nil, err
+			}
+			series.Points = append(series.Points, point)
+		}
+
+		queryRes.Series = append(queryRes.Series, &series)
+	}
+
+	return queryRes, nil
+}
+
+// parseSamplePair parses a value of a matrix, ex [1580000000.5, "2.1"].
+func parseSamplePair(pair [2]json.RawMessage) (tsdb.TimePoint, error) {
+	var timestamp float64
+	if err := json.Unmarshal(pair[0], &timestamp); err != nil {
+		return tsdb.TimePoint{}, fmt.Errorf("Invalid sample timestamp %s", string(pair[0]))
+	}
+
+	var text string
+	if err := json.Unmarshal(pair[1], &text); err != nil {
+		return tsdb.TimePoint{}, fmt.Errorf("Invalid sample value %s", string(pair[1]))
+	}
+
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return tsdb.TimePoint{}, fmt.Errorf("Invalid sample value %q", text)
+	}
+
+	return tsdb.NewTimePoint(null.FloatFrom(value), math.Round(timestamp*1000)), nil
+}
+
+func formatLegend(labels map[string]string, query *LokiQuery) string {
+	if query.LegendFormat == "" {
+		return formatLabels(labels)
+	}
+
+	result := legendFormat.ReplaceAllStringFunc(query.LegendFormat, func(in string) string {
+		labelName := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(in, "{{"), "}}"))
+		return labels[labelName]
+	})
+
+	return result
+}
+
+// formatLabels formats labels the way Loki and Prometheus do, ex {job="app", level="error"}.
+func formatLabels(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for k := range labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, k := range names {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", k, labels[k]))
+	}
+
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
@@ -0,0 +1,150 @@
+package loki
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/tsdb"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLoki(t *testing.T) {
+	Convey("Loki", t, func() {
+		Convey("parsing query model", func() {
+			dsInfo := &models.DataSource{JsonData: simplejson.New()}
+			queryContext := &tsdb.TsdbQuery{
+				TimeRange: tsdb.NewTimeRange("1580000000000", "1580003600000"),
+			}
+
+			Convey("should parse the expression, legend and time range", func() {
+				queries, err := parseQuery(dsInfo, []*tsdb.Query{{RefId: "A", Model: queryModel(`{
+					"expr": "rate({job=\"grafana\"}[5m])",
+					"legendFormat": "{{level}}",
+					"interval": "30s"
+				}`)}}, queryContext)
+				So(err, ShouldBeNil)
+				So(queries, ShouldHaveLength, 1)
+				So(queries[0].RefId, ShouldEqual, "A")
+				So(queries[0].Expr, ShouldEqual, `rate({job="grafana"}[5m])`)
+				So(queries[0].LegendFormat, ShouldEqual, "{{level}}")
+				So(queries[0].Start.Unix(), ShouldEqual, 1580000000)
+				So(queries[0].End.Unix(), ShouldEqual, 1580003600)
+				So(queries[0].Step, ShouldEqual, 30*time.Second)
+			})
+
+			Convey("should fail without expression", func() {
+				_, err := parseQuery(dsInfo, []*tsdb.Query{{RefId: "A", Model: queryModel(`{}`)}}, queryContext)
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("calculating step", func() {
+			So(calculateStep(500*time.Millisecond, time.Hour), ShouldEqual, time.Second)
+			So(calculateStep(1500*time.Millisecond, time.Hour), ShouldEqual, 2*time.Second)
+			So(calculateStep(time.Second, 30*24*time.Hour), ShouldEqual, 236*time.Second)
+		})
+
+		Convey("formatting legend", func() {
+			labels := map[string]string{"job": "grafana", "level": "error"}
+
+			So(formatLegend(labels, &LokiQuery{}), ShouldEqual, `{job="grafana", level="error"}`)
+			So(formatLegend(labels, &LokiQuery{LegendFormat: "{{job}} {{ level }} {{missing}}"}), ShouldEqual, "grafana error ")
+		})
+
+		Convey("executing queries against recorded responses", func() {
+			var requests []*http.Request
+			response := "matrix.json"
+			status := http.StatusOK
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r)
+				if status != http.StatusOK {
+					w.WriteHeader(status)
+					_, _ = w.Write([]byte("parse error at line 1, col 6: syntax error: unexpected IDENTIFIER\n"))
+					return
+				}
+
+				body, err := ioutil.ReadFile(filepath.Join("testdata", response))
+				if err != nil {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write(body)
+			}))
+			defer server.Close()
+
+			dsInfo := &models.DataSource{
+				Id:       1,
+				Url:      server.URL,
+				JsonData: simplejson.New(),
+			}
+
+			executor, err := NewLokiExecutor(dsInfo)
+			So(err, ShouldBeNil)
+
+			query := &tsdb.TsdbQuery{
+				TimeRange: tsdb.NewTimeRange("1580000000000", "1580000180000"),
+				Queries: []*tsdb.Query{
+					{RefId: "A", Model: queryModel(`{"expr": "sum by (level) (count_over_time({job=\"grafana\"}[1m]))", "interval": "1m"}`)},
+				},
+			}
+
+			Convey("should convert matrix results to time series", func() {
+				res, err := executor.Query(context.Background(), dsInfo, query)
+				So(err, ShouldBeNil)
+
+				So(requests, ShouldHaveLength, 1)
+				So(requests[0].URL.Path, ShouldEqual, "/loki/api/v1/query_range")
+				So(requests[0].URL.Query(), ShouldResemble, url.Values{
+					"query": []string{`sum by (level) (count_over_time({job="grafana"}[1m]))`},
+					"start": []string{"1580000000000000000"},
+					"end":   []string{"1580000180000000000"},
+					"step":  []string{"60"},
+				})
+
+				queryRes := res.Results["A"]
+				So(queryRes.RefId, ShouldEqual, "A")
+				So(queryRes.Series, ShouldHaveLength, 2)
+
+				series := queryRes.Series[0]
+				So(series.Name, ShouldEqual, `{job="grafana", level="error"}`)
+				So(series.Tags, ShouldResemble, map[string]string{"job": "grafana", "level": "error"})
+				So(series.Points, ShouldHaveLength, 3)
+				So(series.Points[0][0].Float64, ShouldEqual, 0.5)
+				So(series.Points[0][1].Float64, ShouldEqual, 1580000000000)
+				So(series.Points[1][0].Float64, ShouldEqual, 1.25)
+
+				So(queryRes.Series[1].Points[1][1].Float64, ShouldEqual, 1580000060500)
+			})
+
+			Convey("should fail for log queries", func() {
+				response = "streams.json"
+				_, err := executor.Query(context.Background(), dsInfo, query)
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "only metric queries are supported")
+			})
+
+			Convey("should return the error of failed queries", func() {
+				status = http.StatusBadRequest
+				_, err := executor.Query(context.Background(), dsInfo, query)
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "syntax error")
+			})
+		})
+	})
+}
+
+func queryModel(model string) *simplejson.Json {
+	json, err := simplejson.NewJson([]byte(model))
+	So(err, ShouldBeNil)
+	return json
+}
@@ -0,0 +1,34 @@
+package loki
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type LokiQuery struct {
+	Expr         string
+	Step         time.Duration
+	LegendFormat string
+	Start        time.Time
+	End          time.Time
+	RefId        string
+}
+
+type lokiResponse struct {
+	Status    string   `json:"status"`
+	Data      lokiData `json:"data"`
+	ErrorType string   `json:"errorType"`
+	Error     string   `json:"error"`
+}
+
+type lokiData struct {
+	ResultType string          `json:"resultType"`
+	Result     json.RawMessage `json:"result"`
+}
+
+// lokiMatrix is the result of metric queries, ex `rate({job="app"}[5m])`.
+// Values are pairs of a timestamp in seconds and the value as a string.
+type lokiMatrix []struct {
+	Metric map[string]string    `json:"metric"`
+	Values [][2]json.RawMessage `json:"values"`
+}
@@ -3,7 +3,9 @@ package prometheus
 import (
 	"context"
 	"fmt"
+	"math"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -12,6 +14,7 @@ import (
 	"net/http"
 
 	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/tsdb"
@@ -47,6 +50,17 @@ func NewPrometheusExecutor(dsInfo *models.DataSource) (tsdb.TsdbQueryEndpoint, e
 	}, nil
 }
 
+const (
+	// Prometheus rejects range queries returning more than 11000 points per series
+	maxPointsPerSeries = 11000
+
+	rangeQueryType   = "range"
+	instantQueryType = "instant"
+
+	timeSeriesFormat = "time_series"
+	tableFormat      = "table"
+)
+
 var (
 	plog               log.Logger
 	legendFormat       *regexp.Regexp
@@ -98,13 +112,7 @@ func (e *PrometheusExecutor) Query(ctx context.Context, dsInfo *models.DataSourc
 	}
 
 	for _, query := range queries {
-		timeRange := apiv1.Range{
-			Start: query.Start,
-			End:   query.End,
-			Step:  query.Step,
-		}
-
-		plog.Debug("Sending query", "start", timeRange.Start, "end", timeRange.End, "step", timeRange.Step, "query", query.Expr)
+		plog.Debug("Sending query", "start", query.Start, "end", query.End, "step", query.Step, "instant", query.Instant, "query", query.Expr)
 
 		span, ctx := opentracing.StartSpanFromContext(ctx, "alerting.prometheus")
 		span.SetTag("expr", query.Expr)
@@ -112,7 +120,17 @@ func (e *PrometheusExecutor) Query(ctx context.Context, dsInfo *models.DataSourc
 		span.SetTag("stop_unixnano", query.End.UnixNano())
 		defer span.Finish()
 
-		value, _, err := client.QueryRange(ctx, query.Expr, timeRange)
+		var value model.Value
+		var warnings apiv1.Warnings
+		if query.Instant {
+			value, warnings, err = client.Query(ctx, query.Expr, query.End)
+		} else {
+			value, warnings, err = client.QueryRange(ctx, query.Expr, apiv1.Range{
+				Start: query.Start,
+				End:   query.End,
+				Step:  query.Step,
+			})
+		}
 
 		if err != nil {
 			return nil, err
@@ -122,6 +140,8 @@ func (e *PrometheusExecutor) Query(ctx context.Context, dsInfo *models.DataSourc
 		if err != nil {
 			return nil, err
 		}
+		queryResult.RefId = query.RefId
+		queryResult.Meta = queryMeta(query, warnings)
 		result.Results[query.RefId] = queryResult
 	}
 
@@ -176,43 +196,213 @@ func parseQuery(dsInfo *models.DataSource, queries []*tsdb.Query, queryContext *
 		step := time.Duration(int64(interval.Value) * intervalFactor)
 
 		qs = append(qs, &PrometheusQuery{
-			Expr:         expr,
-			Step:         step,
-			LegendFormat: format,
-			Start:        start,
-			End:          end,
-			RefId:        queryModel.RefId,
+			Expr:          expr,
+			Step:          adjustStep(step, end.Sub(start)),
+			RequestedStep: step,
+			LegendFormat:  format,
+			Start:         start,
+			End:           end,
+			RefId:         queryModel.RefId,
+			Instant:       queryModel.QueryType == instantQueryType || queryModel.Model.Get("instant").MustBool(false),
+			Format:        queryModel.Model.Get("format").MustString(timeSeriesFormat),
 		})
 	}
 
 	return qs, nil
 }
 
+// adjustStep increases the step when the time range would have more
+// points per series than Prometheus allows.
+func adjustStep(step time.Duration, timeRange time.Duration) time.Duration {
+	minStep := time.Duration(math.Ceil((timeRange / maxPointsPerSeries).Seconds())) * time.Second
+	if step < minStep {
+		return minStep
+	}
+
+	return step
+}
+
+// queryMeta returns the warnings of Prometheus and how the query was executed.
+func queryMeta(query *PrometheusQuery, warnings apiv1.Warnings) *simplejson.Json {
+	meta := simplejson.New()
+	meta.Set("executedQueryString", query.Expr)
+
+	if query.Instant {
+		meta.Set("queryType", instantQueryType)
+		meta.Set("time", query.End.Unix())
+	} else {
+		meta.Set("queryType", rangeQueryType)
+		meta.Set("step", query.Step.Seconds())
+		if query.Step != query.RequestedStep {
+			meta.Set("requestedStep", query.RequestedStep.Seconds())
+			meta.Set("stepAdjusted", true)
+		}
+	}
+
+	if len(warnings) > 0 {
+		meta.Set("warnings", []string(warnings))
+	}
+
+	return meta
+}
+
 func parseResponse(value model.Value, query *PrometheusQuery) (*tsdb.QueryResult, error) {
 	queryRes := tsdb.NewQueryResult()
 
-	data, ok := value.(model.Matrix)
-	if !ok {
+	switch data := value.(type) {
+	case model.Matrix:
+		if query.Format == tableFormat {
+			queryRes.Tables = append(queryRes.Tables, matrixToTable(data))
+			break
+		}
+		queryRes.Series = matrixToSeries(data, query)
+	case model.Vector:
+		if query.Format == tableFormat {
+			queryRes.Tables = append(queryRes.Tables, vectorToTable(data))
+			break
+		}
+		queryRes.Series = vectorToSeries(data, query)
+	case *model.Scalar:
+		if query.Format == tableFormat {
+			queryRes.Tables = append(queryRes.Tables, &tsdb.Table{
+				Columns: []tsdb.TableColumn{{Text: "Time"}, {Text: "Value"}},
+				Rows:    []tsdb.RowValues{{float64(data.Timestamp), float64(data.Value)}},
+			})
+			break
+		}
+		queryRes.Series = tsdb.TimeSeriesSlice{{
+			Name:   "scalar",
+			Tags:   map[string]string{},
+			Points: tsdb.TimeSeriesPoints{tsdb.NewTimePoint(null.FloatFrom(float64(data.Value)), float64(data.Timestamp))},
+		}}
+	case *model.String:
+		queryRes.Tables = append(queryRes.Tables, &tsdb.Table{
+			Columns: []tsdb.TableColumn{{Text: "Time"}, {Text: "Value"}},
+			Rows:    []tsdb.RowValues{{float64(data.Timestamp), data.Value}},
+		})
+	default:
 		return queryRes, fmt.Errorf("Unsupported result format: %s", value.Type().String())
 	}
 
+	return queryRes, nil
+}
+
+func matrixToSeries(data model.Matrix, query *PrometheusQuery) tsdb.TimeSeriesSlice {
+	result := make(tsdb.TimeSeriesSlice, 0, len(data))
 	for _, v := range data {
 		series := tsdb.TimeSeries{
 			Name:   formatLegend(v.Metric, query),
-			Tags:   make(map[string]string, len(v.Metric)),
+			Tags:   metricTags(v.Metric),
 			Points: make([]tsdb.TimePoint, 0, len(v.Values)),
 		}
 
-		for k, v := range v.Metric {
-			series.Tags[string(k)] = string(v)
+		for _, k := range v.Values {
+			series.Points = append(series.Points, tsdb.NewTimePoint(null.FloatFrom(float64(k.Value)), float64(k.Timestamp.Unix()*1000)))
 		}
 
+		result = append(result, &series)
+	}
+
+	return result
+}
+
+// vectorToSeries returns a series with a single point for every sample, so
+// the results of instant queries can be evaluated by alert conditions.
+func vectorToSeries(data model.Vector, query *PrometheusQuery) tsdb.TimeSeriesSlice {
+	result := make(tsdb.TimeSeriesSlice, 0, len(data))
+	for _, v := range data {
+		result = append(result, &tsdb.TimeSeries{
+			Name:   formatLegend(v.Metric, query),
+			Tags:   metricTags(v.Metric),
+			Points: tsdb.TimeSeriesPoints{tsdb.NewTimePoint(null.FloatFrom(float64(v.Value)), float64(v.Timestamp))},
+		})
+	}
+
+	return result
+}
+
+// matrixToTable returns a table with a row for every point of every series.
+func matrixToTable(data model.Matrix) *tsdb.Table {
+	metrics := make([]model.Metric, 0, len(data))
+	for _, v := range data {
+		metrics = append(metrics, v.Metric)
+	}
+
+	labels := labelNames(metrics)
+	table := newLabelsTable(labels)
+	for _, v := range data {
 		for _, k := range v.Values {
-			series.Points = append(series.Points, tsdb.NewTimePoint(null.FloatFrom(float64(k.Value)), float64(k.Timestamp.Unix()*1000)))
+			table.Rows = append(table.Rows, labelsRow(float64(k.Timestamp), v.Metric, labels, float64(k.Value)))
 		}
+	}
 
-		queryRes.Series = append(queryRes.Series, &series)
+	return table
+}
+
+// vectorToTable returns a table with a row for every sample.
+func vectorToTable(data model.Vector) *tsdb.Table {
+	metrics := make([]model.Metric, 0, len(data))
+	for _, v := range data {
+		metrics = append(metrics, v.Metric)
 	}
 
-	return queryRes, nil
+	labels := labelNames(metrics)
+	table := newLabelsTable(labels)
+	for _, v := range data {
+		table.Rows = append(table.Rows, labelsRow(float64(v.Timestamp), v.Metric, labels, float64(v.Value)))
+	}
+
+	return table
+}
+
+// labelNames returns the sorted names of the labels of all metrics.
+func labelNames(metrics []model.Metric) []string {
+	names := []string{}
+	seen := map[string]bool{}
+	for _, metric := range metrics {
+		for k := range metric {
+			if !seen[string(k)] {
+				seen[string(k)] = true
+				names = append(names, string(k))
+			}
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func newLabelsTable(labels []string) *tsdb.Table {
+	table := &tsdb.Table{
+		Columns: make([]tsdb.TableColumn, 0, len(labels)+2),
+		Rows:    make([]tsdb.RowValues, 0),
+	}
+
+	table.Columns = append(table.Columns, tsdb.TableColumn{Text: "Time"})
+	for _, label := range labels {
+		table.Columns = append(table.Columns, tsdb.TableColumn{Text: label})
+	}
+	table.Columns = append(table.Columns, tsdb.TableColumn{Text: "Value"})
+
+	return table
+}
+
+func labelsRow(timestamp float64, metric model.Metric, labels []string, value float64) tsdb.RowValues {
+	row := make(tsdb.RowValues, 0, len(labels)+2)
+	row = append(row, timestamp)
+	for _, label := range labels {
+		row = append(row, string(metric[model.LabelName(label)]))
+	}
+	row = append(row, value)
+
+	return row
+}
+
+func metricTags(metric model.Metric) map[string]string {
+	tags := make(map[string]string, len(metric))
+	for k, v := range metric {
+		tags[string(k)] = string(v)
+	}
+
+	return tags
 }
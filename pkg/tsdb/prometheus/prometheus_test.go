@@ -1,6 +1,10 @@
 package prometheus
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
@@ -8,6 +12,7 @@ import (
 	"github.com/grafana/grafana/pkg/tsdb"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	apiv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	p "github.com/prometheus/common/model"
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -156,5 +161,134 @@ func TestPrometheus(t *testing.T) {
 			})
 		})
 
+		Convey("parsing query model with long time range", func() {
+			jsonModel, _ := simplejson.NewJson([]byte(`{"expr": "go_goroutines", "interval": "1s", "refId": "A"}`))
+			queryContext := &tsdb.TsdbQuery{TimeRange: tsdb.NewTimeRange("1580000000000", "1580086400000")}
+
+			models, err := parseQuery(dsInfo, []*tsdb.Query{{Model: jsonModel}}, queryContext)
+			So(err, ShouldBeNil)
+
+			Convey("should adjust the step to the maximum number of points", func() {
+				So(models[0].RequestedStep, ShouldEqual, time.Minute)
+				So(models[0].Step, ShouldEqual, time.Minute)
+
+				So(adjustStep(time.Second, 24*time.Hour), ShouldEqual, 8*time.Second)
+				So(adjustStep(time.Minute, 24*time.Hour), ShouldEqual, time.Minute)
+			})
+
+			Convey("should report adjusted steps in meta", func() {
+				query := &PrometheusQuery{Expr: "up", Step: 8 * time.Second, RequestedStep: time.Second}
+				meta := queryMeta(query, apiv1.Warnings{"partial response"})
+
+				So(meta.Get("queryType").MustString(), ShouldEqual, "range")
+				So(meta.Get("step").MustFloat64(), ShouldEqual, 8)
+				So(meta.Get("requestedStep").MustFloat64(), ShouldEqual, 1)
+				So(meta.Get("stepAdjusted").MustBool(), ShouldBeTrue)
+				So(meta.Get("warnings").Interface(), ShouldResemble, []string{"partial response"})
+			})
+		})
+
+		Convey("parsing instant query model", func() {
+			queryContext := &tsdb.TsdbQuery{TimeRange: tsdb.NewTimeRange("1h", "now")}
+
+			Convey("with instant flag", func() {
+				jsonModel, _ := simplejson.NewJson([]byte(`{"expr": "up", "instant": true, "format": "table"}`))
+				models, err := parseQuery(dsInfo, []*tsdb.Query{{Model: jsonModel}}, queryContext)
+				So(err, ShouldBeNil)
+				So(models[0].Instant, ShouldBeTrue)
+				So(models[0].Format, ShouldEqual, "table")
+			})
+
+			Convey("with instant query type", func() {
+				jsonModel, _ := simplejson.NewJson([]byte(`{"expr": "up"}`))
+				models, err := parseQuery(dsInfo, []*tsdb.Query{{Model: jsonModel, QueryType: "instant"}}, queryContext)
+				So(err, ShouldBeNil)
+				So(models[0].Instant, ShouldBeTrue)
+				So(models[0].Format, ShouldEqual, "time_series")
+			})
+		})
+
+		Convey("parsing instant query responses", func() {
+			vector := p.Vector{
+				{Metric: p.Metric{"__name__": "up", "job": "grafana"}, Value: 1, Timestamp: 1580000000000},
+				{Metric: p.Metric{"__name__": "up", "job": "prometheus", "instance": "localhost:9090"}, Value: 0, Timestamp: 1580000000000},
+			}
+
+			Convey("should convert vectors to tables", func() {
+				res, err := parseResponse(vector, &PrometheusQuery{Instant: true, Format: "table"})
+				So(err, ShouldBeNil)
+				So(res.Series, ShouldBeEmpty)
+				So(res.Tables, ShouldHaveLength, 1)
+				So(res.Tables[0].Columns, ShouldResemble, []tsdb.TableColumn{{Text: "Time"}, {Text: "__name__"}, {Text: "instance"}, {Text: "job"}, {Text: "Value"}})
+				So(res.Tables[0].Rows, ShouldResemble, []tsdb.RowValues{
+					{float64(1580000000000), "up", "", "grafana", float64(1)},
+					{float64(1580000000000), "up", "localhost:9090", "prometheus", float64(0)},
+				})
+			})
+
+			Convey("should convert vectors to series with one point", func() {
+				res, err := parseResponse(vector, &PrometheusQuery{Instant: true, Format: "time_series", LegendFormat: "{{job}}"})
+				So(err, ShouldBeNil)
+				So(res.Series, ShouldHaveLength, 2)
+				So(res.Series[0].Name, ShouldEqual, "grafana")
+				So(res.Series[0].Tags["job"], ShouldEqual, "grafana")
+				So(res.Series[0].Points, ShouldHaveLength, 1)
+				So(res.Series[0].Points[0][0].Float64, ShouldEqual, 1)
+				So(res.Series[0].Points[0][1].Float64, ShouldEqual, 1580000000000)
+			})
+
+			Convey("should convert scalars", func() {
+				scalar := &p.Scalar{Value: 42, Timestamp: 1580000000000}
+
+				res, err := parseResponse(scalar, &PrometheusQuery{Instant: true, Format: "table"})
+				So(err, ShouldBeNil)
+				So(res.Tables[0].Rows, ShouldResemble, []tsdb.RowValues{{float64(1580000000000), float64(42)}})
+
+				res, err = parseResponse(scalar, &PrometheusQuery{Instant: true, Format: "time_series"})
+				So(err, ShouldBeNil)
+				So(res.Series[0].Points[0][0].Float64, ShouldEqual, 42)
+			})
+
+			Convey("should convert strings to tables", func() {
+				res, err := parseResponse(&p.String{Value: "hello", Timestamp: 1580000000000}, &PrometheusQuery{Instant: true, Format: "time_series"})
+				So(err, ShouldBeNil)
+				So(res.Tables[0].Rows, ShouldResemble, []tsdb.RowValues{{float64(1580000000000), "hello"}})
+			})
+		})
+
+		Convey("executing instant queries", func() {
+			var values url.Values
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				values = r.Form
+				values.Set("path", r.URL.Path)
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{
+					"status": "success",
+					"warnings": ["results truncated"],
+					"data": {"resultType": "vector", "result": [{"metric": {"job": "grafana"}, "value": [1580000000, "1"]}]}
+				}`))
+			}))
+			defer server.Close()
+
+			executor := &PrometheusExecutor{Transport: http.DefaultTransport}
+			jsonModel, _ := simplejson.NewJson([]byte(`{"expr": "up", "instant": true, "format": "table"}`))
+			res, err := executor.Query(context.Background(), &models.DataSource{Url: server.URL, JsonData: simplejson.New()}, &tsdb.TsdbQuery{
+				TimeRange: tsdb.NewTimeRange("1579996400000", "1580000000000"),
+				Queries:   []*tsdb.Query{{RefId: "A", Model: jsonModel}},
+			})
+			So(err, ShouldBeNil)
+
+			So(values.Get("path"), ShouldEqual, "/api/v1/query")
+			So(values.Get("query"), ShouldEqual, "up")
+			So(values.Get("time"), ShouldEqual, "1580000000")
+
+			result := res.Results["A"]
+			So(result.RefId, ShouldEqual, "A")
+			So(result.Tables[0].Rows, ShouldResemble, []tsdb.RowValues{{float64(1580000000000), "grafana", float64(1)}})
+			So(result.Meta.Get("queryType").MustString(), ShouldEqual, "instant")
+			So(result.Meta.Get("warnings").Interface(), ShouldResemble, []string{"results truncated"})
+		})
+
 	})
 }
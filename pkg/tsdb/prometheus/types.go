@@ -3,10 +3,13 @@ package prometheus
 import "time"
 
 type PrometheusQuery struct {
-	Expr         string
-	Step         time.Duration
-	LegendFormat string
-	Start        time.Time
-	End          time.Time
-	RefId        string
+	Expr          string
+	Step          time.Duration
+	RequestedStep time.Duration
+	LegendFormat  string
+	Start         time.Time
+	End           time.Time
+	RefId         string
+	Instant       bool
+	Format        string
 }
@@ -0,0 +1,235 @@
+package tsdb
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/metrics"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+const (
+	// QueryCacheStatusHit is the cache status of results read from the query cache.
+	QueryCacheStatusHit = "hit"
+	// QueryCacheStatusMiss is the cache status of results queried from the data source and added to the query cache.
+	QueryCacheStatusMiss = "miss"
+
+	defaultQueryCacheTTL           = time.Minute
+	defaultQueryCacheHistoricalTTL = 24 * time.Hour
+
+	// time ranges ending before this age only contain data that
+	// is not expected to change and are cached longer.
+	historicalDataAge = time.Hour
+)
+
+// QueryCache stores the responses of data sources with query caching enabled.
+// It is implemented by the remote cache, which can be the database, redis or memcached.
+type QueryCache interface {
+	Get(key string) (interface{}, error)
+	Set(key string, value interface{}, expire time.Duration) error
+}
+
+var (
+	queryCache       QueryCache
+	queryCacheLogger = log.New("tsdb.querycache")
+)
+
+// SetQueryCache sets the storage of cached query results.
+// Query results are not cached until it is set.
+func SetQueryCache(cache QueryCache) {
+	queryCache = cache
+}
+
+// queryCacheSettings holds the query cache options of a data source,
+// read from jsonData `queryCacheEnabled`, `queryCacheTTL` and `queryCacheHistoricalTTL`.
+type queryCacheSettings struct {
+	TTL           time.Duration
+	HistoricalTTL time.Duration
+}
+
+// getQueryCacheSettings returns the query cache settings of the data source,
+// or false if its queries must not be cached.
+func getQueryCacheSettings(dsInfo *models.DataSource, req *TsdbQuery) (*queryCacheSettings, bool) {
+	if queryCache == nil || dsInfo.JsonData == nil || req.TimeRange == nil || req.Debug {
+		return nil, false
+	}
+
+	if !dsInfo.JsonData.Get("queryCacheEnabled").MustBool() {
+		return nil, false
+	}
+
+	// the results depend on the identity of the user
+	if dsInfo.JsonData.Get("oauthPassThru").MustBool() {
+		return nil, false
+	}
+
+	settings := &queryCacheSettings{
+		TTL:           parseQueryCacheTTL(dsInfo.JsonData, "queryCacheTTL", defaultQueryCacheTTL),
+		HistoricalTTL: parseQueryCacheTTL(dsInfo.JsonData, "queryCacheHistoricalTTL", defaultQueryCacheHistoricalTTL),
+	}
+
+	return settings, true
+}
+
+func parseQueryCacheTTL(jsonData *simplejson.Json, key string, defaultTTL time.Duration) time.Duration {
+	text := jsonData.Get(key).MustString()
+	if text == "" {
+		return defaultTTL
+	}
+
+	ttl, err := time.ParseDuration(text)
+	if err != nil || ttl <= 0 {
+		queryCacheLogger.Warn("Invalid query cache ttl, using default", "key", key, "value", text)
+		return defaultTTL
+	}
+
+	return ttl
+}
+
+// alignTimeRange rounds the time range down to a multiple of the ttl so that requests
+// made within the same ttl share their cache entry. It is only used for the cache key,
+// data sources are queried with the time range of the request.
+func alignTimeRange(timeRange *TimeRange, ttl time.Duration) *TimeRange {
+	step := ttl.Nanoseconds()
+	from := timeRange.MustGetFrom().UnixNano()
+	to := timeRange.MustGetTo().UnixNano()
+
+	from -= from % step
+	to -= to % step
+
+	return NewTimeRange(
+		strconv.FormatInt(from/int64(time.Millisecond), 10),
+		strconv.FormatInt(to/int64(time.Millisecond), 10),
+	)
+}
+
+type queryCacheKeyQuery struct {
+	RefId         string           `json:"refId"`
+	DatasourceId  int64            `json:"datasourceId"`
+	Model         *simplejson.Json `json:"model"`
+	MaxDataPoints int64            `json:"maxDataPoints"`
+	IntervalMs    int64            `json:"intervalMs"`
+	QueryType     string           `json:"queryType"`
+}
+
+// getQueryCacheKey returns the cache key of a request. Query models are
+// normalized by encoding them as JSON, which sorts object keys.
+func getQueryCacheKey(dsInfo *models.DataSource, req *TsdbQuery) (string, error) {
+	queries := make([]*queryCacheKeyQuery, 0, len(req.Queries))
+	for _, q := range req.Queries {
+		keyQuery := &queryCacheKeyQuery{
+			RefId:         q.RefId,
+			MaxDataPoints: q.MaxDataPoints,
+			IntervalMs:    q.IntervalMs,
+			QueryType:     q.QueryType,
+		}
+
+		if q.DataSource != nil {
+			keyQuery.DatasourceId = q.DataSource.Id
+		}
+
+		if q.Model != nil {
+			model, err := q.Model.Map()
+			if err != nil {
+				return "", err
+			}
+
+			// the request id changes on every request of the frontend
+			normalized := make(map[string]interface{}, len(model))
+			for k, v := range model {
+				if k != "requestId" {
+					normalized[k] = v
+				}
+			}
+			keyQuery.Model = simplejson.NewFromAny(normalized)
+		}
+
+		queries = append(queries, keyQuery)
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"datasourceId":      dsInfo.Id,
+		"datasourceVersion": dsInfo.Version,
+		"from":              req.TimeRange.From,
+		"to":                req.TimeRange.To,
+		"queries":           queries,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(data)
+	return "tsdb-query-cache-" + hex.EncodeToString(hash[:]), nil
+}
+
+// queryWithCache returns the response of the request from the query cache,
+// or queries the data source and caches the response if it has no errors.
+func queryWithCache(ctx context.Context, endpoint TsdbQueryEndpoint, dsInfo *models.DataSource, req *TsdbQuery, settings *queryCacheSettings) (*Response, error) {
+	keyReq := *req
+	keyReq.TimeRange = alignTimeRange(req.TimeRange, settings.TTL)
+
+	key, err := getQueryCacheKey(dsInfo, &keyReq)
+	if err != nil {
+		queryCacheLogger.Warn("Failed to compute query cache key", "datasource", dsInfo.Name, "error", err)
+		return endpoint.Query(ctx, dsInfo, req)
+	}
+
+	if cached, err := queryCache.Get(key); err == nil {
+		if data, ok := cached.([]byte); ok {
+			res := &Response{}
+			if err := json.Unmarshal(data, res); err == nil {
+				metrics.MDataSourceQueryCacheHits.WithLabelValues(dsInfo.Type).Inc()
+				setQueryCacheStatus(res, QueryCacheStatusHit)
+				return res, nil
+			}
+		}
+	}
+
+	metrics.MDataSourceQueryCacheMisses.WithLabelValues(dsInfo.Type).Inc()
+
+	res, err := endpoint.Query(ctx, dsInfo, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if isCacheableResponse(res) {
+		ttl := settings.TTL
+		if time.Since(req.TimeRange.MustGetTo()) > historicalDataAge {
+			ttl = settings.HistoricalTTL
+		}
+
+		if data, err := json.Marshal(res); err != nil {
+			queryCacheLogger.Warn("Failed to encode query response", "datasource", dsInfo.Name, "error", err)
+		} else if err := queryCache.Set(key, data, ttl); err != nil {
+			queryCacheLogger.Warn("Failed to cache query response", "datasource", dsInfo.Name, "error", err)
+		}
+	}
+
+	setQueryCacheStatus(res, QueryCacheStatusMiss)
+	return res, nil
+}
+
+func isCacheableResponse(res *Response) bool {
+	for _, result := range res.Results {
+		if result.Error != nil || result.ErrorString != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+func setQueryCacheStatus(res *Response, status string) {
+	for _, result := range res.Results {
+		if result.Meta == nil {
+			result.Meta = simplejson.New()
+		}
+		result.Meta.Set("cacheStatus", status)
+	}
+}
@@ -0,0 +1,164 @@
+package tsdb
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type fakeQueryCache struct {
+	items   map[string]interface{}
+	expires map[string]time.Duration
+}
+
+func newFakeQueryCache() *fakeQueryCache {
+	return &fakeQueryCache{items: map[string]interface{}{}, expires: map[string]time.Duration{}}
+}
+
+func (c *fakeQueryCache) Get(key string) (interface{}, error) {
+	if item, ok := c.items[key]; ok {
+		return item, nil
+	}
+	return nil, errors.New("cache item not found")
+}
+
+func (c *fakeQueryCache) Set(key string, value interface{}, expire time.Duration) error {
+	c.items[key] = value
+	c.expires[key] = expire
+	return nil
+}
+
+func TestQueryCache(t *testing.T) {
+	Convey("When executing requests with query cache", t, func() {
+		cache := newFakeQueryCache()
+		SetQueryCache(cache)
+		defer SetQueryCache(nil)
+
+		calls := 0
+		var queriedTimeRange *TimeRange
+		fakeExecutor := registerFakeExecutor()
+		fakeExecutor.HandleQuery("A", func(req *TsdbQuery) *QueryResult {
+			calls++
+			queriedTimeRange = req.TimeRange
+			return &QueryResult{RefId: "A", Series: TimeSeriesSlice{NewTimeSeries("argh", NewTimeSeriesPointsFromArgs(1, 1000))}}
+		})
+
+		ds := &models.DataSource{Id: 1, Version: 1, Type: "test", JsonData: simplejson.NewFromAny(map[string]interface{}{
+			"queryCacheEnabled": true,
+			"queryCacheTTL":     "5m",
+		})}
+
+		now := time.Now()
+		newRequest := func(to time.Time, target string) *TsdbQuery {
+			return &TsdbQuery{
+				TimeRange: NewTimeRange(strconv.FormatInt(to.Add(-time.Hour).UnixNano()/int64(time.Millisecond), 10), strconv.FormatInt(to.UnixNano()/int64(time.Millisecond), 10)),
+				Queries: []*Query{
+					{RefId: "A", DataSource: ds, Model: simplejson.NewFromAny(map[string]interface{}{"target": target, "requestId": target + strconv.Itoa(calls)})},
+				},
+			}
+		}
+
+		res, err := HandleRequest(context.TODO(), ds, newRequest(now, "a"))
+		So(err, ShouldBeNil)
+		So(calls, ShouldEqual, 1)
+		So(res.Results["A"].Meta.Get("cacheStatus").MustString(), ShouldEqual, QueryCacheStatusMiss)
+		So(cache.items, ShouldHaveLength, 1)
+
+		Convey("Should query the data source with the time range of the request", func() {
+			req := newRequest(now, "b")
+			_, err := HandleRequest(context.TODO(), ds, req)
+			So(err, ShouldBeNil)
+			So(queriedTimeRange.From, ShouldEqual, req.TimeRange.From)
+			So(queriedTimeRange.To, ShouldEqual, req.TimeRange.To)
+		})
+
+		Convey("Should return cached results for the same query", func() {
+			res, err := HandleRequest(context.TODO(), ds, newRequest(now, "a"))
+			So(err, ShouldBeNil)
+			So(calls, ShouldEqual, 1)
+			So(res.Results["A"].Meta.Get("cacheStatus").MustString(), ShouldEqual, QueryCacheStatusHit)
+			So(res.Results["A"].Series[0].Name, ShouldEqual, "argh")
+			So(res.Results["A"].Series[0].Points[0][0].Float64, ShouldEqual, 1)
+		})
+
+		Convey("Should query the data source for other queries", func() {
+			res, err := HandleRequest(context.TODO(), ds, newRequest(now, "b"))
+			So(err, ShouldBeNil)
+			So(calls, ShouldEqual, 2)
+			So(res.Results["A"].Meta.Get("cacheStatus").MustString(), ShouldEqual, QueryCacheStatusMiss)
+		})
+
+		Convey("Should query the data source when the data source is updated", func() {
+			ds.Version = 2
+			_, err := HandleRequest(context.TODO(), ds, newRequest(now, "a"))
+			So(err, ShouldBeNil)
+			So(calls, ShouldEqual, 2)
+		})
+
+		Convey("Should query the data source for debug requests", func() {
+			req := newRequest(now, "a")
+			req.Debug = true
+			res, err := HandleRequest(context.TODO(), ds, req)
+			So(err, ShouldBeNil)
+			So(calls, ShouldEqual, 2)
+			So(res.Results["A"].Meta, ShouldBeNil)
+		})
+
+		Convey("Should cache historical time ranges with the historical ttl", func() {
+			_, err := HandleRequest(context.TODO(), ds, newRequest(now.Add(-48*time.Hour), "a"))
+			So(err, ShouldBeNil)
+			So(cache.expires, ShouldContainKey, mustQueryCacheKey(ds, newRequest(now.Add(-48*time.Hour), "a"), 5*time.Minute))
+			So(cache.expires[mustQueryCacheKey(ds, newRequest(now.Add(-48*time.Hour), "a"), 5*time.Minute)], ShouldEqual, defaultQueryCacheHistoricalTTL)
+		})
+
+		Convey("Should not cache responses with errors", func() {
+			fakeExecutor.HandleQuery("A", func(req *TsdbQuery) *QueryResult {
+				calls++
+				return &QueryResult{RefId: "A", Error: errors.New("failed")}
+			})
+
+			_, err := HandleRequest(context.TODO(), ds, newRequest(now, "c"))
+			So(err, ShouldBeNil)
+			So(cache.items, ShouldHaveLength, 1)
+		})
+	})
+
+	Convey("When executing requests for data sources without query cache", t, func() {
+		cache := newFakeQueryCache()
+		SetQueryCache(cache)
+		defer SetQueryCache(nil)
+
+		fakeExecutor := registerFakeExecutor()
+		fakeExecutor.Return("A", TimeSeriesSlice{&TimeSeries{Name: "argh"}})
+
+		ds := &models.DataSource{Id: 1, Type: "test", JsonData: simplejson.New()}
+		res, err := HandleRequest(context.TODO(), ds, &TsdbQuery{
+			TimeRange: NewTimeRange("1h", "now"),
+			Queries:   []*Query{{RefId: "A", DataSource: ds}},
+		})
+		So(err, ShouldBeNil)
+		So(res.Results["A"].Meta, ShouldBeNil)
+		So(cache.items, ShouldBeEmpty)
+	})
+
+	Convey("Query cache time ranges", t, func() {
+		Convey("Should be aligned to the ttl", func() {
+			timeRange := alignTimeRange(NewTimeRange("1000", "1299999"), time.Minute)
+			So(timeRange.From, ShouldEqual, "0")
+			So(timeRange.To, ShouldEqual, "1260000")
+		})
+	})
+}
+
+func mustQueryCacheKey(ds *models.DataSource, req *TsdbQuery, ttl time.Duration) string {
+	req.TimeRange = alignTimeRange(req.TimeRange, ttl)
+	key, err := getQueryCacheKey(ds, req)
+	So(err, ShouldBeNil)
+	return key
+}
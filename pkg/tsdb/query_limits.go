@@ -0,0 +1,205 @@
+package tsdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/metrics"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+const (
+	defaultQueryQueueTimeout = 30 * time.Second
+
+	// QueryRejectedQueueTimeout is the reason of queries rejected because no query slot was free within the queue timeout.
+	QueryRejectedQueueTimeout = "queue_timeout"
+	// QueryRejectedQueryTimeout is the reason of queries canceled because they took longer than the query timeout.
+	QueryRejectedQueryTimeout = "query_timeout"
+)
+
+var (
+	// ErrTooManyQueries is returned when a data source has reached its maximum number
+	// of concurrent queries and no query finished within the queue timeout.
+	ErrTooManyQueries = errors.New("Too many concurrent queries to the data source, try again later")
+	// ErrQueryTimeout is returned when a query takes longer than the query timeout of its data source.
+	ErrQueryTimeout = errors.New("Data source query timed out")
+
+	queryLimitsLogger = log.New("tsdb.querylimits")
+
+	queryLimiters     = make(map[int64]*queryLimiter)
+	queryLimitersLock sync.Mutex
+)
+
+// QueryLimitSettings holds the query limits of a data source, read from jsonData
+// `maxConcurrentQueries`, `queryQueueTimeout` and `queryTimeout`.
+type QueryLimitSettings struct {
+	// MaxConcurrentQueries is the maximum number of queries executed at the same time, 0 is unlimited.
+	MaxConcurrentQueries int
+	// QueueTimeout is how long queries wait for a free query slot, 0 rejects queries right away.
+	QueueTimeout time.Duration
+	// QueryTimeout is how long queries may take, 0 is unlimited.
+	QueryTimeout time.Duration
+}
+
+// GetQueryLimitSettings returns the query limits of the data source.
+func GetQueryLimitSettings(dsInfo *models.DataSource) *QueryLimitSettings {
+	settings := &QueryLimitSettings{QueueTimeout: defaultQueryQueueTimeout}
+	if dsInfo.JsonData == nil {
+		return settings
+	}
+
+	settings.MaxConcurrentQueries = dsInfo.JsonData.Get("maxConcurrentQueries").MustInt(0)
+	settings.QueueTimeout = parseQueryLimitDuration(dsInfo.JsonData, "queryQueueTimeout", defaultQueryQueueTimeout)
+	settings.QueryTimeout = parseQueryLimitDuration(dsInfo.JsonData, "queryTimeout", 0)
+
+	return settings
+}
+
+func parseQueryLimitDuration(jsonData *simplejson.Json, key string, defaultDuration time.Duration) time.Duration {
+	text := jsonData.Get(key).MustString()
+	if text == "" {
+		return defaultDuration
+	}
+
+	duration, err := time.ParseDuration(text)
+	if err != nil || duration < 0 {
+		queryLimitsLogger.Warn("Invalid query limit duration, using default", "key", key, "value", text)
+		return defaultDuration
+	}
+
+	return duration
+}
+
+// queryLimiter holds the query slots of a data source.
+type queryLimiter struct {
+	version int
+	slots   chan struct{}
+}
+
+func (l *queryLimiter) release() {
+	<-l.slots
+}
+
+// getQueryLimiter returns the limiter of the data source, a new limiter is created when the
+// data source or its limit changes. Queries running on the old limiter release their slots to it.
+func getQueryLimiter(dsInfo *models.DataSource, maxConcurrentQueries int) *queryLimiter {
+	queryLimitersLock.Lock()
+	defer queryLimitersLock.Unlock()
+
+	limiter, ok := queryLimiters[dsInfo.Id]
+	if !ok || limiter.version != dsInfo.Version || cap(limiter.slots) != maxConcurrentQueries {
+		limiter = &queryLimiter{version: dsInfo.Version, slots: make(chan struct{}, maxConcurrentQueries)}
+		queryLimiters[dsInfo.Id] = limiter
+	}
+
+	return limiter
+}
+
+// AcquireQuerySlot waits for a free query slot of the data source, the returned func
+// releases the slot and must be called once the query is done. ErrTooManyQueries is
+// returned if no slot was free within the queue timeout.
+func AcquireQuerySlot(ctx context.Context, dsInfo *models.DataSource) (func(), error) {
+	settings := GetQueryLimitSettings(dsInfo)
+	if settings.MaxConcurrentQueries <= 0 {
+		return func() {}, nil
+	}
+
+	limiter := getQueryLimiter(dsInfo, settings.MaxConcurrentQueries)
+
+	select {
+	case limiter.slots <- struct{}{}:
+		return limiter.release, nil
+	default:
+	}
+
+	if settings.QueueTimeout <= 0 {
+		metrics.MDataSourceQueriesRejected.WithLabelValues(dsInfo.Type, QueryRejectedQueueTimeout).Inc()
+		return nil, ErrTooManyQueries
+	}
+
+	queueDepth := metrics.MDataSourceQueryQueueDepth.WithLabelValues(dsInfo.Type)
+	queueDepth.Inc()
+	defer queueDepth.Dec()
+
+	timer := time.NewTimer(settings.QueueTimeout)
+	defer timer.Stop()
+
+	select {
+	case limiter.slots <- struct{}{}:
+		return limiter.release, nil
+	case <-timer.C:
+		queryLimitsLogger.Warn("Query rejected, too many concurrent queries", "datasource", dsInfo.Name, "maxConcurrentQueries", settings.MaxConcurrentQueries)
+		metrics.MDataSourceQueriesRejected.WithLabelValues(dsInfo.Type, QueryRejectedQueueTimeout).Inc()
+		return nil, ErrTooManyQueries
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// WithQueryTimeout returns a context that is canceled after the query timeout of the data source.
+func WithQueryTimeout(ctx context.Context, dsInfo *models.DataSource) (context.Context, context.CancelFunc) {
+	if timeout := GetQueryLimitSettings(dsInfo).QueryTimeout; timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+
+	return context.WithCancel(ctx)
+}
+
+// limitedQueryEndpoint enforces the query limits of the data source on the queries of an endpoint.
+type limitedQueryEndpoint struct {
+	endpoint TsdbQueryEndpoint
+}
+
+type queryResponse struct {
+	res *Response
+	err error
+}
+
+func (e *limitedQueryEndpoint) Query(ctx context.Context, dsInfo *models.DataSource, req *TsdbQuery) (*Response, error) {
+	release, err := AcquireQuerySlot(ctx, dsInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := GetQueryLimitSettings(dsInfo).QueryTimeout
+	if timeout <= 0 {
+		defer release()
+		return e.endpoint.Query(ctx, dsInfo, req)
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// not every endpoint stops querying when the context is canceled, the query
+	// keeps its slot until it returns so that the data source is not overloaded
+	done := make(chan queryResponse, 1)
+	go func() {
+		defer release()
+		defer func() {
+			if r := recover(); r != nil {
+				queryLimitsLogger.Error("Query panic", "datasource", dsInfo.Name, "error", r)
+				done <- queryResponse{err: fmt.Errorf("query panic: %v", r)}
+			}
+		}()
+
+		res, err := e.endpoint.Query(queryCtx, dsInfo, req)
+		done <- queryResponse{res: res, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.res, r.err
+	case <-queryCtx.Done():
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		metrics.MDataSourceQueriesRejected.WithLabelValues(dsInfo.Type, QueryRejectedQueryTimeout).Inc()
+		return nil, fmt.Errorf("%w after %s", ErrQueryTimeout, timeout)
+	}
+}
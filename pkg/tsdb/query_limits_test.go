@@ -0,0 +1,144 @@
+package tsdb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestQueryLimits(t *testing.T) {
+	Convey("Query limit settings", t, func() {
+		Convey("Should default to no limits", func() {
+			settings := GetQueryLimitSettings(&models.DataSource{JsonData: simplejson.New()})
+			So(settings.MaxConcurrentQueries, ShouldEqual, 0)
+			So(settings.QueueTimeout, ShouldEqual, defaultQueryQueueTimeout)
+			So(settings.QueryTimeout, ShouldEqual, 0)
+		})
+
+		Convey("Should read the limits from json data", func() {
+			settings := GetQueryLimitSettings(&models.DataSource{JsonData: simplejson.NewFromAny(map[string]interface{}{
+				"maxConcurrentQueries": 5,
+				"queryQueueTimeout":    "10s",
+				"queryTimeout":         "1m",
+			})})
+			So(settings.MaxConcurrentQueries, ShouldEqual, 5)
+			So(settings.QueueTimeout, ShouldEqual, 10*time.Second)
+			So(settings.QueryTimeout, ShouldEqual, time.Minute)
+		})
+
+		Convey("Should use the defaults for invalid durations", func() {
+			settings := GetQueryLimitSettings(&models.DataSource{JsonData: simplejson.NewFromAny(map[string]interface{}{
+				"queryQueueTimeout": "abc",
+				"queryTimeout":      "-1s",
+			})})
+			So(settings.QueueTimeout, ShouldEqual, defaultQueryQueueTimeout)
+			So(settings.QueryTimeout, ShouldEqual, 0)
+		})
+	})
+
+	Convey("When executing requests with query limits", t, func() {
+		started := make(chan struct{}, 10)
+		unblock := make(chan struct{})
+		fakeExecutor := registerFakeExecutor()
+		fakeExecutor.HandleQuery("A", func(req *TsdbQuery) *QueryResult {
+			started <- struct{}{}
+			<-unblock
+			return &QueryResult{RefId: "A"}
+		})
+		fakeExecutor.Return("B", TimeSeriesSlice{})
+
+		jsonData := simplejson.NewFromAny(map[string]interface{}{
+			"maxConcurrentQueries": 1,
+			"queryQueueTimeout":    "50ms",
+		})
+		ds := &models.DataSource{Id: 100, Version: 1, Type: "test", JsonData: jsonData}
+
+		newRequest := func(refID string) *TsdbQuery {
+			return &TsdbQuery{Queries: []*Query{{RefId: refID, DataSource: ds}}}
+		}
+
+		runBlockingQuery := func() chan error {
+			errs := make(chan error, 1)
+			go func() {
+				_, err := HandleRequest(context.Background(), ds, newRequest("A"))
+				errs <- err
+			}()
+			<-started
+			return errs
+		}
+
+		Convey("Should reject queries when no query slot is free within the queue timeout", func() {
+			errs := runBlockingQuery()
+
+			_, err := HandleRequest(context.Background(), ds, newRequest("B"))
+			So(err, ShouldEqual, ErrTooManyQueries)
+
+			close(unblock)
+			So(<-errs, ShouldBeNil)
+
+			res, err := HandleRequest(context.Background(), ds, newRequest("B"))
+			So(err, ShouldBeNil)
+			So(res.Results["B"], ShouldNotBeNil)
+		})
+
+		Convey("Should run queued queries once a query slot is free", func() {
+			jsonData.Set("queryQueueTimeout", "5s")
+			errs := runBlockingQuery()
+
+			done := make(chan error, 1)
+			go func() {
+				_, err := HandleRequest(context.Background(), ds, newRequest("B"))
+				done <- err
+			}()
+
+			time.Sleep(10 * time.Millisecond)
+			close(unblock)
+
+			So(<-errs, ShouldBeNil)
+			So(<-done, ShouldBeNil)
+		})
+
+		Convey("Should stop waiting when the request is canceled", func() {
+			jsonData.Set("queryQueueTimeout", "5s")
+			errs := runBlockingQuery()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			_, err := HandleRequest(ctx, ds, newRequest("B"))
+			So(err, ShouldEqual, context.Canceled)
+
+			close(unblock)
+			So(<-errs, ShouldBeNil)
+		})
+
+		Convey("Should return an error when the query timeout is exceeded", func() {
+			jsonData.Set("queryTimeout", "20ms")
+
+			_, err := HandleRequest(context.Background(), ds, newRequest("A"))
+			So(errors.Is(err, ErrQueryTimeout), ShouldBeTrue)
+			So(err.Error(), ShouldEqual, "Data source query timed out after 20ms")
+
+			// the query keeps its slot until it returns
+			_, err = HandleRequest(context.Background(), ds, newRequest("B"))
+			So(err, ShouldEqual, ErrTooManyQueries)
+
+			close(unblock)
+		})
+
+		Convey("Should not limit data sources without max concurrent queries", func() {
+			jsonData.Del("maxConcurrentQueries")
+			errs := runBlockingQuery()
+
+			_, err := HandleRequest(context.Background(), ds, newRequest("B"))
+			So(err, ShouldBeNil)
+
+			close(unblock)
+			So(<-errs, ShouldBeNil)
+		})
+	})
+}
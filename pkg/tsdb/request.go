@@ -14,5 +14,12 @@ func HandleRequest(ctx context.Context, dsInfo *models.DataSource, req *TsdbQuer
 		return nil, err
 	}
 
+	// cached results are returned without waiting for a query slot
+	endpoint = &limitedQueryEndpoint{endpoint: endpoint}
+
+	if settings, ok := getQueryCacheSettings(dsInfo, req); ok {
+		return queryWithCache(ctx, endpoint, dsInfo, req, settings)
+	}
+
 	return endpoint.Query(ctx, dsInfo, req)
 }
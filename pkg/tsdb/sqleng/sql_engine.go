@@ -64,6 +64,8 @@ type sqlQueryEndpoint struct {
 	engine                 *xorm.Engine
 	timeColumnNames        []string
 	metricColumnTypes      []string
+	rowLimit               int64
+	byteLimit              int64
 	log                    log.Logger
 }
 
@@ -80,9 +82,15 @@ var NewSqlQueryEndpoint = func(config *SqlQueryEndpointConfiguration, queryResul
 		queryResultTransformer: queryResultTransformer,
 		macroEngine:            macroEngine,
 		timeColumnNames:        []string{"time"},
+		rowLimit:               config.Datasource.JsonData.Get("maxRows").MustInt64(defaultRowLimit),
+		byteLimit:              config.Datasource.JsonData.Get("maxBytes").MustInt64(0),
 		log:                    log,
 	}
 
+	if queryEndpoint.rowLimit <= 0 {
+		queryEndpoint.rowLimit = defaultRowLimit
+	}
+
 	if len(config.TimeColumnNames) > 0 {
 		queryEndpoint.timeColumnNames = config.TimeColumnNames
 	}
@@ -120,7 +128,78 @@ var NewSqlQueryEndpoint = func(config *SqlQueryEndpointConfiguration, queryResul
 	return &queryEndpoint, nil
 }
 
-const rowLimit = 1000000
+// defaultRowLimit is the maximum number of rows read from the result set of a query
+// when the data source has no maxRows, results with more rows are truncated.
+const defaultRowLimit = 1000000
+
+// resultLimiter stops reading the rows of a query result set once the
+// row or byte limit of the data source is reached.
+type resultLimiter struct {
+	rowLimit  int64
+	byteLimit int64
+	rows      int64
+	bytes     int64
+	truncated bool
+}
+
+func (e *sqlQueryEndpoint) newResultLimiter() *resultLimiter {
+	return &resultLimiter{rowLimit: e.rowLimit, byteLimit: e.byteLimit}
+}
+
+// add counts a row read from the result set and returns false if it
+// exceeds a limit, in which case the row and the rows after it are dropped.
+func (l *resultLimiter) add(values tsdb.RowValues) bool {
+	size := estimateRowSize(values)
+	if l.rows+1 > l.rowLimit || (l.byteLimit > 0 && l.bytes+size > l.byteLimit) {
+		l.truncated = true
+		return false
+	}
+
+	l.rows++
+	l.bytes += size
+	return true
+}
+
+// setMeta adds a notice to the meta of a truncated query result, which
+// is shown in the header of panels.
+func (l *resultLimiter) setMeta(result *tsdb.QueryResult) {
+	if !l.truncated {
+		return
+	}
+
+	text := fmt.Sprintf("Query result truncated to %d rows, the data source limit is %d rows", l.rows, l.rowLimit)
+	if l.byteLimit > 0 {
+		text += fmt.Sprintf(" and %d bytes", l.byteLimit)
+	}
+
+	result.Meta.Set("truncated", true)
+	result.Meta.Set("notices", []interface{}{
+		map[string]interface{}{"severity": "warning", "text": text},
+	})
+}
+
+// estimateRowSize returns the approximate memory size of the values of a row.
+func estimateRowSize(values tsdb.RowValues) int64 {
+	var size int64
+	for _, v := range values {
+		switch value := v.(type) {
+		case nil:
+		case string:
+			size += int64(len(value))
+		case *string:
+			if value != nil {
+				size += int64(len(*value))
+			}
+		case []byte:
+			size += int64(len(value))
+		case time.Time, *time.Time:
+			size += 24
+		default:
+			size += 8
+		}
+	}
+	return size
+}
 
 // Query is the main function for the SqlQueryEndpoint
 func (e *sqlQueryEndpoint) Query(ctx context.Context, dsInfo *models.DataSource, tsdbQuery *tsdb.TsdbQuery) (*tsdb.Response, error) {
@@ -163,13 +242,18 @@ func (e *sqlQueryEndpoint) Query(ctx context.Context, dsInfo *models.DataSource,
 			defer session.Close()
 			db := session.DB()
 
-			rows, err := db.Query(rawSQL)
+			// the query is cancelled when the request is, for example when a panel is refreshed,
+			// and when the rows of a truncated result set are left unread
+			queryCtx, cancel := context.WithCancel(ctx)
+			rows, err := db.QueryContext(queryCtx, rawSQL)
 			if err != nil {
+				cancel()
 				queryResult.Error = e.queryResultTransformer.TransformQueryError(err)
 				return
 			}
 
 			defer rows.Close()
+			defer cancel()
 
 			format := query.Model.Get("format").MustString("time_series")
 
@@ -248,16 +332,18 @@ func (e *sqlQueryEndpoint) transformToTable(query *tsdb.Query, rows *core.Rows,
 		return err
 	}
 
-	for ; rows.Next(); rowCount++ {
-		if rowCount > rowLimit {
-			return fmt.Errorf("query row limit exceeded, limit %d", rowLimit)
-		}
+	limiter := e.newResultLimiter()
 
+	for ; rows.Next(); rowCount++ {
 		values, err := e.queryResultTransformer.TransformQueryResult(columnTypes, rows)
 		if err != nil {
 			return err
 		}
 
+		if !limiter.add(values) {
+			break
+		}
+
 		// converts column named time and timeend to unix timestamp in milliseconds
 		// to make native mssql datetime types and epoch dates work in
 		// annotation and table queries.
@@ -266,8 +352,13 @@ func (e *sqlQueryEndpoint) transformToTable(query *tsdb.Query, rows *core.Rows,
 		table.Rows = append(table.Rows, values)
 	}
 
+	if err := rows.Err(); err != nil {
+		return e.queryResultTransformer.TransformQueryError(err)
+	}
+
 	result.Tables = append(result.Tables, table)
 	result.Meta.Set("rowCount", rowCount)
+	limiter.setMeta(result)
 	return nil
 }
 
@@ -343,20 +434,22 @@ func (e *sqlQueryEndpoint) transformToTimeSeries(query *tsdb.Query, rows *core.R
 		}
 	}
 
+	limiter := e.newResultLimiter()
+
 	for rows.Next() {
 		var timestamp float64
 		var value null.Float
 		var metric string
 
-		if rowCount > rowLimit {
-			return fmt.Errorf("query row limit exceeded, limit %d", rowLimit)
-		}
-
 		values, err := e.queryResultTransformer.TransformQueryResult(columnTypes, rows)
 		if err != nil {
 			return err
 		}
 
+		if !limiter.add(values) {
+			break
+		}
+
 		// converts column named time to unix timestamp in milliseconds to make
 		// native mysql datetime types and epoch dates work in
 		// annotation and table queries.
@@ -438,6 +531,10 @@ func (e *sqlQueryEndpoint) transformToTimeSeries(query *tsdb.Query, rows *core.R
 		}
 	}
 
+	if err := rows.Err(); err != nil {
+		return e.queryResultTransformer.TransformQueryError(err)
+	}
+
 	for elem := seriesByQueryOrder.Front(); elem != nil; elem = elem.Next() {
 		key := elem.Value.(string)
 		result.Series = append(result.Series, pointsBySeries[key])
@@ -466,6 +563,7 @@ func (e *sqlQueryEndpoint) transformToTimeSeries(query *tsdb.Query, rows *core.R
 	}
 
 	result.Meta.Set("rowCount", rowCount)
+	limiter.setMeta(result)
 	return nil
 }
 
@@ -1,14 +1,22 @@
 package sqleng
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/grafana/grafana/pkg/components/null"
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/tsdb"
+	_ "github.com/mattn/go-sqlite3"
+	"xorm.io/core"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -334,3 +342,129 @@ func TestSqlEngine(t *testing.T) {
 		})
 	})
 }
+
+type testQueryResultTransformer struct{}
+
+func (t *testQueryResultTransformer) TransformQueryResult(columnTypes []*sql.ColumnType, rows *core.Rows) (tsdb.RowValues, error) {
+	values := make([]interface{}, len(columnTypes))
+	pointers := make([]interface{}, len(columnTypes))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	if err := rows.Scan(pointers...); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+func (t *testQueryResultTransformer) TransformQueryError(err error) error {
+	return err
+}
+
+type testMacroEngine struct{}
+
+func (m *testMacroEngine) Interpolate(query *tsdb.Query, timeRange *tsdb.TimeRange, sql string) (string, error) {
+	return sql, nil
+}
+
+func TestSqlEngineQueryLimits(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sqleng")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	Convey("Given a data source with query limits", t, func() {
+		connectionString := filepath.Join(dir, "limits.db")
+		jsonData := simplejson.New()
+		ds := &models.DataSource{Id: 1000, Version: 1, JsonData: jsonData}
+
+		newEndpoint := func() tsdb.TsdbQueryEndpoint {
+			endpoint, err := NewSqlQueryEndpoint(&SqlQueryEndpointConfiguration{
+				DriverName:       "sqlite3",
+				Datasource:       ds,
+				ConnectionString: connectionString,
+			}, &testQueryResultTransformer{}, &testMacroEngine{}, log.New("test"))
+			So(err, ShouldBeNil)
+			return endpoint
+		}
+
+		engine := newEndpoint().(*sqlQueryEndpoint).engine
+		_, err := engine.Exec("DROP TABLE IF EXISTS metric")
+		So(err, ShouldBeNil)
+		_, err = engine.Exec("CREATE TABLE metric (time INTEGER, value REAL, name TEXT)")
+		So(err, ShouldBeNil)
+		for i := 0; i < 10; i++ {
+			_, err := engine.Exec("INSERT INTO metric VALUES (?, ?, ?)", 1000*(i+1), float64(i), fmt.Sprintf("name-%d", i))
+			So(err, ShouldBeNil)
+		}
+
+		timeRange := tsdb.NewTimeRange("1000", "10000")
+		newQuery := func(format, rawSQL string) *tsdb.TsdbQuery {
+			return &tsdb.TsdbQuery{
+				TimeRange: timeRange,
+				Queries: []*tsdb.Query{
+					{
+						RefId:      "A",
+						DataSource: ds,
+						Model: simplejson.NewFromAny(map[string]interface{}{
+							"rawSql": rawSQL,
+							"format": format,
+						}),
+					},
+				},
+			}
+		}
+
+		Convey("table results without limits should not be truncated", func() {
+			res, err := newEndpoint().Query(context.Background(), ds, newQuery("table", "SELECT time, value, name FROM metric ORDER BY time"))
+			So(err, ShouldBeNil)
+
+			queryRes := res.Results["A"]
+			So(queryRes.Error, ShouldBeNil)
+			So(queryRes.Tables[0].Rows, ShouldHaveLength, 10)
+			So(queryRes.Meta.Get("truncated").MustBool(), ShouldBeFalse)
+		})
+
+		Convey("table results should be truncated to the max rows", func() {
+			jsonData.Set("maxRows", 4)
+
+			res, err := newEndpoint().Query(context.Background(), ds, newQuery("table", "SELECT time, value, name FROM metric ORDER BY time"))
+			So(err, ShouldBeNil)
+
+			queryRes := res.Results["A"]
+			So(queryRes.Error, ShouldBeNil)
+			So(queryRes.Tables[0].Rows, ShouldHaveLength, 4)
+			So(queryRes.Meta.Get("rowCount").MustInt(), ShouldEqual, 4)
+			So(queryRes.Meta.Get("truncated").MustBool(), ShouldBeTrue)
+			notice := queryRes.Meta.Get("notices").GetIndex(0)
+			So(notice.Get("severity").MustString(), ShouldEqual, "warning")
+			So(notice.Get("text").MustString(), ShouldEqual, "Query result truncated to 4 rows, the data source limit is 4 rows")
+		})
+
+		Convey("time series results should be truncated to the max bytes", func() {
+			// a row is 8 bytes for time and value
+			jsonData.Set("maxBytes", 50)
+
+			res, err := newEndpoint().Query(context.Background(), ds, newQuery("time_series", "SELECT time, value FROM metric ORDER BY time"))
+			So(err, ShouldBeNil)
+
+			queryRes := res.Results["A"]
+			So(queryRes.Error, ShouldBeNil)
+			So(queryRes.Series, ShouldHaveLength, 1)
+			So(queryRes.Series[0].Points, ShouldHaveLength, 3)
+			So(queryRes.Meta.Get("truncated").MustBool(), ShouldBeTrue)
+		})
+
+		Convey("queries should be cancelled with the request", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			res, err := newEndpoint().Query(ctx, ds, newQuery("table", "SELECT time, value, name FROM metric ORDER BY time"))
+			So(err, ShouldBeNil)
+			So(res.Results["A"].Error, ShouldEqual, context.Canceled)
+		})
+	})
+}
@@ -4,7 +4,6 @@ import { connect } from 'react-redux';
 import { NavModel } from '@grafana/data';
 import { getNavModel } from 'app/core/selectors/navModel';
 import { getRouteParamsId } from 'app/core/selectors/location';
-import config from 'app/core/config';
 import Page from 'app/core/components/Page/Page';
 import { UserProfile } from './UserProfile';
 import { UserPermissions } from './UserPermissions';
@@ -141,7 +140,7 @@ export class UserAdminPage extends PureComponent<Props, State> {
                 onUserEnable={this.onUserEnable}
                 onPasswordChange={this.onPasswordChange}
               />
-              {isLDAPUser && config.licenseInfo.hasLicense && ldapSyncInfo && (
+              {isLDAPUser && ldapSyncInfo && (
                 <UserLdapSyncInfo ldapSyncInfo={ldapSyncInfo} user={user} onUserSync={this.onUserSync} />
               )}
               <UserPermissions isGrafanaAdmin={user.isGrafanaAdmin} onGrafanaAdminChange={this.onGrafanaAdminChange} />
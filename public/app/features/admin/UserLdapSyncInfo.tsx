@@ -59,7 +59,7 @@ export class UserLdapSyncInfo extends PureComponent<Props, State> {
                     <>
                       <td>Last synchronisation</td>
                       <td>{prevSyncTime}</td>
-                      <td>Successful</td>
+                      <td>{ldapSyncInfo.prevSync.error ? 'Failed' : 'Successful'}</td>
                     </>
                   ) : (
                     <td colSpan={3}>Last synchronisation</td>
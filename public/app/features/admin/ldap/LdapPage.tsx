@@ -5,19 +5,12 @@ import { NavModel } from '@grafana/data';
 import { Alert, LegacyForms } from '@grafana/ui';
 const { FormField } = LegacyForms;
 import { getNavModel } from 'app/core/selectors/navModel';
-import config from 'app/core/config';
 import Page from 'app/core/components/Page/Page';
 import { LdapConnectionStatus } from './LdapConnectionStatus';
 import { LdapSyncInfo } from './LdapSyncInfo';
 import { LdapUserInfo } from './LdapUserInfo';
 import { AppNotificationSeverity, LdapError, LdapUser, StoreState, SyncInfo, LdapConnectionInfo } from 'app/types';
-import {
-  loadLdapState,
-  loadLdapSyncStatus,
-  loadUserMapping,
-  clearUserError,
-  clearUserMappingInfo,
-} from '../state/actions';
+import { loadLdapState, loadUserMapping, clearUserError, clearUserMappingInfo } from '../state/actions';
 
 interface Props {
   navModel: NavModel;
@@ -29,7 +22,6 @@ interface Props {
   username?: string;
 
   loadLdapState: typeof loadLdapState;
-  loadLdapSyncStatus: typeof loadLdapSyncStatus;
   loadUserMapping: typeof loadUserMapping;
   clearUserError: typeof clearUserError;
   clearUserMappingInfo: typeof clearUserMappingInfo;
@@ -55,8 +47,8 @@ export class LdapPage extends PureComponent<Props, State> {
   }
 
   async fetchLDAPStatus() {
-    const { loadLdapState, loadLdapSyncStatus } = this.props;
-    return Promise.all([loadLdapState(), loadLdapSyncStatus()]);
+    const { loadLdapState } = this.props;
+    return loadLdapState();
   }
 
   async fetchUserMapping(username: string) {
@@ -92,7 +84,7 @@ export class LdapPage extends PureComponent<Props, State> {
 
             <LdapConnectionStatus ldapConnectionInfo={ldapConnectionInfo} />
 
-            {config.licenseInfo.hasLicense && ldapSyncInfo && <LdapSyncInfo ldapSyncInfo={ldapSyncInfo} />}
+            {ldapSyncInfo && <LdapSyncInfo ldapSyncInfo={ldapSyncInfo} />}
 
             <h3 className="page-heading">Test user mapping</h3>
             <div className="gf-form-group">
@@ -141,7 +133,6 @@ const mapStateToProps = (state: StoreState) => ({
 
 const mapDispatchToProps = {
   loadLdapState,
-  loadLdapSyncStatus,
   loadUserMapping,
   clearUserError,
   clearUserMappingInfo,
@@ -26,7 +26,7 @@ export class LdapSyncInfo extends PureComponent<Props, State> {
   render() {
     const { ldapSyncInfo } = this.props;
     const { isSyncing } = this.state;
-    const nextSyncTime = dateTimeFormat(ldapSyncInfo.nextSync, { format });
+    const nextSyncTime = ldapSyncInfo.nextSync ? dateTimeFormat(ldapSyncInfo.nextSync, { format }) : '';
     const prevSyncSuccessful = ldapSyncInfo && ldapSyncInfo.prevSync;
     const prevSyncTime = prevSyncSuccessful ? dateTimeFormat(ldapSyncInfo.prevSync!.started, { format }) : '';
 
@@ -59,8 +59,10 @@ export class LdapSyncInfo extends PureComponent<Props, State> {
                   <td>Last synchronisation</td>
                   {prevSyncSuccessful && (
                     <>
-                      <td>{prevSyncTime}</td>
-                      <td>Successful</td>
+                      <td>
+                        {prevSyncTime} on {ldapSyncInfo.prevSync!.instance}
+                      </td>
+                      <td>{ldapSyncInfo.prevSync!.error ? `Failed: ${ldapSyncInfo.prevSync!.error}` : 'Successful'}</td>
                     </>
                   )}
                 </tr>
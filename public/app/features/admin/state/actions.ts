@@ -32,7 +32,7 @@ export function loadAdminUserPage(userId: number): ThunkResult<void> {
       await dispatch(loadUserProfile(userId));
       await dispatch(loadUserOrgs(userId));
       await dispatch(loadUserSessions(userId));
-      if (config.ldapEnabled && config.licenseInfo.hasLicense) {
+      if (config.ldapEnabled) {
         await dispatch(loadLdapSyncStatus());
       }
       dispatch(userAdminPageLoadedAction(true));
@@ -174,11 +174,8 @@ export function revokeAllSessions(userId: number): ThunkResult<void> {
 
 export function loadLdapSyncStatus(): ThunkResult<void> {
   return async dispatch => {
-    // Available only in enterprise
-    if (config.licenseInfo.hasLicense) {
-      const syncStatus = await getBackendSrv().get(`/api/admin/ldap-sync-status`);
-      dispatch(ldapSyncStatusLoadedAction(syncStatus));
-    }
+    const { sync } = await getBackendSrv().get(`/api/admin/ldap/status`);
+    dispatch(ldapSyncStatusLoadedAction(sync));
   };
 }
 
@@ -194,8 +191,9 @@ export function syncLdapUser(userId: number): ThunkResult<void> {
 export function loadLdapState(): ThunkResult<void> {
   return async dispatch => {
     try {
-      const connectionInfo = await getBackendSrv().get(`/api/admin/ldap/status`);
-      dispatch(ldapConnectionInfoLoadedAction(connectionInfo));
+      const { servers, sync } = await getBackendSrv().get(`/api/admin/ldap/status`);
+      dispatch(ldapConnectionInfoLoadedAction(servers));
+      dispatch(ldapSyncStatusLoadedAction(sync));
     } catch (error) {
       error.isHandled = true;
       const ldapError = {
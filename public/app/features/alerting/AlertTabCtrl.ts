@@ -451,6 +451,10 @@ export class AlertTabCtrl {
       }
       case 'no_value': {
         evaluator.params = [];
+        break;
+      }
+      case 'anomaly': {
+        evaluator.params = [evaluator.params[0] || 3, '1d', 4];
       }
     }
 
@@ -132,6 +132,11 @@ export class AlertNotificationEditCtrl {
     return `notifier-options-${type}`;
   }
 
+  supportsGrouping() {
+    const notifier: any = _.find(this.notifiers, { type: this.model.type });
+    return notifier && notifier.supportsGrouping;
+  }
+
   typeChanged() {
     this.model.settings = _.defaults({}, this.defaults.settings);
     this.notifierTemplateId = this.getNotifierTemplateId(this.model.type);
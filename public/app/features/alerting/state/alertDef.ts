@@ -31,6 +31,7 @@ const evalFunctions = [
   { text: 'IS OUTSIDE RANGE', value: 'outside_range' },
   { text: 'IS WITHIN RANGE', value: 'within_range' },
   { text: 'HAS NO VALUE', value: 'no_value' },
+  { text: 'IS ANOMALOUS', value: 'anomaly' },
 ];
 
 const evalOperators = [
@@ -51,6 +52,14 @@ const reducerTypes = [
   { text: 'percent_diff()', value: 'percent_diff' },
   { text: 'percent_diff_abs()', value: 'percent_diff_abs' },
   { text: 'count_non_null()', value: 'count_non_null' },
+  { text: 'p50()', value: 'p50' },
+  { text: 'p90()', value: 'p90' },
+  { text: 'p95()', value: 'p95' },
+  { text: 'p99()', value: 'p99' },
+  { text: 'stddev()', value: 'stddev' },
+  { text: 'variance()', value: 'variance' },
+  { text: 'rate()', value: 'rate' },
+  { text: 'increase()', value: 'increase' },
 ];
 
 const noDataModes = [
@@ -78,6 +78,7 @@ describe('Functions', () => {
       name: 'member',
       labels: [],
       permission: TeamPermissionLevel.Member,
+      external: false,
     };
     const { instance } = setup({ member });
     const permission = TeamPermissionLevel.Admin;
@@ -87,7 +87,11 @@ export class TeamMemberRow extends PureComponent<Props> {
         {this.renderPermissions(member)}
         {syncEnabled && this.renderLabels(member.labels)}
         <td className="text-right">
-          <DeleteButton size="sm" disabled={!signedInUserIsTeamAdmin} onConfirm={() => this.onRemoveMember(member)} />
+          <DeleteButton
+            size="sm"
+            disabled={!signedInUserIsTeamAdmin || member.external}
+            onConfirm={() => this.onRemoveMember(member)}
+          />
         </td>
       </tr>
     );
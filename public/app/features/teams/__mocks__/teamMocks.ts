@@ -39,6 +39,7 @@ export const getMockTeamMembers = (amount: number, teamAdminId: number): TeamMem
       name: 'testName',
       login: `testUser-${i}`,
       labels: ['label 1', 'label 2'],
+      external: false,
       permission: i === teamAdminId ? TeamPermissionLevel.Admin : TeamPermissionLevel.Member,
     });
   }
@@ -55,6 +56,7 @@ export const getMockTeamMember = (): TeamMember => {
     name: 'testName',
     login: 'testUser',
     labels: [],
+    external: false,
     permission: TeamPermissionLevel.Member,
   };
 };
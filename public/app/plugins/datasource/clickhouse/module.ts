@@ -0,0 +1,34 @@
+import { ClickHouseDatasource } from './datasource';
+import { ClickHouseQueryCtrl } from './query_ctrl';
+import { ClickHouseConfigCtrl } from './config_ctrl';
+
+const defaultQuery = `SELECT
+    <time_column> AS time,
+    <text_column> AS text,
+    <tags_column> AS tags
+  FROM
+    <table name>
+  WHERE
+    $__timeFilter(<time_column>)
+  ORDER BY
+    <time_column> ASC
+  LIMIT 100`;
+
+class ClickHouseAnnotationsQueryCtrl {
+  static templateUrl = 'partials/annotations.editor.html';
+
+  annotation: any;
+
+  /** @ngInject */
+  constructor() {
+    this.annotation.rawQuery = this.annotation.rawQuery || defaultQuery;
+  }
+}
+
+export {
+  ClickHouseDatasource,
+  ClickHouseDatasource as Datasource,
+  ClickHouseQueryCtrl as QueryCtrl,
+  ClickHouseConfigCtrl as ConfigCtrl,
+  ClickHouseAnnotationsQueryCtrl as AnnotationsQueryCtrl,
+};
@@ -0,0 +1,7 @@
+export interface ClickHouseQueryForInterpolation {
+  alias?: any;
+  format?: any;
+  rawSql?: any;
+  refId?: any;
+  hide?: any;
+}
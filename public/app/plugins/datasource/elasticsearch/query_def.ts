@@ -72,6 +72,20 @@ export const metricAggTypes = [
     supportsMultipleBucketPaths: true,
     minVersion: 2,
   },
+  {
+    text: 'Cumulative Sum',
+    value: 'cumulative_sum',
+    requiresField: false,
+    isPipelineAgg: true,
+    minVersion: 2,
+  },
+  {
+    text: 'Serial Difference',
+    value: 'serial_diff',
+    requiresField: false,
+    isPipelineAgg: true,
+    minVersion: 2,
+  },
   { text: 'Raw Document', value: 'raw_document', requiresField: false },
   { text: 'Logs', value: 'logs', requiresField: false },
 ];
@@ -144,6 +158,8 @@ export const pipelineOptions: any = {
   ],
   derivative: [{ text: 'unit', default: undefined }],
   bucket_script: [],
+  cumulative_sum: [{ text: 'format', default: undefined }],
+  serial_diff: [{ text: 'lag', default: undefined }],
 };
 
 export const movingAvgModelSettings: any = {
@@ -22,11 +22,13 @@ export interface LdapUserSyncInfo {
 }
 
 export interface SyncResult {
+  instance: string;
   started: string;
   elapsed: string;
   UpdatedUserIds: number[];
   MissingUserIds: number[];
   FailedUsers?: FailedUser[];
+  error?: string;
 }
 
 export interface FailedUser {
@@ -18,6 +18,7 @@ export interface TeamMember {
   login: string;
   labels: string[];
   permission: number;
+  external: boolean;
 }
 
 export interface TeamGroup {